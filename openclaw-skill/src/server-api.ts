@@ -50,6 +50,7 @@ async function requestJson(
   const url = `${base}${path}`;
   const apiKey = process.env.EASYMEME_API_KEY?.trim();
   const userId = process.env.EASYMEME_USER_ID?.trim();
+  const agentId = process.env.EASYMEME_AGENT_ID?.trim();
   const method = (init?.method || "GET").toUpperCase();
   const body = typeof init?.body === "string" ? init.body : "";
   const signatureHeaders = signRequest(method, path, body);
@@ -59,6 +60,7 @@ async function requestJson(
       "Content-Type": "application/json",
       ...(apiKey ? { "X-API-Key": apiKey } : {}),
       ...(userId ? { "X-User-Id": userId } : {}),
+      ...(agentId ? { "X-Agent-Id": agentId } : {}),
       ...signatureHeaders,
       ...(init?.headers ?? {})
     }
@@ -128,11 +130,14 @@ function normalizeToken(raw: unknown): PendingToken | null {
 }
 
 function normalizeTokenList(payload: unknown): PendingToken[] {
+  const data = payload && typeof payload === "object" ? (payload as any).data : undefined;
   const list = Array.isArray(payload)
     ? payload
-    : payload && typeof payload === "object" && Array.isArray((payload as any).data)
-      ? (payload as any).data
-      : [];
+    : Array.isArray(data)
+      ? data
+      : data && Array.isArray(data.tokens)
+        ? data.tokens
+        : [];
   const tokens: PendingToken[] = [];
   for (const item of list) {
     const token = normalizeToken(item);
@@ -143,13 +148,18 @@ function normalizeTokenList(payload: unknown): PendingToken[] {
   return tokens;
 }
 
+// Claims (leases) pending tokens so parallel agents do not analyze the same
+// tokens. Submitting an analysis releases the lease.
 export async function fetchPendingTokens(
   limit = 10,
   overrideUrl?: string,
 ): Promise<PendingToken[]> {
   const payload = await requestJson(
-    `/api/tokens/pending?limit=${encodeURIComponent(limit)}`,
-    undefined,
+    `/api/tokens/pending/claim`,
+    {
+      method: "POST",
+      body: JSON.stringify({ limit })
+    },
     overrideUrl,
   );
   return normalizeTokenList(payload);
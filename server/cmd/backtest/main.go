@@ -0,0 +1,146 @@
+// Command backtest replays stored golden dog signals against stored price
+// snapshots with an auto-trade config and prints the report.
+//
+//	go run ./cmd/backtest -from 2026-10-01T00:00:00Z -config config.json
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"easymeme/internal/config"
+	"easymeme/internal/repository"
+	"easymeme/internal/service"
+	"easymeme/pkg/ethereum"
+)
+
+func main() {
+	now := time.Now().UTC()
+	from := flag.String("from", now.Add(-7*24*time.Hour).Format(time.RFC3339), "RFC3339 start of the signal window")
+	to := flag.String("to", now.Format(time.RFC3339), "RFC3339 end of the signal window")
+	configPath := flag.String("config", "", "auto-trade config JSON file")
+	userID := flag.String("user", "", "use this user's stored auto-trade config")
+	slippage := flag.Float64("slippage", 0, "slippage per fill on top of price impact (default 0.01)")
+	gas := flag.Float64("gas", 0, "BNB charged per swap")
+	bnbUSD := flag.Float64("bnb-usd", 0, "BNB price in USD (default: current WBNB/USDT price)")
+	balance := flag.Float64("balance", 0, "starting equity in BNB for drawdown (default 10)")
+	horizon := flag.Int("horizon", 0, "minutes to follow a position without maxHoldMinutes (default 1440)")
+	maxSignals := flag.Int("max-signals", 0, "maximum signals to replay (default 500)")
+	asJSON := flag.Bool("json", false, "print the full report as JSON")
+	flag.Parse()
+
+	cfg, err := config.Load()
+	if err != nil {
+		log.Fatalf("Failed to load config: %v", err)
+	}
+	repo, err := repository.New(cfg.DatabaseURL)
+	if err != nil {
+		log.Fatalf("Failed to connect database: %v", err)
+	}
+	ctx := context.Background()
+	// Phases and time decay come from the active scoring model.
+	if err := service.NewScoringModels(repo).Start(ctx); err != nil {
+		log.Fatalf("Failed to load scoring model: %v", err)
+	}
+
+	params := service.BacktestParams{
+		Slippage:          *slippage,
+		GasBNB:            *gas,
+		BNBPriceUSD:       *bnbUSD,
+		InitialBalanceBNB: *balance,
+		HorizonMinutes:    *horizon,
+		MaxSignals:        *maxSignals,
+	}
+	if params.From, err = time.Parse(time.RFC3339, *from); err != nil {
+		log.Fatalf("Invalid -from: %v", err)
+	}
+	if params.To, err = time.Parse(time.RFC3339, *to); err != nil {
+		log.Fatalf("Invalid -to: %v", err)
+	}
+	switch {
+	case *configPath != "":
+		raw, err := os.ReadFile(*configPath)
+		if err != nil {
+			log.Fatalf("Failed to read config: %v", err)
+		}
+		if err := json.Unmarshal(raw, &params.Config); err != nil {
+			log.Fatalf("Invalid config: %v", err)
+		}
+	case *userID != "":
+		if params.Config, err = service.LoadAutoTradeConfig(ctx, repo, *userID); err != nil {
+			log.Fatalf("Failed to load config for %s: %v", *userID, err)
+		}
+	default:
+		log.Fatal("-config or -user is required")
+	}
+	if params.BNBPriceUSD <= 0 {
+		client, err := ethereum.NewClient(cfg.BscRpcHTTP, "")
+		if err != nil {
+			log.Fatalf("Failed to connect BSC, pass -bnb-usd: %v", err)
+		}
+		price, err := service.NewValuator(client, repo).BNBPriceUSD(ctx)
+		client.Close()
+		if err != nil {
+			log.Fatalf("Failed to read BNB price, pass -bnb-usd: %v", err)
+		}
+		params.BNBPriceUSD = price.InexactFloat64()
+	}
+
+	report, err := service.NewBacktester(repo).Run(ctx, params)
+	if err != nil {
+		log.Fatalf("Backtest failed: %v", err)
+	}
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(report); err != nil {
+			log.Fatalf("Failed to write report: %v", err)
+		}
+		return
+	}
+	printReport(report)
+}
+
+func printReport(report *service.BacktestReport) {
+	s := report.Summary
+	fmt.Printf("Backtest %s .. %s at %.2f USD/BNB, scoring model v%d\n\n", report.Params.From.Format(time.RFC3339), report.Params.To.Format(time.RFC3339), report.Params.BNBPriceUSD, report.ScoringVersion)
+	fmt.Printf("Signals %d, trades %d, skipped %d\n", s.Signals, s.Trades, s.Skipped)
+	fmt.Printf("Win rate %.1f%% (%d wins, %d losses)\n", s.WinRate*100, s.Wins, s.Losses)
+	fmt.Printf("P&L %.4f BNB on %.4f BNB deployed\n", s.TotalPnLBNB, s.TotalCostBNB)
+	fmt.Printf("Return avg %.2f%%, median %.2f%%, best %.2f%%, worst %.2f%%\n", s.AvgReturn*100, s.MedianReturn*100, s.BestReturn*100, s.WorstReturn*100)
+	fmt.Printf("Max drawdown %.4f BNB (%.2f%%)\n\n", s.MaxDrawdownBNB, s.MaxDrawdown*100)
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "RETURN\tTRADES")
+	for _, b := range report.Distribution {
+		fmt.Fprintf(w, "%s\t%d\n", b.Label, b.Count)
+	}
+	w.Flush()
+	fmt.Println()
+
+	w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ENTRY\tTOKEN\tSCORE\tCOST BNB\tP&L BNB\tRETURN\tEXIT")
+	for _, t := range report.Trades {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%.4f\t%.4f\t%.2f%%\t%s\n", t.EntryAt.Format(time.RFC3339), t.TokenSymbol, t.GoldenDogScore, t.CostBNB, t.PnLBNB, t.Return*100, t.ExitReason)
+		for _, e := range t.Events {
+			fmt.Fprintf(w, "\t  %s %s\t\t%.4f\t\t\t%s\n", e.At.Format("15:04:05"), e.Action, e.AmountBNB, e.Reason)
+		}
+	}
+	w.Flush()
+
+	if len(report.Skipped) > 0 {
+		fmt.Println()
+		w = tabwriter.NewWriter(os.Stdout, 0, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "SIGNAL\tTOKEN\tSKIPPED")
+		for _, sk := range report.Skipped {
+			fmt.Fprintf(w, "%s\t%s\t%s\n", sk.SignalAt.Format(time.RFC3339), sk.TokenSymbol, sk.Reason)
+		}
+		w.Flush()
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	_ "easymeme/docs"
 	"easymeme/internal/config"
@@ -47,21 +48,61 @@ func main() {
 	wsHub := handler.NewWebSocketHub()
 	go wsHub.Run()
 
-	scanner := service.NewScanner(ethClient, repo, wsHub, cfg.BscScanAPIKey)
-
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	scoring := service.NewScoringModels(repo)
+	if err := scoring.Start(ctx); err != nil {
+		log.Fatalf("Failed to start scoring models: %v", err)
+	}
+
+	breaker := service.NewCircuitBreaker(ethClient, repo, wsHub, service.BreakerConfig{
+		MaxConsecutiveFailures: cfg.BreakerMaxFailures,
+		LossRate:               cfg.BreakerLossRate,
+		LossWindow:             cfg.BreakerLossWindow,
+		RPCFailures:            cfg.BreakerRPCFailures,
+		RPCMaxLatency:          time.Duration(cfg.BreakerRPCMaxLatencyMs) * time.Millisecond,
+	})
+	if err := breaker.Start(ctx); err != nil {
+		log.Fatalf("Failed to start circuit breaker: %v", err)
+	}
+
+	valuator := service.NewValuator(ethClient, repo)
+	valuator.Start(ctx)
+
+	walletHandler := handler.NewWalletHandler(repo, ethClient, wsHub, breaker, valuator)
+	autoTrader := service.NewAutoTrader(repo, walletHandler, wsHub)
+	autoTrader.Start(ctx)
+
+	agentNotifier := service.NewAgentNotifier(repo, handler.PendingTokenPayload)
+	agentNotifier.Start(ctx)
+
+	scanner := service.NewScanner(ethClient, repo, wsHub, cfg.BscScanAPIKey, autoTrader, agentNotifier)
 	if err := scanner.Start(ctx); err != nil {
 		log.Printf("Scanner not started: %v", err)
 	}
 
-	tokenHandler := handler.NewTokenHandler(repo)
+	agentAccuracy := service.NewAgentAccuracy(repo)
+	agentAccuracy.Start(ctx)
+	outcomeLabeler := service.NewOutcomeLabeler(repo)
+	outcomeLabeler.Start(ctx)
+
+	tokenHandler := handler.NewTokenHandler(repo, autoTrader)
 	tradeHandler := handler.NewTradeHandler(repo)
-	walletHandler := handler.NewWalletHandler(repo, ethClient)
 	aiTradeHandler := handler.NewAITradeHandler(repo)
+	adminHandler := handler.NewAdminHandler(repo, breaker, scoring)
+	backtestHandler := handler.NewBacktestHandler(repo, valuator)
+
+	positionMonitor := service.NewPositionMonitor(ethClient, repo, walletHandler, wsHub)
+	positionMonitor.Start(ctx)
+	orderEngine := service.NewOrderEngine(ethClient, repo, walletHandler, wsHub)
+	orderEngine.Start(ctx)
+	receiptPoller := service.NewReceiptPoller(ethClient, repo, walletHandler)
+	receiptPoller.Start(ctx)
+	approvalExpirer := service.NewApprovalExpirer(repo, wsHub)
+	approvalExpirer.Start(ctx)
 
-	r := router.Setup(cfg, tokenHandler, tradeHandler, walletHandler, aiTradeHandler, wsHub, scanner)
+	r := router.Setup(cfg, tokenHandler, tradeHandler, walletHandler, aiTradeHandler, adminHandler, backtestHandler, wsHub, scanner, breaker)
 
 	go func() {
 		log.Printf("Server starting on port %s", cfg.Port)
@@ -15,19 +15,22 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/api/ai-positions": {
-            "get": {
-                "description": "Get AI positions by user (userId optional, fallback to EASYMEME_USER_ID)",
+        "/api/admin/kill-switch": {
+            "post": {
+                "description": "Block ExecuteTrade and every automated execution globally or for one user until reset",
                 "tags": [
-                    "ai-trades"
+                    "admin"
                 ],
-                "summary": "Get AI positions",
+                "summary": "Engage kill switch",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query"
+                        "description": "Kill switch payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.KillSwitchRequest"
+                        }
                     }
                 ],
                 "responses": {
@@ -36,10 +39,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "array",
-                                "items": {
-                                    "$ref": "#/definitions/handler.AIPositionResponse"
-                                }
+                                "$ref": "#/definitions/model.TradingHalt"
                             }
                         }
                     },
@@ -60,21 +60,30 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/ai-trades": {
+        "/api/admin/scoring-models": {
             "get": {
-                "description": "List AI trades",
+                "description": "The scoring config in effect and the stored versions, newest first",
                 "tags": [
-                    "ai-trades"
+                    "admin"
                 ],
-                "summary": "Get AI trades",
+                "summary": "List scoring model versions",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 50,
+                        "default": 20,
                         "description": "Limit",
                         "name": "limit",
                         "in": "query"
@@ -84,7 +93,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AITradeListResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.ScoringModelsResponse"
+                            }
                         }
                     },
                     "500": {
@@ -95,23 +107,32 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             },
             "post": {
-                "description": "Create AI trade record",
+                "description": "Validate a scoring config and store it as the next version, optionally activating it on every instance",
                 "tags": [
-                    "ai-trades"
+                    "admin"
                 ],
-                "summary": "Create AI trade",
+                "summary": "Publish scoring model version",
                 "parameters": [
                     {
-                        "description": "AI trade payload",
+                        "description": "Scoring model",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.CreateAITradeRequest"
+                            "$ref": "#/definitions/handler.PublishScoringModelRequest"
                         }
                     }
                 ],
@@ -119,7 +140,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AITradeResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.ScoringModel"
+                            }
                         }
                     },
                     "400": {
@@ -139,51 +163,70 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/ai-trades/stats": {
-            "get": {
-                "description": "Aggregate AI trade statistics",
+        "/api/admin/scoring-models/{version}/activate": {
+            "post": {
+                "description": "Switch every instance to a stored scoring model version, for example to roll back",
                 "tags": [
-                    "ai-trades"
+                    "admin"
+                ],
+                "summary": "Activate scoring model version",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "description": "Version",
+                        "name": "version",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Activation payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ActivateScoringModelRequest"
+                        }
+                    }
                 ],
-                "summary": "Get AI trade stats",
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/handler.AITradeStatsResponse"
+                                "$ref": "#/definitions/model.ScoringModel"
                             }
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/tokens": {
-            "get": {
-                "description": "List latest tokens",
-                "tags": [
-                    "tokens"
-                ],
-                "summary": "Get latest tokens",
-                "responses": {
-                    "200": {
-                        "description": "OK",
+                    },
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
-                            "$ref": "#/definitions/handler.TokenListResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "500": {
@@ -194,35 +237,9 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/tokens/analyzed": {
-            "get": {
-                "description": "List analyzed tokens",
-                "tags": [
-                    "tokens"
-                ],
-                "summary": "Get analyzed tokens",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 20,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.AnalyzedTokenListResponseEnvelope"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -233,18 +250,18 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tokens/golden-dogs": {
+        "/api/admin/trading-halts": {
             "get": {
-                "description": "List golden dog tokens sorted by effective score and excluding expired",
+                "description": "Active kill switches and circuit breakers plus recent history",
                 "tags": [
-                    "tokens"
+                    "admin"
                 ],
-                "summary": "Get golden dog tokens",
+                "summary": "List trading halts",
                 "parameters": [
                     {
                         "type": "integer",
-                        "default": 20,
-                        "description": "Limit",
+                        "default": 50,
+                        "description": "History limit",
                         "name": "limit",
                         "in": "query"
                     }
@@ -253,7 +270,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.GoldenDogListResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.TradingHaltsResponse"
+                            }
                         }
                     },
                     "500": {
@@ -264,41 +284,9 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
-                    }
-                }
-            }
-        },
-        "/api/tokens/pending": {
-            "get": {
-                "description": "List tokens pending analysis",
-                "tags": [
-                    "tokens"
-                ],
-                "summary": "Get pending tokens",
-                "parameters": [
-                    {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Limit",
-                        "name": "limit",
-                        "in": "query"
-                    },
-                    {
-                        "type": "number",
-                        "description": "Min liquidity",
-                        "name": "min_liquidity",
-                        "in": "query"
-                    }
-                ],
-                "responses": {
-                    "200": {
-                        "description": "OK",
-                        "schema": {
-                            "$ref": "#/definitions/handler.PendingTokenListResponseEnvelope"
-                        }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "503": {
+                        "description": "Service Unavailable",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -309,21 +297,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tokens/price-snapshots": {
+        "/api/admin/trading-halts/reset": {
             "post": {
-                "description": "Upsert a token price snapshot (for tx_agent data feed)",
+                "description": "Release a kill switch or reset tripped circuit breakers; breakers never reset on their own",
                 "tags": [
-                    "tokens"
+                    "admin"
                 ],
-                "summary": "Upsert token price snapshot",
+                "summary": "Reset kill switch or breaker",
                 "parameters": [
                     {
-                        "description": "snapshot",
+                        "description": "Reset payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.UpsertTokenPriceSnapshotPayload"
+                            "$ref": "#/definitions/handler.ResetHaltRequest"
                         }
                     }
                 ],
@@ -333,7 +321,8 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "integer",
+                                "format": "int64"
                             }
                         }
                     },
@@ -354,30 +343,37 @@ const docTemplate = `{
                                 "type": "string"
                             }
                         }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
                     }
                 }
             }
         },
-        "/api/tokens/stats/golden-dog-score-distribution": {
+        "/api/agent-subscriptions": {
             "get": {
-                "description": "Get analyzed token goldenDogScore distribution for recent days",
+                "description": "List the enriched-token webhooks of the calling agent. Without API keys configured the agent is named by X-Agent-Id or agentId, and every agent is listed when none is given.",
                 "tags": [
                     "tokens"
                 ],
-                "summary": "Get goldenDogScore distribution",
+                "summary": "List agent subscriptions",
                 "parameters": [
                     {
-                        "type": "integer",
-                        "default": 7,
-                        "description": "Recent days",
-                        "name": "days",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Agent identity, honoured only when the server has no API keys",
+                        "name": "X-Agent-Id",
+                        "in": "header"
                     },
                     {
-                        "type": "integer",
-                        "default": 10,
-                        "description": "Bucket size",
-                        "name": "bucket",
+                        "type": "string",
+                        "description": "Agent identity when the header is absent",
+                        "name": "agentId",
                         "in": "query"
                     }
                 ],
@@ -385,8 +381,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handler.AgentSubscriptionListResponseEnvelope"
                         }
                     },
                     "500": {
@@ -399,33 +394,51 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/tokens/{address}": {
-            "get": {
-                "description": "Get token by address",
+            },
+            "post": {
+                "description": "Register a webhook that receives every token entering the analysis queue with at least minPriority, as {\"type\":\"token_enriched\",\"data\":PendingTokenResponse}, unless another agent holds its lease. Bodies are signed with HMAC-SHA256 of secret in X-EasyMeme-Signature; a secret of at least 16 characters is required, or one is generated and returned only in this response. Failed deliveries are retried with backoff.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
                     "tokens"
                 ],
-                "summary": "Get token",
+                "summary": "Subscribe to enriched tokens",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Token address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
+                        "description": "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides",
+                        "name": "X-Agent-Id",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Subscription",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AgentSubscriptionRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TokenResponseEnvelope"
+                            "$ref": "#/definitions/handler.AgentSubscriptionResponseEnvelope"
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -436,33 +449,30 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tokens/{address}/analysis": {
+        "/api/agent-subscriptions/remove": {
             "post": {
-                "security": [
-                    {
-                        "ApiKeyAuth": []
-                    }
+                "description": "Stop pushing enriched tokens to a webhook",
+                "consumes": [
+                    "application/json"
                 ],
-                "description": "Submit AI analysis for a token",
                 "tags": [
                     "tokens"
                 ],
-                "summary": "Submit token analysis",
+                "summary": "Remove agent subscription",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Token address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
+                        "description": "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides",
+                        "name": "X-Agent-Id",
+                        "in": "header"
                     },
                     {
-                        "description": "Analysis payload",
+                        "description": "Subscription",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.AnalyzeTokenRiskPayload"
+                            "$ref": "#/definitions/handler.RemoveAgentSubscriptionRequest"
                         }
                     }
                 ],
@@ -470,7 +480,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.AnalysisStatusResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     },
                     "400": {
@@ -482,8 +495,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "401": {
-                        "description": "Unauthorized",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -503,31 +516,45 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tokens/{address}/detail": {
+        "/api/ai-positions": {
             "get": {
-                "description": "Get token detail including scores and time decay metadata",
+                "description": "Get AI positions by user with mark-to-market value in BNB and USD (userId optional, fallback to EASYMEME_USER_ID)",
                 "tags": [
-                    "tokens"
+                    "ai-trades"
                 ],
-                "summary": "Get token detail",
+                "summary": "Get AI positions",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Token address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TokenDetailResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.AIPositionResponse"
+                                }
+                            }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -538,38 +565,26 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/tokens/{address}/price-series": {
+        "/api/ai-trades": {
             "get": {
-                "description": "Get analyzed token subsequent price series",
+                "description": "List AI trades; paper trades are flagged simulated",
                 "tags": [
-                    "tokens"
+                    "ai-trades"
                 ],
-                "summary": "Get token price series",
+                "summary": "Get AI trades",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Token address",
-                        "name": "address",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "type": "string",
-                        "description": "RFC3339 start time",
-                        "name": "from",
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
                         "in": "query"
                     },
                     {
                         "type": "string",
-                        "description": "RFC3339 end time",
-                        "name": "to",
-                        "in": "query"
-                    },
-                    {
-                        "type": "integer",
-                        "default": 2000,
-                        "description": "Max points",
-                        "name": "limit",
+                        "default": "all",
+                        "description": "live, paper or all",
+                        "name": "mode",
                         "in": "query"
                     }
                 ],
@@ -577,8 +592,7 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "type": "object",
-                            "additionalProperties": true
+                            "$ref": "#/definitions/handler.AITradeListResponseEnvelope"
                         }
                     },
                     "500": {
@@ -591,29 +605,29 @@ const docTemplate = `{
                         }
                     }
                 }
-            }
-        },
-        "/api/trades": {
-            "get": {
-                "description": "Get trades by user address",
+            },
+            "post": {
+                "description": "Create AI trade record",
                 "tags": [
-                    "trades"
+                    "ai-trades"
                 ],
-                "summary": "Get trades",
+                "summary": "Create AI trade",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "User address",
-                        "name": "user",
-                        "in": "query",
-                        "required": true
+                        "description": "AI trade payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateAITradeRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TradeListResponseEnvelope"
+                            "$ref": "#/definitions/handler.AITradeResponseEnvelope"
                         }
                     },
                     "400": {
@@ -635,39 +649,59 @@ const docTemplate = `{
                         }
                     }
                 }
-            },
-            "post": {
-                "description": "Create trade record",
+            }
+        },
+        "/api/ai-trades/stats": {
+            "get": {
+                "description": "Aggregate AI trade statistics; paper trades are excluded unless mode is paper or all",
                 "tags": [
-                    "trades"
+                    "ai-trades"
                 ],
-                "summary": "Create trade",
+                "summary": "Get AI trade stats",
                 "parameters": [
                     {
-                        "description": "Trade payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateTradeRequest"
-                        }
+                        "type": "string",
+                        "default": "live",
+                        "description": "live, paper or all",
+                        "name": "mode",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TradeResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.AITradeStatsResponse"
+                            }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/analysis-agents": {
+            "get": {
+                "description": "Agents that submitted analyses with the accuracy that weighs them in the consensus",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "List analysis agents",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnalysisAgentListResponseEnvelope"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -681,28 +715,21 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/trades/{txHash}": {
-            "patch": {
-                "description": "Update trade status by tx hash",
+        "/api/backtest": {
+            "post": {
+                "description": "Replay the first golden dog verdict on each token stored between from and to, as it stood then, against stored price and liquidity snapshots, applying the config's entry, take-profit, stop-loss and trailing rules with modeled tax and slippage",
                 "tags": [
-                    "trades"
+                    "backtest"
                 ],
-                "summary": "Update trade status",
+                "summary": "Run backtest",
                 "parameters": [
                     {
-                        "type": "string",
-                        "description": "Transaction hash",
-                        "name": "txHash",
-                        "in": "path",
-                        "required": true
-                    },
-                    {
-                        "description": "Status payload",
+                        "description": "Backtest payload",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.TradeStatusUpdateRequest"
+                            "$ref": "#/definitions/handler.BacktestRequest"
                         }
                     }
                 ],
@@ -710,7 +737,10 @@ const docTemplate = `{
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.TradeStatusUpdateResponseEnvelope"
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/service.BacktestReport"
+                            }
                         }
                     },
                     "400": {
@@ -734,13 +764,13 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallet/balance": {
+        "/api/portfolio/realized": {
             "get": {
-                "description": "Get managed wallet balance by user",
+                "description": "Realized P\u0026L per sell in BNB and USD with gas included, newest first; totals cover the returned entries",
                 "tags": [
-                    "wallet"
+                    "ai-trades"
                 ],
-                "summary": "Get managed wallet balance",
+                "summary": "Realized P\u0026L ledger",
                 "parameters": [
                     {
                         "type": "string",
@@ -748,6 +778,19 @@ const docTemplate = `{
                         "name": "userId",
                         "in": "query",
                         "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -756,7 +799,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/handler.WalletBalanceResponse"
+                                "$ref": "#/definitions/handler.RealizedPnLResponse"
                             }
                         }
                     },
@@ -769,15 +812,6 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
-                        "schema": {
-                            "type": "object",
-                            "additionalProperties": {
-                                "type": "string"
-                            }
-                        }
-                    },
                     "500": {
                         "description": "Internal Server Error",
                         "schema": {
@@ -790,22 +824,33 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallet/config": {
-            "post": {
-                "description": "Upsert auto-trade config for managed wallet",
+        "/api/portfolio/snapshots": {
+            "get": {
+                "description": "Periodic portfolio valuations, newest first",
                 "tags": [
-                    "wallet"
+                    "ai-trades"
                 ],
-                "summary": "Upsert wallet config",
+                "summary": "Portfolio history",
                 "parameters": [
                     {
-                        "description": "Wallet config payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.WalletConfigRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start time",
+                        "name": "since",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 288,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
@@ -814,7 +859,10 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "type": "string"
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/model.PortfolioSnapshot"
+                                }
                             }
                         }
                     },
@@ -839,22 +887,20 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallet/create": {
-            "post": {
-                "description": "Create a managed wallet and store encrypted private key",
+        "/api/portfolio/summary": {
+            "get": {
+                "description": "Wallet BNB plus open positions marked to market in BNB and USD, with unrealized P\u0026L",
                 "tags": [
-                    "wallet"
+                    "ai-trades"
                 ],
-                "summary": "Create managed wallet",
+                "summary": "Portfolio summary",
                 "parameters": [
                     {
-                        "description": "Create wallet payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
-                        "schema": {
-                            "$ref": "#/definitions/handler.CreateWalletRequest"
-                        }
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
                     }
                 ],
                 "responses": {
@@ -863,7 +909,7 @@ const docTemplate = `{
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/handler.CreateWalletResponse"
+                                "$ref": "#/definitions/service.PortfolioValuation"
                             }
                         }
                     },
@@ -888,42 +934,130 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallet/execute-trade": {
-            "post": {
-                "description": "Execute managed wallet trade on BSC",
+        "/api/tokens": {
+            "get": {
+                "description": "List latest tokens",
                 "tags": [
-                    "wallet"
+                    "tokens"
                 ],
-                "summary": "Execute trade",
-                "parameters": [
-                    {
-                        "description": "Execute trade payload",
-                        "name": "payload",
-                        "in": "body",
-                        "required": true,
+                "summary": "Get latest tokens",
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/handler.ExecuteTradeRequest"
+                            "$ref": "#/definitions/handler.TokenListResponseEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
                         }
                     }
+                }
+            }
+        },
+        "/api/tokens/analyzed": {
+            "get": {
+                "description": "List analyzed tokens",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get analyzed tokens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnalyzedTokenListResponseEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/tokens/golden-dogs": {
+            "get": {
+                "description": "List golden dog tokens sorted by effective score and excluding expired",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get golden dog tokens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.GoldenDogListResponseEnvelope"
+                        }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/tokens/pending": {
+            "get": {
+                "description": "List tokens pending analysis, highest queue priority first",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get pending tokens",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "type": "number",
+                        "description": "Min liquidity",
+                        "name": "min_liquidity",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PendingTokenListResponseEnvelope"
+                        }
                     },
                     "500": {
                         "description": "Internal Server Error",
@@ -937,42 +1071,96 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallet/info": {
-            "get": {
-                "description": "Get managed wallet address and balance (userId optional, fallback to EASYMEME_USER_ID)",
+        "/api/tokens/pending/claim": {
+            "post": {
+                "description": "Lease up to limit pending tokens to the calling agent so other agents skip them. Leases expire after ttlSeconds (default 300, max 1800) unless renewed; submitting an analysis as the lease holder releases the token.",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "wallet"
+                    "tokens"
                 ],
-                "summary": "Get managed wallet info",
+                "summary": "Claim pending tokens",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "User ID",
-                        "name": "userId",
-                        "in": "query"
+                        "description": "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides",
+                        "name": "X-Agent-Id",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Claim",
+                        "name": "payload",
+                        "in": "body",
+                        "schema": {
+                            "$ref": "#/definitions/handler.ClaimPendingTokensRequest"
+                        }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.PendingTokenClaimResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/handler.WalletBalanceResponse"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
                                 "type": "string"
                             }
                         }
+                    }
+                }
+            }
+        },
+        "/api/tokens/pending/heartbeat": {
+            "post": {
+                "description": "Heartbeat: extend the leases the calling agent still holds. Addresses missing from renewed were claimed by another agent or already analyzed.",
+                "consumes": [
+                    "application/json"
+                ],
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Renew token leases",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides",
+                        "name": "X-Agent-Id",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Leases",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenLeaseRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenLeaseRenewResponseEnvelope"
+                        }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -992,36 +1180,82 @@ const docTemplate = `{
                 }
             }
         },
-        "/api/wallet/withdraw": {
+        "/api/tokens/pending/release": {
             "post": {
-                "description": "Decrease managed wallet balance (placeholder, no on-chain tx)",
+                "description": "Return tokens the calling agent will not analyze to the queue",
+                "consumes": [
+                    "application/json"
+                ],
                 "tags": [
-                    "wallet"
+                    "tokens"
                 ],
-                "summary": "Withdraw from managed wallet",
+                "summary": "Release token leases",
                 "parameters": [
                     {
-                        "description": "Withdraw payload",
+                        "type": "string",
+                        "description": "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides",
+                        "name": "X-Agent-Id",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Leases",
                         "name": "payload",
                         "in": "body",
                         "required": true,
                         "schema": {
-                            "$ref": "#/definitions/handler.WithdrawRequest"
+                            "$ref": "#/definitions/handler.TokenLeaseRequest"
                         }
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenLeaseReleaseResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
-                                "$ref": "#/definitions/handler.WithdrawResponse"
+                                "type": "string"
                             }
                         }
                     },
-                    "400": {
-                        "description": "Bad Request",
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/price-snapshots": {
+            "post": {
+                "description": "Upsert a token price snapshot (for tx_agent data feed)",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Upsert token price snapshot",
+                "parameters": [
+                    {
+                        "description": "snapshot",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.UpsertTokenPriceSnapshotPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1029,8 +1263,8 @@ const docTemplate = `{
                             }
                         }
                     },
-                    "404": {
-                        "description": "Not Found",
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "type": "object",
                             "additionalProperties": {
@@ -1049,181 +1283,4652 @@ const docTemplate = `{
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "handler.AIPositionResponse": {
-            "type": "object",
-            "properties": {
-                "cost_bnb": {
-                    "type": "string"
-                },
-                "quantity": {
-                    "type": "string"
-                },
-                "token_address": {
-                    "type": "string"
-                },
-                "token_symbol": {
-                    "type": "string"
-                },
-                "updated_at": {
-                    "type": "string"
-                },
-                "user_id": {
-                    "type": "string"
-                }
-            }
-        },
-        "handler.AITradeListResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.AITrade"
-                    }
-                }
-            }
-        },
-        "handler.AITradeResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.AITrade"
-                }
-            }
         },
-        "handler.AITradeStatsResponse": {
-            "type": "object",
+        "/api/tokens/stats/calibration": {
+            "get": {
+                "description": "Score the first analysis of each token against its labeled outcome: precision, recall, median max return and rug rate per score bucket and risk level, plus a calibration curve",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get golden dog score calibration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start of analysis time, 30 days before to by default",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end of analysis time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "24h",
+                        "description": "Outcome horizon: 1h, 6h or 24h",
+                        "name": "horizon",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "default": "2x",
+                        "description": "Good outcome label: 2x, 10x or survived",
+                        "name": "good",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "baseline or agent",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent id",
+                        "name": "agent",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Model version of the analysis; the scoring model version for baseline analyses",
+                        "name": "model_version",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Bucket size",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.CalibrationReportResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/stats/golden-dog-score-distribution": {
+            "get": {
+                "description": "Get analyzed token goldenDogScore distribution for recent days",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get goldenDogScore distribution",
+                "parameters": [
+                    {
+                        "type": "integer",
+                        "default": 7,
+                        "description": "Recent days",
+                        "name": "days",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 10,
+                        "description": "Bucket size",
+                        "name": "bucket",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{address}": {
+            "get": {
+                "description": "Get token by address",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get token",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenResponseEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{address}/analyses": {
+            "get": {
+                "description": "Every stored analysis of a token, newest first, with the current one flagged",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get token analysis history",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "baseline or agent",
+                        "name": "source",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenAnalysisListResponseEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{address}/analysis": {
+            "post": {
+                "security": [
+                    {
+                        "ApiKeyAuth": []
+                    }
+                ],
+                "description": "Submit an agent's analysis for a token. The token's scores become the accuracy-weighted consensus of every agent's latest analysis.",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Submit token analysis",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides",
+                        "name": "X-Agent-Id",
+                        "in": "header"
+                    },
+                    {
+                        "description": "Analysis payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnalyzeTokenRiskPayload"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.AnalysisStatusResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{address}/detail": {
+            "get": {
+                "description": "Get token detail including scores and time decay metadata",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get token detail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenDetailResponseEnvelope"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{address}/outcomes": {
+            "get": {
+                "description": "Outcome labels of a token at 1h, 6h and 24h after launch with the metrics behind them",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get token outcomes",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TokenOutcomeListResponseEnvelope"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/tokens/{address}/price-series": {
+            "get": {
+                "description": "Get analyzed token subsequent price series",
+                "tags": [
+                    "tokens"
+                ],
+                "summary": "Get token price series",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Token address",
+                        "name": "address",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 start time",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "RFC3339 end time",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 2000,
+                        "description": "Max points",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": true
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/trades": {
+            "get": {
+                "description": "Get trades by user address",
+                "tags": [
+                    "trades"
+                ],
+                "summary": "Get trades",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User address",
+                        "name": "user",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TradeListResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create trade record",
+                "tags": [
+                    "trades"
+                ],
+                "summary": "Create trade",
+                "parameters": [
+                    {
+                        "description": "Trade payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateTradeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TradeResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/trades/{txHash}": {
+            "patch": {
+                "description": "Update trade status by tx hash",
+                "tags": [
+                    "trades"
+                ],
+                "summary": "Update trade status",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Transaction hash",
+                        "name": "txHash",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Status payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TradeStatusUpdateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/handler.TradeStatusUpdateResponseEnvelope"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/allowances": {
+            "get": {
+                "description": "List outstanding token approvals of the managed wallet, refreshed from chain",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List token allowances",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.AllowanceResponse"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/allowances/revoke": {
+            "post": {
+                "description": "Reset approvals to zero for one token or for every outstanding approval",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Revoke token allowances",
+                "parameters": [
+                    {
+                        "description": "Revoke payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.RevokeAllowanceRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.RevokeAllowanceResult"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/approvals": {
+            "get": {
+                "description": "List trades held back by ConfirmThreshold; overdue approvals are expired first",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List trade approvals",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter (pending, approved, rejected, expired, executed, failed)",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/model.TradeApproval"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/approvals/approve": {
+            "post": {
+                "description": "Approve a pending trade and execute it; always requires a TOTP code",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Approve trade",
+                "parameters": [
+                    {
+                        "description": "Approval payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApprovalDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/approvals/events": {
+            "get": {
+                "description": "List who created, approved, rejected or executed an approval and when",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Trade approval audit trail",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Approval ID",
+                        "name": "approvalId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/model.TradeApprovalEvent"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/approvals/reject": {
+            "post": {
+                "description": "Reject a pending trade so it never executes; requires a TOTP code once TOTP is enabled",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Reject trade",
+                "parameters": [
+                    {
+                        "description": "Rejection payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ApprovalDecisionRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/balance": {
+            "get": {
+                "description": "Get managed wallet balance by user",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get managed wallet balance",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.WalletBalanceResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/config": {
+            "post": {
+                "description": "Upsert auto-trade config for managed wallet",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Upsert wallet config",
+                "parameters": [
+                    {
+                        "description": "Wallet config payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WalletConfigRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/create": {
+            "post": {
+                "description": "Create a managed wallet and store encrypted private key; paper wallets trade virtual BNB and never broadcast. Each user has one wallet, live or paper",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Create managed wallet",
+                "parameters": [
+                    {
+                        "description": "Create wallet payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateWalletRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.CreateWalletResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/execute-trade": {
+            "post": {
+                "description": "Execute managed wallet trade on BSC; BUYs above ConfirmThreshold are queued for approval (202), which needs TOTP enabled (403 otherwise). amountOut is the minimum received, in tokens for a BUY and BNB for a SELL",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Execute trade",
+                "parameters": [
+                    {
+                        "description": "Execute trade payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.ExecuteTradeRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "202": {
+                        "description": "Accepted",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "503": {
+                        "description": "Service Unavailable",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/info": {
+            "get": {
+                "description": "Get managed wallet address and balance (userId optional, fallback to EASYMEME_USER_ID)",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get managed wallet info",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.WalletBalanceResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/ladders": {
+            "get": {
+                "description": "List laddered entries with their tranches",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List laddered entries",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 20,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.LadderResponse"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Split a BUY into tranches spaced by time, blocks or price drawdown from the spot price at the first fill",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Create laddered entry",
+                "parameters": [
+                    {
+                        "description": "Ladder payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateLadderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.LadderResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/ladders/cancel": {
+            "post": {
+                "description": "Cancel every tranche that has not executed yet; filled tranches stay in the position",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Cancel laddered entry",
+                "parameters": [
+                    {
+                        "description": "Cancel payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CancelLadderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/liquidate": {
+            "post": {
+                "description": "Sell every open AI position in parallel; tokens whose sell simulation reverts are skipped and any allowance raised for them is reset. Runs even while trading is halted, and bypasses the policy's slippage and price impact caps, recording each bypass as a violation.",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Liquidate all positions",
+                "parameters": [
+                    {
+                        "description": "Liquidation payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.LiquidateRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.LiquidationResult"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/orders": {
+            "get": {
+                "description": "List limit and conditional orders for the managed wallet",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List resting orders",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Status filter",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "default": 100,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/model.TradeOrder"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Create a limit or conditional order executed by the managed wallet when its trigger holds",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Create resting order",
+                "parameters": [
+                    {
+                        "description": "Order payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CreateOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/model.TradeOrder"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/orders/cancel": {
+            "post": {
+                "description": "Cancel an open order, or one awaiting approval; the approval is rejected once no other order waits on it",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Cancel resting order",
+                "parameters": [
+                    {
+                        "description": "Cancel payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.CancelOrderRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/policy": {
+            "get": {
+                "description": "Get the spending policy evaluated before every managed wallet signature",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get wallet spending policy",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/service.SpendingPolicy"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Replace the wallet spending policy; requires a TOTP code once TOTP is enabled",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Upsert wallet spending policy",
+                "parameters": [
+                    {
+                        "description": "Wallet policy payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WalletPolicyRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/policy/violations": {
+            "get": {
+                "description": "List actions blocked by the wallet spending policy, and caps emergency exits bypassed (bypassed=true)",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Get policy violations",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "default": 50,
+                        "description": "Limit",
+                        "name": "limit",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.PolicyViolationResponse"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/totp/enable": {
+            "post": {
+                "description": "Confirm the TOTP secret from setup with a current code and the setupToken setup returned",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Enable TOTP",
+                "parameters": [
+                    {
+                        "description": "TOTP enable payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TOTPEnableRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/totp/setup": {
+            "post": {
+                "description": "Generate a TOTP secret for withdrawals and allowlist changes; it stays inactive until confirmed with the returned setupToken within 10 minutes, and cannot be replaced before then",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Set up TOTP",
+                "parameters": [
+                    {
+                        "description": "TOTP setup payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.TOTPSetupRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.TOTPSetupResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/withdraw": {
+            "post": {
+                "description": "Send BNB to an active allowlisted address; requires a TOTP code and respects the daily withdrawal limit",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Withdraw from managed wallet",
+                "parameters": [
+                    {
+                        "description": "Withdraw payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WithdrawRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.WithdrawResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "429": {
+                        "description": "Too Many Requests",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/withdrawal-addresses": {
+            "get": {
+                "description": "List allowlisted withdrawal addresses and their time-lock state",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "List withdrawal addresses",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "array",
+                                "items": {
+                                    "$ref": "#/definitions/handler.WithdrawalAddressResponse"
+                                }
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            },
+            "post": {
+                "description": "Allowlist a withdrawal address; it becomes usable after a 24h time-lock",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Add withdrawal address",
+                "parameters": [
+                    {
+                        "description": "Withdrawal address payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WithdrawalAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.WithdrawalAddressResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "409": {
+                        "description": "Conflict",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/withdrawal-addresses/remove": {
+            "post": {
+                "description": "Remove an address from the withdrawal allowlist",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Remove withdrawal address",
+                "parameters": [
+                    {
+                        "description": "Withdrawal address payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WithdrawalAddressRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/withdrawal-limit": {
+            "post": {
+                "description": "Set the rolling 24h withdrawal limit in BNB; decreases apply at once, increases after 24 hours",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Update daily withdrawal limit",
+                "parameters": [
+                    {
+                        "description": "Withdrawal limit payload",
+                        "name": "payload",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/handler.WithdrawalLimitRequest"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "$ref": "#/definitions/handler.WithdrawalLimitResponse"
+                            }
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "403": {
+                        "description": "Forbidden",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        },
+        "/api/wallet/ws": {
+            "get": {
+                "description": "WebSocket carrying the public stream plus the user's private events such as trade approvals",
+                "tags": [
+                    "wallet"
+                ],
+                "summary": "Wallet event stream",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "User ID",
+                        "name": "userId",
+                        "in": "query",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "101": {
+                        "description": "Switching Protocols",
+                        "schema": {
+                            "type": "string"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "type": "object",
+                            "additionalProperties": {
+                                "type": "string"
+                            }
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "handler.AIPositionResponse": {
+            "type": "object",
+            "properties": {
+                "cost_bnb": {
+                    "type": "string"
+                },
+                "entry_price_bnb": {
+                    "type": "string"
+                },
+                "marked_at": {
+                    "type": "string"
+                },
+                "price_bnb": {
+                    "type": "string"
+                },
+                "price_change": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                },
+                "token_symbol": {
+                    "type": "string"
+                },
+                "unrealized_pl": {
+                    "type": "number"
+                },
+                "unrealized_pl_bnb": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                },
+                "value_bnb": {
+                    "type": "string"
+                },
+                "value_usd": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AITradeListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AITrade"
+                    }
+                }
+            }
+        },
+        "handler.AITradeResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.AITrade"
+                }
+            }
+        },
+        "handler.AITradeStatsResponse": {
+            "type": "object",
+            "properties": {
+                "avgPL": {
+                    "type": "number"
+                },
+                "byPeriod": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PeriodStat"
+                    }
+                },
+                "byStrategy": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.StrategyStat"
+                    }
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "totalPL": {
+                    "type": "number"
+                },
+                "winRate": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.ActivateScoringModelRequest": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AgentSubscriptionCreatedResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "agent_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "consecutive failed deliveries",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_delivered_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "min_priority": {
+                    "type": "number"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AgentSubscriptionListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AgentSubscription"
+                    }
+                }
+            }
+        },
+        "handler.AgentSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "minPriority": {
+                    "type": "number"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AgentSubscriptionResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.AgentSubscriptionCreatedResponse"
+                }
+            }
+        },
+        "handler.AllowanceResponse": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "string"
+                },
+                "lastCheckedAt": {
+                    "type": "string"
+                },
+                "lastTxHash": {
+                    "type": "string"
+                },
+                "spender": {
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "tokenSymbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AnalysisAgentListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.AnalysisAgent"
+                    }
+                }
+            }
+        },
+        "handler.AnalysisStatusResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.AnalyzeTokenRiskPayload": {
+            "type": "object",
+            "properties": {
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "isGoldenDog": {
+                    "type": "boolean"
+                },
+                "reasoning": {
+                    "type": "string"
+                },
+                "recommendation": {
+                    "type": "string"
+                },
+                "riskFactors": {
+                    "type": "object",
+                    "properties": {
+                        "concentrationRisk": {
+                            "type": "string"
+                        },
+                        "honeypotRisk": {
+                            "type": "string"
+                        },
+                        "ownerRisk": {
+                            "type": "string"
+                        },
+                        "taxRisk": {
+                            "type": "string"
+                        }
+                    }
+                },
+                "riskLevel": {
+                    "type": "string"
+                },
+                "riskScore": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.AnalyzedTokenListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.TokenResponseDTO"
+                    }
+                }
+            }
+        },
+        "handler.ApprovalDecisionRequest": {
+            "type": "object",
+            "properties": {
+                "approvalId": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "totpCode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.BacktestRequest": {
+            "type": "object",
+            "properties": {
+                "bnbPriceUsd": {
+                    "description": "BNBPriceUSD defaults to the current WBNB/USDT price.",
+                    "type": "number"
+                },
+                "config": {
+                    "description": "Config is the auto-trade config to test; when omitted the stored config\nof UserID is used.",
+                    "allOf": [
+                        {
+                            "$ref": "#/definitions/service.AutoTradeConfig"
+                        }
+                    ]
+                },
+                "from": {
+                    "type": "string"
+                },
+                "gasBnb": {
+                    "type": "number"
+                },
+                "horizonMinutes": {
+                    "type": "integer"
+                },
+                "initialBalanceBnb": {
+                    "type": "number"
+                },
+                "maxSignals": {
+                    "description": "MaxSignals defaults to and is capped at 500; each signal reads its own\nsnapshot series.",
+                    "type": "integer"
+                },
+                "slippage": {
+                    "description": "Slippage is applied to every fill on top of modeled price impact (default 0.01).",
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CalibrationReportResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/service.CalibrationReport"
+                }
+            }
+        },
+        "handler.CancelLadderRequest": {
+            "type": "object",
+            "properties": {
+                "ladderId": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CancelOrderRequest": {
+            "type": "object",
+            "properties": {
+                "orderId": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ClaimPendingTokensRequest": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "limit": {
+                    "type": "integer"
+                },
+                "minLiquidity": {
+                    "type": "number"
+                },
+                "ttlSeconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.CreateAITradeRequest": {
+            "type": "object",
+            "properties": {
+                "amountIn": {
+                    "type": "string"
+                },
+                "amountOut": {
+                    "type": "string"
+                },
+                "currentValue": {
+                    "type": "string"
+                },
+                "decisionReason": {
+                    "type": "string"
+                },
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "profitLoss": {
+                    "type": "number"
+                },
+                "simulated": {
+                    "description": "Simulated records a paper trade.",
+                    "type": "boolean"
+                },
+                "strategyUsed": {
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "tokenDecimals": {
+                    "description": "TokenDecimals defaults to the indexed token's decimals, else 18.",
+                    "type": "integer"
+                },
+                "tokenSymbol": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateLadderRequest": {
+            "type": "object",
+            "properties": {
+                "drawdownStep": {
+                    "description": "DrawdownStep is the extra drop below the spot price at the first fill for each later tranche (0.1 = 10%).",
+                    "type": "number"
+                },
+                "expiresInMinutes": {
+                    "type": "integer"
+                },
+                "intervalBlocks": {
+                    "type": "integer"
+                },
+                "intervalSeconds": {
+                    "description": "IntervalSeconds spaces time tranches; IntervalBlocks spaces block tranches.",
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "time | block | drawdown",
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "tokenSymbol": {
+                    "type": "string"
+                },
+                "totalAmount": {
+                    "description": "BNB across all tranches",
+                    "type": "string"
+                },
+                "tranches": {
+                    "type": "integer"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateOrderRequest": {
+            "type": "object",
+            "properties": {
+                "amountIn": {
+                    "description": "BNB for BUY; token amount, ratio or ALL for SELL",
+                    "type": "string"
+                },
+                "amountOutMin": {
+                    "type": "string"
+                },
+                "expiresInMinutes": {
+                    "description": "ExpiresInMinutes is optional; zero keeps the order open until filled or cancelled.",
+                    "type": "integer"
+                },
+                "side": {
+                    "description": "BUY | SELL",
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "tokenSymbol": {
+                    "type": "string"
+                },
+                "trigger": {
+                    "description": "price_below | price_above | liquidity_above | golden_score_above",
+                    "type": "string"
+                },
+                "triggerValue": {
+                    "description": "BNB per token, USD liquidity or score",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateTradeRequest": {
+            "type": "object",
+            "properties": {
+                "amount_in": {
+                    "type": "string"
+                },
+                "amount_out": {
+                    "type": "string"
+                },
+                "gas_used": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                },
+                "token_symbol": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "type": {
+                    "type": "string"
+                },
+                "user_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateWalletRequest": {
+            "type": "object",
+            "properties": {
+                "mode": {
+                    "description": "Mode is live (default) or paper.",
+                    "type": "string"
+                },
+                "paperBalance": {
+                    "description": "PaperBalance is the virtual BNB a paper wallet starts with (default 10).",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.CreateWalletResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ExecuteTradeRequest": {
+            "type": "object",
+            "properties": {
+                "amountIn": {
+                    "description": "BNB for BUY, Token for SELL",
+                    "type": "string"
+                },
+                "amountOut": {
+                    "description": "minimum received: Token for BUY, BNB for SELL",
+                    "type": "string"
+                },
+                "decisionReason": {
+                    "type": "string"
+                },
+                "decisionTrail": {
+                    "description": "DecisionTrail is stored on the AITrade to explain automated decisions.",
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "force": {
+                    "type": "boolean"
+                },
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "profitLoss": {
+                    "type": "number"
+                },
+                "strategyUsed": {
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "tokenSymbol": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "BUY | SELL",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.GoldenDogListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.GoldenDogResponse"
+                    }
+                }
+            }
+        },
+        "handler.GoldenDogResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "analyzedAt": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "effectiveScore": {
+                    "type": "integer"
+                },
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "isGoldenDog": {
+                    "type": "boolean"
+                },
+                "liquidity": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pairAddress": {
+                    "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "riskLevel": {
+                    "type": "string"
+                },
+                "riskScore": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timeDecayFactor": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.KillSwitchRequest": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "UserID limits the kill switch to one user; empty halts all trading.",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.LadderResponse": {
+            "type": "object",
+            "properties": {
+                "ladder": {
+                    "$ref": "#/definitions/model.OrderLadder"
+                },
+                "tranches": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TradeOrder"
+                    }
+                }
+            }
+        },
+        "handler.LiquidateRequest": {
+            "type": "object",
+            "properties": {
+                "reason": {
+                    "type": "string"
+                },
+                "slippage": {
+                    "description": "Slippage applies to every token without an override (0.2 = 20%).",
+                    "type": "number"
+                },
+                "slippageOverrides": {
+                    "description": "SlippageOverrides maps token address to its slippage.",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "number",
+                        "format": "float64"
+                    }
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.LiquidationResult": {
+            "type": "object",
+            "properties": {
+                "amountIn": {
+                    "type": "string"
+                },
+                "amountOut": {
+                    "type": "string"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "minAmountOut": {
+                    "type": "string"
+                },
+                "profitLoss": {
+                    "type": "string"
+                },
+                "slippage": {
+                    "type": "number"
+                },
+                "status": {
+                    "description": "sold | skipped | failed",
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "tokenSymbol": {
+                    "type": "string"
+                },
+                "tradeId": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PendingTokenClaimResponse": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "leaseExpiresAt": {
+                    "type": "string"
+                },
+                "tokens": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PendingTokenResponse"
+                    }
+                }
+            }
+        },
+        "handler.PendingTokenClaimResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.PendingTokenClaimResponse"
+                }
+            }
+        },
+        "handler.PendingTokenListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.PendingTokenResponse"
+                    }
+                }
+            }
+        },
+        "handler.PendingTokenResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "baseline": {
+                    "description": "Baseline is the server's rule-based analysis, when one exists."
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creatorAddress": {
+                    "type": "string"
+                },
+                "creatorHistory": {},
+                "dexscreener": {},
+                "goplus": {},
+                "holderDistribution": {},
+                "liquidity": {
+                    "type": "number"
+                },
+                "marketAlerts": {},
+                "name": {
+                    "type": "string"
+                },
+                "pairAddress": {
+                    "type": "string"
+                },
+                "priority": {
+                    "description": "queue priority including aging",
+                    "type": "number"
+                },
+                "smartMoneySignals": {},
+                "socialSignals": {},
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PeriodStat": {
+            "type": "object",
+            "properties": {
+                "avgPL": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "period": {
+                    "type": "string"
+                },
+                "totalPL": {
+                    "type": "number"
+                },
+                "winRate": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.PolicyViolationResponse": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "bypassed": {
+                    "type": "boolean"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "details": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "id": {
+                    "type": "string"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "rule": {
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.PublishScoringModelRequest": {
+            "type": "object",
+            "properties": {
+                "activate": {
+                    "type": "boolean"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "config": {
+                    "description": "Config holds only the fields that differ from the built-in defaults.",
+                    "type": "object"
+                },
+                "note": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RealizedPnLResponse": {
+            "type": "object",
+            "properties": {
+                "entries": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.RealizedPnL"
+                    }
+                },
+                "gas_bnb": {
+                    "type": "string"
+                },
+                "total_bnb": {
+                    "type": "string"
+                },
+                "total_usd": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RemoveAgentSubscriptionRequest": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ResetHaltRequest": {
+            "type": "object",
+            "properties": {
+                "actor": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "Kind resets one kill switch or breaker; empty resets all of the scope.",
+                    "type": "string"
+                },
+                "userId": {
+                    "description": "UserID selects a per-user halt; empty resets global halts.",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RevokeAllowanceRequest": {
+            "type": "object",
+            "properties": {
+                "spender": {
+                    "description": "defaults to the PancakeSwap router",
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "description": "empty revokes every outstanding approval",
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.RevokeAllowanceResult": {
+            "type": "object",
+            "properties": {
+                "error": {
+                    "type": "string"
+                },
+                "spender": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.ScoringModelsResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "$ref": "#/definitions/model.ScoringConfig"
+                },
+                "versions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ScoringModel"
+                    }
+                }
+            }
+        },
+        "handler.StrategyStat": {
+            "type": "object",
+            "properties": {
+                "avgPL": {
+                    "type": "number"
+                },
+                "count": {
+                    "type": "integer"
+                },
+                "strategy": {
+                    "type": "string"
+                },
+                "totalPL": {
+                    "type": "number"
+                },
+                "winRate": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.TOTPEnableRequest": {
+            "type": "object",
+            "properties": {
+                "code": {
+                    "type": "string"
+                },
+                "setupToken": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TOTPSetupRequest": {
+            "type": "object",
+            "properties": {
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TOTPSetupResponse": {
+            "type": "object",
+            "properties": {
+                "expiresAt": {
+                    "type": "string"
+                },
+                "otpauthUrl": {
+                    "type": "string"
+                },
+                "secret": {
+                    "type": "string"
+                },
+                "setupToken": {
+                    "description": "SetupToken must accompany the code that enables the secret.",
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TokenAnalysisListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.TokenAnalysisResponse"
+                    }
+                }
+            }
+        },
+        "handler.TokenAnalysisResponse": {
+            "type": "object",
+            "properties": {
+                "agent_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "current": {
+                    "type": "boolean"
+                },
+                "golden_dog_score": {
+                    "type": "integer"
+                },
+                "honeypot_risk": {
+                    "description": "LOW | MEDIUM | HIGH, empty when not assessed",
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "is_golden_dog": {
+                    "type": "boolean"
+                },
+                "model": {
+                    "description": "\"baseline\" or the agent's model",
+                    "type": "string"
+                },
+                "model_version": {
+                    "description": "scoring model version for baseline",
+                    "type": "string"
+                },
+                "payload": {
+                    "type": "object"
+                },
+                "risk_level": {
+                    "type": "string"
+                },
+                "risk_score": {
+                    "type": "integer"
+                },
+                "source": {
+                    "description": "baseline, agent",
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TokenDetailResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "agentCount": {
+                    "type": "integer"
+                },
+                "agentDisagreement": {
+                    "type": "boolean"
+                },
+                "analysisResult": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "analysisSource": {
+                    "type": "string"
+                },
+                "analyzedAt": {
+                    "type": "string"
+                },
+                "consensus": {
+                    "$ref": "#/definitions/service.Consensus"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "creatorAddress": {
+                    "type": "string"
+                },
+                "creatorHistory": {},
+                "dex": {
+                    "type": "string"
+                },
+                "dexscreener": {},
+                "effectiveScore": {
+                    "type": "integer"
+                },
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "goplus": {},
+                "holderDistribution": {},
+                "id": {
+                    "type": "string"
+                },
+                "isGoldenDog": {
+                    "type": "boolean"
+                },
+                "liquidity": {
+                    "type": "number"
+                },
+                "marketAlerts": {},
+                "name": {
+                    "type": "string"
+                },
+                "pairAddress": {
+                    "type": "string"
+                },
+                "phase": {
+                    "type": "string"
+                },
+                "riskDetails": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "riskLevel": {
+                    "type": "string"
+                },
+                "riskScore": {
+                    "type": "integer"
+                },
+                "scoringVersion": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timeDecayFactor": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.TokenDetailResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.TokenDetailResponse"
+                }
+            }
+        },
+        "handler.TokenLeaseReleaseResponse": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "released": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.TokenLeaseReleaseResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.TokenLeaseReleaseResponse"
+                }
+            }
+        },
+        "handler.TokenLeaseRenewResponse": {
+            "type": "object",
+            "properties": {
+                "agentId": {
+                    "type": "string"
+                },
+                "leaseExpiresAt": {
+                    "type": "string"
+                },
+                "renewed": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.TokenLeaseRenewResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.TokenLeaseRenewResponse"
+                }
+            }
+        },
+        "handler.TokenLeaseRequest": {
+            "type": "object",
+            "properties": {
+                "addresses": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "agentId": {
+                    "type": "string"
+                },
+                "ttlSeconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "handler.TokenListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/handler.TokenResponseDTO"
+                    }
+                }
+            }
+        },
+        "handler.TokenOutcomeListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TokenOutcome"
+                    }
+                }
+            }
+        },
+        "handler.TokenResponseDTO": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "agent_count": {
+                    "type": "integer"
+                },
+                "agent_disagreement": {
+                    "type": "boolean"
+                },
+                "analysis_source": {
+                    "type": "string"
+                },
+                "analysis_status": {
+                    "type": "string"
+                },
+                "analyzed_at": {
+                    "type": "string"
+                },
+                "buy_tax": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "creator_address": {
+                    "type": "string"
+                },
+                "decimals": {
+                    "type": "integer"
+                },
+                "dex": {
+                    "type": "string"
+                },
+                "golden_dog_score": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "initial_liquidity": {
+                    "type": "string"
+                },
+                "is_golden_dog": {
+                    "type": "boolean"
+                },
+                "is_honeypot": {
+                    "type": "boolean"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "pair_address": {
+                    "type": "string"
+                },
+                "risk_level": {
+                    "type": "string"
+                },
+                "risk_score": {
+                    "type": "integer"
+                },
+                "scoring_version": {
+                    "type": "integer"
+                },
+                "sell_tax": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TokenResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/handler.TokenResponseDTO"
+                }
+            }
+        },
+        "handler.TradeListResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.Trade"
+                    }
+                }
+            }
+        },
+        "handler.TradeResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/model.Trade"
+                }
+            }
+        },
+        "handler.TradeStatusUpdateRequest": {
+            "type": "object",
+            "properties": {
+                "status": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.TradeStatusUpdateResponseEnvelope": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "handler.TradingHaltsResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TradingHalt"
+                    }
+                },
+                "history": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.TradingHalt"
+                    }
+                }
+            }
+        },
+        "handler.UpsertTokenPriceSnapshotPayload": {
+            "type": "object",
+            "properties": {
+                "liquidityUsd": {
+                    "type": "number"
+                },
+                "priceUsd": {
+                    "type": "number"
+                },
+                "tokenAddress": {
+                    "type": "string"
+                },
+                "ts": {
+                    "type": "string"
+                },
+                "volume5mUsd": {
+                    "type": "number"
+                }
+            }
+        },
+        "handler.WalletBalanceResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "string"
+                },
+                "mode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WalletConfigRequest": {
+            "type": "object",
+            "properties": {
+                "config": {
+                    "type": "object",
+                    "additionalProperties": true
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WalletPolicyRequest": {
+            "type": "object",
+            "properties": {
+                "policy": {
+                    "$ref": "#/definitions/service.SpendingPolicy"
+                },
+                "totpCode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WithdrawRequest": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "description": "Amount is in BNB; send it as a string to keep full precision.",
+                    "type": "string"
+                },
+                "toAddress": {
+                    "type": "string"
+                },
+                "totpCode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WithdrawResponse": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "balance": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "toAddress": {
+                    "type": "string"
+                },
+                "txHash": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WithdrawalAddressRequest": {
+            "type": "object",
+            "properties": {
+                "address": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                },
+                "totpCode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WithdrawalAddressResponse": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "activeAt": {
+                    "type": "string"
+                },
+                "address": {
+                    "type": "string"
+                },
+                "createdAt": {
+                    "type": "string"
+                },
+                "label": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WithdrawalLimitRequest": {
+            "type": "object",
+            "properties": {
+                "dailyLimit": {
+                    "type": "string"
+                },
+                "totpCode": {
+                    "type": "string"
+                },
+                "userId": {
+                    "type": "string"
+                }
+            }
+        },
+        "handler.WithdrawalLimitResponse": {
+            "type": "object",
+            "properties": {
+                "activeAt": {
+                    "type": "string"
+                },
+                "dailyLimit": {
+                    "type": "string"
+                },
+                "pendingLimit": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AITrade": {
+            "type": "object",
+            "properties": {
+                "amount_in": {
+                    "type": "number"
+                },
+                "amount_in_wei": {
+                    "type": "number"
+                },
+                "amount_out": {
+                    "type": "number"
+                },
+                "amount_out_wei": {
+                    "type": "number"
+                },
+                "block_number": {
+                    "type": "integer"
+                },
+                "current_value": {
+                    "description": "CurrentValue is the BNB value of the tokens a BUY received that are\nstill held, as last marked; it drops to 0 once they are all sold.",
+                    "type": "number"
+                },
+                "decision_reason": {
+                    "type": "string"
+                },
+                "decision_trail": {
+                    "description": "DecisionTrail records the inputs and checks behind an automated trade.",
+                    "type": "object"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "gas_fee_bnb": {
+                    "description": "GasFeeBNB is the gas paid by the swap transaction.",
+                    "type": "number"
+                },
+                "gas_used": {
+                    "description": "GasUsed is in gas units; GasFeeBNB is what it cost.",
+                    "type": "integer"
+                },
+                "golden_dog_score": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "description": "OrderID is the resting order that placed the trade, if any.",
+                    "type": "string"
+                },
+                "profit_loss": {
+                    "description": "ProfitLoss is the realized return of a sell as a ratio of its cost basis.",
+                    "type": "number"
+                },
+                "realized_pl_bnb": {
+                    "description": "RealizedPLBNB and RealizedPLUSD are a sell's ledger P\u0026L net of gas.",
+                    "type": "number"
+                },
+                "realized_pl_usd": {
+                    "type": "number"
+                },
+                "simulated": {
+                    "description": "Simulated marks paper trades, which were filled against a quote and\nnever broadcast.",
+                    "type": "boolean"
+                },
+                "status": {
+                    "description": "pending | success | failed",
+                    "type": "string"
+                },
+                "strategy_used": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                },
+                "token_decimals": {
+                    "description": "TokenDecimals scales the token side of the trade: the output of a BUY\nand the input of a SELL. The BNB side always has 18 decimals.",
+                    "type": "integer"
+                },
+                "token_symbol": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "type": {
+                    "description": "BUY | SELL",
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AgentSubscription": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "type": "boolean"
+                },
+                "agent_id": {
+                    "type": "string"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "failures": {
+                    "description": "consecutive failed deliveries",
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_delivered_at": {
+                    "type": "string"
+                },
+                "last_error": {
+                    "type": "string"
+                },
+                "min_priority": {
+                    "type": "number"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "url": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.AnalysisAgent": {
+            "type": "object",
+            "properties": {
+                "accuracy": {
+                    "type": "number"
+                },
+                "accuracy_at": {
+                    "type": "string"
+                },
+                "agent_id": {
+                    "type": "string"
+                },
+                "correct": {
+                    "type": "integer"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "last_seen_at": {
+                    "type": "string"
+                },
+                "precision": {
+                    "description": "share of golden dog calls that were right",
+                    "type": "number"
+                },
+                "recall": {
+                    "description": "share of golden dogs it called",
+                    "type": "number"
+                },
+                "samples": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.GoldenDogListSize": {
+            "type": "object",
             "properties": {
-                "avgPL": {
+                "fetchMultiplier": {
+                    "type": "integer"
+                },
+                "maxFetch": {
+                    "type": "integer"
+                },
+                "minFetch": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.OrderLadder": {
+            "type": "object",
+            "properties": {
+                "avg_price_bnb": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "drawdown_step": {
+                    "description": "each tranche rests this fraction further below FirstFillPrice",
+                    "type": "number"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "filled_bnb": {
+                    "type": "number"
+                },
+                "filled_tokens": {
+                    "type": "number"
+                },
+                "filled_tranches": {
+                    "type": "integer"
+                },
+                "first_fill_price": {
+                    "description": "spot price when the first tranche filled",
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "interval_blocks": {
+                    "type": "integer"
+                },
+                "interval_seconds": {
+                    "type": "integer"
+                },
+                "mode": {
+                    "description": "time | block | drawdown",
+                    "type": "string"
+                },
+                "status": {
+                    "description": "open | filled | partial | failed | cancelled | expired",
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                },
+                "token_symbol": {
+                    "type": "string"
+                },
+                "total_amount_bnb": {
+                    "type": "number"
+                },
+                "tranches": {
+                    "type": "integer"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.PortfolioSnapshot": {
+            "type": "object",
+            "properties": {
+                "bnb_price_usd": {
+                    "type": "number"
+                },
+                "cash_bnb": {
+                    "type": "number"
+                },
+                "cost_bnb": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "open_positions": {
+                    "type": "integer"
+                },
+                "positions": {
+                    "description": "Positions holds the per-token valuations behind the totals.",
+                    "type": "object"
+                },
+                "positions_bnb": {
+                    "type": "number"
+                },
+                "total_bnb": {
+                    "type": "number"
+                },
+                "total_usd": {
+                    "type": "number"
+                },
+                "unrealized_pl_bnb": {
+                    "type": "number"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.RealizedPnL": {
+            "type": "object",
+            "properties": {
+                "bnb_price_usd": {
+                    "type": "number"
+                },
+                "cost_basis": {
+                    "description": "fifo | average",
+                    "type": "string"
+                },
+                "cost_bnb": {
+                    "type": "number"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "gas_bnb": {
+                    "type": "number"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "lots": {
+                    "description": "Lots lists the lots consumed and how much of each.",
+                    "type": "object"
+                },
+                "proceeds_bnb": {
+                    "description": "ProceedsBNB is the BNB received after sell taxes, before gas.",
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "realized_bnb": {
+                    "type": "number"
+                },
+                "realized_usd": {
+                    "type": "number"
+                },
+                "simulated": {
+                    "description": "Simulated marks paper trades, which stay out of live loss limits.",
+                    "type": "boolean"
+                },
+                "token_address": {
+                    "type": "string"
+                },
+                "token_symbol": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "user_id": {
+                    "type": "string"
+                }
+            }
+        },
+        "model.ScoringConfig": {
+            "type": "object",
+            "properties": {
+                "expiredDecay": {
+                    "type": "number"
+                },
+                "expiredPhase": {
+                    "type": "string"
+                },
+                "goldenDogList": {
+                    "$ref": "#/definitions/model.GoldenDogListSize"
+                },
+                "levels": {
+                    "$ref": "#/definitions/model.ScoringLevels"
+                },
+                "phases": {
+                    "description": "Phases are consecutive age windows. Decay runs linearly from DecayFrom\nat the start of a phase to DecayTo at its end. Trading rules match\nphases by name, so renaming one changes which tokens they accept.",
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/model.ScoringPhase"
+                    }
+                },
+                "priority": {
+                    "$ref": "#/definitions/model.ScoringPriority"
+                },
+                "thresholds": {
+                    "$ref": "#/definitions/model.ScoringThresholds"
+                },
+                "version": {
+                    "description": "Version is the ScoringModel version the config was loaded from; 0 is\nthe built-in default.",
+                    "type": "integer"
+                },
+                "weights": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "integer"
+                    }
+                }
+            }
+        },
+        "model.ScoringLevels": {
+            "type": "object",
+            "properties": {
+                "categoryHigh": {
+                    "description": "A risk category is HIGH or MEDIUM once it loses this many points.",
+                    "type": "integer"
+                },
+                "categoryMedium": {
+                    "type": "integer"
+                },
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "riskShare": {
+                    "description": "RiskShare is the part of the risk score a golden dog score starts from.",
+                    "type": "number"
+                },
+                "safeScore": {
+                    "type": "integer"
+                },
+                "warningScore": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ScoringModel": {
+            "type": "object",
+            "properties": {
+                "activated_at": {
+                    "type": "string"
+                },
+                "active": {
+                    "type": "boolean"
+                },
+                "config": {
+                    "type": "object"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "created_by": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "note": {
+                    "type": "string"
+                },
+                "version": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ScoringPhase": {
+            "type": "object",
+            "properties": {
+                "decayFrom": {
+                    "type": "number"
+                },
+                "decayTo": {
+                    "type": "number"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "untilMinutes": {
+                    "type": "integer"
+                }
+            }
+        },
+        "model.ScoringPriority": {
+            "type": "object",
+            "properties": {
+                "agingPerMinute": {
+                    "type": "number"
+                },
+                "buyFlowPoints": {
+                    "type": "number"
+                },
+                "creatorPoints": {
+                    "type": "number"
+                },
+                "freshnessPoints": {
+                    "type": "number"
+                },
+                "holderGrowthPoints": {
+                    "type": "number"
+                },
+                "holdersPerMinute": {
+                    "type": "number"
+                },
+                "liquidityPoints": {
+                    "type": "number"
+                },
+                "liquidityUsd": {
+                    "type": "number"
+                },
+                "maxAging": {
+                    "type": "number"
+                }
+            }
+        },
+        "model.ScoringThresholds": {
+            "type": "object",
+            "properties": {
+                "activeVolumeRatio": {
+                    "type": "number"
+                },
+                "holderBase": {
+                    "type": "integer"
+                },
+                "liquidityDeepUsd": {
+                    "type": "number"
+                },
+                "liquidityDrop": {
+                    "description": "LiquidityDrop is the relative change between two market refreshes\nthat raises a LIQUIDITY_DROP alert.",
+                    "type": "number"
+                },
+                "liquidityLowUsd": {
+                    "type": "number"
+                },
+                "liquidityMediumUsd": {
+                    "type": "number"
+                },
+                "minHolders": {
+                    "type": "integer"
+                },
+                "minTxnsH1": {
+                    "type": "integer"
+                },
+                "priceDumpH1": {
+                    "type": "number"
+                },
+                "priceMomentumH1": {
                     "type": "number"
                 },
-                "byPeriod": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.PeriodStat"
-                    }
+                "priceSurgeH1": {
+                    "type": "number"
                 },
-                "byStrategy": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.StrategyStat"
-                    }
+                "sellPressureRatio": {
+                    "type": "number"
                 },
-                "count": {
+                "serialDeployerHigh": {
                     "type": "integer"
                 },
-                "totalPL": {
+                "serialDeployerMedium": {
+                    "type": "integer"
+                },
+                "taxExtreme": {
                     "type": "number"
                 },
-                "winRate": {
+                "taxHigh": {
+                    "type": "number"
+                },
+                "taxMedium": {
+                    "type": "number"
+                },
+                "top10High": {
+                    "type": "number"
+                },
+                "top10Medium": {
                     "type": "number"
                 }
             }
         },
-        "handler.AnalysisStatusResponseEnvelope": {
+        "model.TokenOutcome": {
             "type": "object",
             "properties": {
-                "status": {
+                "created_at": {
                     "type": "string"
-                }
-            }
-        },
-        "handler.AnalyzeTokenRiskPayload": {
-            "type": "object",
-            "properties": {
-                "goldenDogScore": {
-                    "type": "integer"
-                },
-                "isGoldenDog": {
-                    "type": "boolean"
                 },
-                "reasoning": {
+                "evaluated_at": {
                     "type": "string"
                 },
-                "recommendation": {
+                "evidence": {
+                    "type": "object"
+                },
+                "horizon": {
+                    "description": "1h, 6h, 24h",
                     "type": "string"
                 },
-                "riskFactors": {
-                    "type": "object",
-                    "properties": {
-                        "concentrationRisk": {
-                            "type": "string"
-                        },
-                        "honeypotRisk": {
-                            "type": "string"
-                        },
-                        "ownerRisk": {
-                            "type": "string"
-                        },
-                        "taxRisk": {
-                            "type": "string"
-                        }
-                    }
+                "horizon_at": {
+                    "type": "string"
                 },
-                "riskLevel": {
+                "id": {
                     "type": "string"
                 },
-                "riskScore": {
-                    "type": "integer"
-                }
-            }
-        },
-        "handler.AnalyzedTokenListResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
+                "labels": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.TokenResponseDTO"
+                        "type": "string"
                     }
+                },
+                "live": {
+                    "description": "Live is true when the chain state was read before the horizon passed;\notherwise only stored snapshots up to the horizon were used.",
+                    "type": "boolean"
+                },
+                "max_return": {
+                    "description": "peak price over entry price",
+                    "type": "number"
+                },
+                "metrics": {
+                    "type": "object"
+                },
+                "outcome": {
+                    "description": "first of Labels",
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
                 }
             }
         },
-        "handler.CreateAITradeRequest": {
+        "model.Trade": {
             "type": "object",
             "properties": {
-                "amountIn": {
-                    "type": "string"
-                },
-                "amountOut": {
-                    "type": "string"
+                "amount_in": {
+                    "type": "number"
                 },
-                "currentValue": {
-                    "type": "string"
+                "amount_out": {
+                    "type": "number"
                 },
-                "decisionReason": {
+                "created_at": {
                     "type": "string"
                 },
-                "goldenDogScore": {
-                    "type": "integer"
-                },
-                "profitLoss": {
+                "gas_used": {
                     "type": "number"
                 },
-                "strategyUsed": {
+                "id": {
                     "type": "string"
                 },
-                "tokenAddress": {
+                "status": {
+                    "description": "pending, success, failed",
                     "type": "string"
                 },
-                "tokenSymbol": {
+                "token_address": {
                     "type": "string"
                 },
-                "txHash": {
+                "token_symbol": {
+                    "type": "string"
+                },
+                "tx_hash": {
                     "type": "string"
                 },
                 "type": {
+                    "description": "buy, sell",
                     "type": "string"
                 },
-                "userId": {
+                "user_address": {
                     "type": "string"
                 }
             }
         },
-        "handler.CreateTradeRequest": {
+        "model.TradeApproval": {
             "type": "object",
             "properties": {
                 "amount_in": {
@@ -1232,10 +5937,45 @@ const docTemplate = `{
                 "amount_out": {
                     "type": "string"
                 },
-                "gas_used": {
+                "created_at": {
+                    "type": "string"
+                },
+                "decided_at": {
+                    "type": "string"
+                },
+                "decided_by": {
+                    "type": "string"
+                },
+                "decision_note": {
+                    "type": "string"
+                },
+                "decision_trail": {
+                    "type": "object"
+                },
+                "error_message": {
+                    "type": "string"
+                },
+                "expires_at": {
+                    "type": "string"
+                },
+                "golden_score": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "order_id": {
+                    "description": "resting order that placed the trade, if any",
+                    "type": "string"
+                },
+                "reason": {
                     "type": "string"
                 },
                 "status": {
+                    "description": "pending | approved | rejected | expired | executed | failed",
+                    "type": "string"
+                },
+                "strategy_used": {
                     "type": "string"
                 },
                 "token_address": {
@@ -1244,121 +5984,194 @@ const docTemplate = `{
                 "token_symbol": {
                     "type": "string"
                 },
+                "trade_id": {
+                    "type": "string"
+                },
                 "tx_hash": {
                     "type": "string"
                 },
                 "type": {
+                    "description": "BUY | SELL",
                     "type": "string"
                 },
-                "user_address": {
+                "updated_at": {
                     "type": "string"
-                }
-            }
-        },
-        "handler.CreateWalletRequest": {
-            "type": "object",
-            "properties": {
-                "userId": {
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "handler.CreateWalletResponse": {
+        "model.TradeApprovalEvent": {
             "type": "object",
             "properties": {
-                "address": {
+                "action": {
+                    "description": "created | approved | rejected | expired | executed | failed",
+                    "type": "string"
+                },
+                "actor": {
+                    "type": "string"
+                },
+                "approval_id": {
+                    "type": "string"
+                },
+                "created_at": {
                     "type": "string"
                 },
                 "id": {
                     "type": "string"
                 },
-                "userId": {
+                "note": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "handler.ExecuteTradeRequest": {
+        "model.TradeOrder": {
             "type": "object",
             "properties": {
-                "amountIn": {
-                    "description": "BNB for BUY, Token for SELL",
+                "amount_in": {
+                    "description": "BNB for BUY; token amount, ratio or ALL for SELL",
                     "type": "string"
                 },
-                "amountOut": {
+                "amount_out": {
                     "type": "string"
                 },
-                "decisionReason": {
+                "amount_out_min": {
                     "type": "string"
                 },
-                "force": {
-                    "type": "boolean"
+                "approval_id": {
+                    "description": "TradeApproval an awaiting_approval order waits on",
+                    "type": "string"
                 },
-                "goldenDogScore": {
-                    "type": "integer"
+                "created_at": {
+                    "type": "string"
                 },
-                "profitLoss": {
-                    "type": "number"
+                "error_message": {
+                    "type": "string"
                 },
-                "strategyUsed": {
+                "expires_at": {
                     "type": "string"
                 },
-                "tokenAddress": {
+                "filled_at": {
                     "type": "string"
                 },
-                "tokenSymbol": {
+                "id": {
                     "type": "string"
                 },
-                "type": {
+                "parent_id": {
+                    "description": "OrderLadder this tranche belongs to",
+                    "type": "string"
+                },
+                "side": {
                     "description": "BUY | SELL",
                     "type": "string"
                 },
-                "userId": {
+                "spot_price": {
+                    "description": "token price in BNB from pair reserves when triggered, before the swap",
+                    "type": "number"
+                },
+                "status": {
+                    "description": "waiting | open | executing | awaiting_approval | filled | failed | cancelled | expired",
+                    "type": "string"
+                },
+                "token_address": {
+                    "type": "string"
+                },
+                "token_symbol": {
+                    "type": "string"
+                },
+                "trade_id": {
+                    "type": "string"
+                },
+                "tranche_index": {
+                    "type": "integer"
+                },
+                "trigger": {
+                    "description": "price_below | price_above | liquidity_above | golden_score_above | at_time | at_block",
+                    "type": "string"
+                },
+                "trigger_value": {
+                    "type": "number"
+                },
+                "triggered_at": {
+                    "type": "string"
+                },
+                "tx_hash": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "string"
+                },
+                "user_id": {
                     "type": "string"
                 }
             }
         },
-        "handler.GoldenDogListResponseEnvelope": {
+        "model.TradingHalt": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/handler.GoldenDogResponse"
-                    }
+                "active": {
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "string"
+                },
+                "kind": {
+                    "description": "kill_switch | consecutive_failures | loss_rate | rpc_degraded | daily_loss",
+                    "type": "string"
+                },
+                "reason": {
+                    "type": "string"
+                },
+                "reset_at": {
+                    "type": "string"
+                },
+                "reset_by": {
+                    "type": "string"
+                },
+                "scope": {
+                    "description": "\"global\" or \"user:\u003cid\u003e\"",
+                    "type": "string"
+                },
+                "tripped_at": {
+                    "type": "string"
+                },
+                "tripped_by": {
+                    "type": "string"
                 }
             }
         },
-        "handler.GoldenDogResponse": {
+        "service.AgentVerdict": {
             "type": "object",
             "properties": {
-                "address": {
-                    "type": "string"
-                },
-                "analyzedAt": {
+                "agentId": {
                     "type": "string"
                 },
-                "createdAt": {
+                "analysisId": {
                     "type": "string"
                 },
-                "effectiveScore": {
-                    "type": "integer"
+                "analyzedAt": {
+                    "type": "string"
                 },
                 "goldenDogScore": {
                     "type": "integer"
                 },
+                "honeypotRisk": {
+                    "type": "string"
+                },
                 "isGoldenDog": {
                     "type": "boolean"
                 },
-                "liquidity": {
-                    "type": "number"
-                },
-                "name": {
-                    "type": "string"
-                },
-                "pairAddress": {
+                "model": {
                     "type": "string"
                 },
-                "phase": {
+                "modelVersion": {
                     "type": "string"
                 },
                 "riskLevel": {
@@ -1367,454 +6180,675 @@ const docTemplate = `{
                 "riskScore": {
                     "type": "integer"
                 },
-                "symbol": {
-                    "type": "string"
-                },
-                "timeDecayFactor": {
+                "weight": {
                     "type": "number"
                 }
             }
         },
-        "handler.PendingTokenListResponseEnvelope": {
+        "service.AutoTradeConfig": {
             "type": "object",
             "properties": {
-                "data": {
+                "approvalTtlMinutes": {
+                    "description": "ApprovalTTLMinutes bounds how long a trade waits for ConfirmThreshold sign-off.",
+                    "type": "integer"
+                },
+                "approvalWebhookUrl": {
+                    "description": "ApprovalWebhookURL receives pending approvals, signed with WebhookSecret when set.",
+                    "type": "string"
+                },
+                "autoBuyAmount": {
+                    "description": "AutoBuyAmount is the BNB the auto-trader spends per golden dog; defaults to MaxAmountPerTrade.",
+                    "type": "number"
+                },
+                "autoTradeBaseline": {
+                    "description": "AutoTradeBaseline lets golden dogs scored only by the server's baseline\nrules, before any agent reviewed the token, trigger auto buys.",
+                    "type": "boolean"
+                },
+                "confirmThreshold": {
+                    "type": "number"
+                },
+                "costBasis": {
+                    "description": "CostBasis matches sells to buy lots: fifo (default) or average.",
+                    "type": "string"
+                },
+                "dailyBudget": {
+                    "type": "number"
+                },
+                "enabled": {
+                    "type": "boolean"
+                },
+                "exitPhases": {
+                    "description": "ExitPhases lists GoldenDogPhase values (e.g. DECLINING) that force an exit.",
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.PendingTokenResponse"
+                        "type": "string"
                     }
-                }
-            }
-        },
-        "handler.PendingTokenResponse": {
-            "type": "object",
-            "properties": {
-                "address": {
-                    "type": "string"
                 },
-                "createdAt": {
-                    "type": "string"
+                "maxAmountPerTrade": {
+                    "type": "number"
                 },
-                "creatorAddress": {
-                    "type": "string"
+                "maxDailyLoss": {
+                    "type": "number"
                 },
-                "liquidity": {
+                "maxHoldMinutes": {
+                    "type": "integer"
+                },
+                "minGoldenDogScore": {
+                    "type": "integer"
+                },
+                "paperSlippage": {
+                    "description": "PaperSlippage is the adverse move applied to paper fills after the\nquote (0.005 = 0.5%).",
                     "type": "number"
                 },
-                "name": {
-                    "type": "string"
+                "stopLoss": {
+                    "type": "number"
                 },
-                "pairAddress": {
-                    "type": "string"
+                "takeProfitAmounts": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
                 },
-                "symbol": {
+                "takeProfitLevels": {
+                    "type": "array",
+                    "items": {
+                        "type": "number"
+                    }
+                },
+                "trailingActivation": {
+                    "description": "TrailingActivation is the profit the position must reach before the trailing stop arms.",
+                    "type": "number"
+                },
+                "trailingStop": {
+                    "description": "TrailingStop exits once price falls this fraction below the peak since entry.",
+                    "type": "number"
+                },
+                "webhookSecret": {
                     "type": "string"
                 }
             }
         },
-        "handler.PeriodStat": {
+        "service.BacktestBucket": {
             "type": "object",
             "properties": {
-                "avgPL": {
-                    "type": "number"
-                },
                 "count": {
                     "type": "integer"
                 },
-                "period": {
+                "label": {
                     "type": "string"
                 },
-                "totalPL": {
+                "max": {
                     "type": "number"
                 },
-                "winRate": {
+                "min": {
                     "type": "number"
                 }
             }
         },
-        "handler.StrategyStat": {
+        "service.BacktestEquityPoint": {
             "type": "object",
             "properties": {
-                "avgPL": {
-                    "type": "number"
-                },
-                "count": {
-                    "type": "integer"
-                },
-                "strategy": {
+                "at": {
                     "type": "string"
                 },
-                "totalPL": {
-                    "type": "number"
-                },
-                "winRate": {
+                "equityBnb": {
                     "type": "number"
                 }
             }
         },
-        "handler.TokenDetailResponse": {
+        "service.BacktestEvent": {
             "type": "object",
             "properties": {
-                "address": {
+                "action": {
+                    "description": "BUY | SELL",
                     "type": "string"
                 },
-                "analysisResult": {
-                    "type": "object",
-                    "additionalProperties": true
+                "amountBnb": {
+                    "description": "AmountBNB is what a BUY spent or a SELL received, net of gas.",
+                    "type": "number"
                 },
-                "analyzedAt": {
+                "at": {
                     "type": "string"
                 },
-                "createdAt": {
+                "priceUsd": {
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "reason": {
                     "type": "string"
                 },
-                "creatorAddress": {
+                "strategy": {
                     "type": "string"
+                }
+            }
+        },
+        "service.BacktestParams": {
+            "type": "object",
+            "properties": {
+                "bnbPriceUsd": {
+                    "description": "BNBPriceUSD converts the USD price and liquidity series into BNB.",
+                    "type": "number"
                 },
-                "dex": {
+                "config": {
+                    "$ref": "#/definitions/service.AutoTradeConfig"
+                },
+                "from": {
                     "type": "string"
                 },
-                "effectiveScore": {
-                    "type": "integer"
+                "gasBnb": {
+                    "description": "GasBNB is charged per swap.",
+                    "type": "number"
                 },
-                "goldenDogScore": {
+                "horizonMinutes": {
+                    "description": "HorizonMinutes bounds how long a position is followed when the config\nhas no MaxHoldMinutes; open positions are closed at the last snapshot.",
                     "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "initialBalanceBnb": {
+                    "description": "InitialBalanceBNB is the starting equity the drawdown is measured on.",
+                    "type": "number"
                 },
-                "isGoldenDog": {
-                    "type": "boolean"
+                "maxSignals": {
+                    "type": "integer"
                 },
-                "liquidity": {
+                "slippage": {
+                    "description": "Slippage is charged on every fill on top of the price impact modeled\nfrom the snapshot's liquidity (0.01 = 1%).",
                     "type": "number"
                 },
-                "name": {
+                "to": {
                     "type": "string"
+                }
+            }
+        },
+        "service.BacktestReport": {
+            "type": "object",
+            "properties": {
+                "distribution": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BacktestBucket"
+                    }
                 },
-                "pairAddress": {
-                    "type": "string"
+                "equity": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BacktestEquityPoint"
+                    }
                 },
-                "phase": {
+                "generatedAt": {
                     "type": "string"
                 },
-                "riskDetails": {
-                    "type": "object",
-                    "additionalProperties": true
-                },
-                "riskLevel": {
-                    "type": "string"
+                "params": {
+                    "$ref": "#/definitions/service.BacktestParams"
                 },
-                "riskScore": {
+                "scoringVersion": {
+                    "description": "ScoringVersion is the scoring model whose phases and decay the replay\nused.",
                     "type": "integer"
                 },
-                "symbol": {
-                    "type": "string"
+                "skipped": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BacktestSkip"
+                    }
                 },
-                "timeDecayFactor": {
-                    "type": "number"
-                }
-            }
-        },
-        "handler.TokenDetailResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/handler.TokenDetailResponse"
-                }
-            }
-        },
-        "handler.TokenListResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
+                "summary": {
+                    "$ref": "#/definitions/service.BacktestSummary"
+                },
+                "trades": {
                     "type": "array",
                     "items": {
-                        "$ref": "#/definitions/handler.TokenResponseDTO"
+                        "$ref": "#/definitions/service.BacktestTrade"
                     }
                 }
             }
         },
-        "handler.TokenResponseDTO": {
+        "service.BacktestSkip": {
             "type": "object",
             "properties": {
-                "address": {
+                "reason": {
                     "type": "string"
                 },
-                "analysis_status": {
+                "signalAt": {
                     "type": "string"
                 },
-                "analyzed_at": {
+                "tokenAddress": {
                     "type": "string"
                 },
-                "buy_tax": {
+                "tokenSymbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.BacktestSummary": {
+            "type": "object",
+            "properties": {
+                "avgReturn": {
                     "type": "number"
                 },
-                "created_at": {
-                    "type": "string"
+                "bestReturn": {
+                    "type": "number"
                 },
-                "creator_address": {
-                    "type": "string"
+                "losses": {
+                    "type": "integer"
                 },
-                "decimals": {
+                "maxDrawdown": {
+                    "type": "number"
+                },
+                "maxDrawdownBnb": {
+                    "description": "MaxDrawdownBNB is the largest peak-to-trough fall of realized equity;\nMaxDrawdown is the same as a ratio of the peak.",
+                    "type": "number"
+                },
+                "medianReturn": {
+                    "type": "number"
+                },
+                "signals": {
                     "type": "integer"
                 },
-                "dex": {
-                    "type": "string"
+                "skipped": {
+                    "type": "integer"
                 },
-                "golden_dog_score": {
+                "totalCostBnb": {
+                    "type": "number"
+                },
+                "totalPnlBnb": {
+                    "type": "number"
+                },
+                "trades": {
                     "type": "integer"
                 },
-                "id": {
-                    "type": "string"
+                "winRate": {
+                    "type": "number"
                 },
-                "initial_liquidity": {
-                    "type": "string"
+                "wins": {
+                    "type": "integer"
                 },
-                "is_golden_dog": {
-                    "type": "boolean"
+                "worstReturn": {
+                    "type": "number"
+                }
+            }
+        },
+        "service.BacktestTrade": {
+            "type": "object",
+            "properties": {
+                "costBnb": {
+                    "type": "number"
                 },
-                "is_honeypot": {
-                    "type": "boolean"
+                "effectiveScore": {
+                    "type": "integer"
                 },
-                "name": {
+                "entryAt": {
                     "type": "string"
                 },
-                "pair_address": {
+                "entryPriceUsd": {
+                    "type": "number"
+                },
+                "events": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.BacktestEvent"
+                    }
+                },
+                "exitAt": {
                     "type": "string"
                 },
-                "risk_level": {
+                "exitPriceUsd": {
+                    "type": "number"
+                },
+                "exitReason": {
                     "type": "string"
                 },
-                "risk_score": {
+                "goldenDogScore": {
                     "type": "integer"
                 },
-                "sell_tax": {
+                "phase": {
+                    "type": "string"
+                },
+                "pnlBnb": {
                     "type": "number"
                 },
-                "symbol": {
+                "proceedsBnb": {
+                    "type": "number"
+                },
+                "return": {
+                    "type": "number"
+                },
+                "signalAt": {
+                    "type": "string"
+                },
+                "tokenAddress": {
                     "type": "string"
                 },
-                "updated_at": {
+                "tokenSymbol": {
                     "type": "string"
                 }
             }
         },
-        "handler.TokenResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "$ref": "#/definitions/handler.TokenResponseDTO"
-                }
-            }
-        },
-        "handler.TradeListResponseEnvelope": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/model.Trade"
-                    }
-                }
-            }
-        },
-        "handler.TradeResponseEnvelope": {
+        "service.CalibrationBucket": {
             "type": "object",
             "properties": {
-                "data": {
-                    "$ref": "#/definitions/model.Trade"
+                "maxScore": {
+                    "type": "integer"
+                },
+                "minScore": {
+                    "type": "integer"
+                },
+                "range": {
+                    "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/service.CalibrationStats"
+                },
+                "threshold": {
+                    "$ref": "#/definitions/service.CalibrationStats"
                 }
             }
         },
-        "handler.TradeStatusUpdateRequest": {
+        "service.CalibrationLevel": {
             "type": "object",
             "properties": {
-                "status": {
+                "riskLevel": {
                     "type": "string"
+                },
+                "stats": {
+                    "$ref": "#/definitions/service.CalibrationStats"
                 }
             }
         },
-        "handler.TradeStatusUpdateResponseEnvelope": {
+        "service.CalibrationPoint": {
             "type": "object",
             "properties": {
-                "data": {
-                    "type": "object",
-                    "additionalProperties": {
-                        "type": "string"
-                    }
+                "count": {
+                    "type": "integer"
+                },
+                "observed": {
+                    "type": "number"
+                },
+                "predicted": {
+                    "type": "number"
+                },
+                "range": {
+                    "type": "string"
                 }
             }
         },
-        "handler.UpsertTokenPriceSnapshotPayload": {
+        "service.CalibrationReport": {
             "type": "object",
             "properties": {
-                "liquidityUsd": {
+                "agentId": {
+                    "type": "string"
+                },
+                "baseRate": {
+                    "description": "BaseRate is the good outcome share over every sample.",
                     "type": "number"
                 },
-                "priceUsd": {
+                "brierScore": {
+                    "description": "BrierScore is the mean squared error of scores read as probabilities.",
                     "type": "number"
                 },
-                "tokenAddress": {
+                "bucketSize": {
+                    "type": "integer"
+                },
+                "buckets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CalibrationBucket"
+                    }
+                },
+                "calibrationCurve": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CalibrationPoint"
+                    }
+                },
+                "from": {
                     "type": "string"
                 },
-                "ts": {
+                "goldenDogCalls": {
+                    "$ref": "#/definitions/service.CalibrationStats"
+                },
+                "goodOutcome": {
                     "type": "string"
                 },
-                "volume5mUsd": {
-                    "type": "number"
-                }
-            }
-        },
-        "handler.WalletBalanceResponse": {
-            "type": "object",
-            "properties": {
-                "address": {
+                "horizon": {
                     "type": "string"
                 },
-                "balance": {
-                    "type": "number"
+                "modelVersion": {
+                    "type": "string"
                 },
-                "userId": {
+                "overall": {
+                    "$ref": "#/definitions/service.CalibrationStats"
+                },
+                "riskLevels": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.CalibrationLevel"
+                    }
+                },
+                "source": {
                     "type": "string"
-                }
-            }
-        },
-        "handler.WalletConfigRequest": {
-            "type": "object",
-            "properties": {
-                "config": {
-                    "type": "object",
-                    "additionalProperties": true
                 },
-                "userId": {
+                "to": {
                     "type": "string"
                 }
             }
         },
-        "handler.WithdrawRequest": {
+        "service.CalibrationStats": {
             "type": "object",
             "properties": {
-                "amount": {
+                "count": {
+                    "type": "integer"
+                },
+                "good": {
+                    "type": "integer"
+                },
+                "medianMaxReturn": {
                     "type": "number"
                 },
-                "userId": {
-                    "type": "string"
+                "precision": {
+                    "type": "number"
+                },
+                "recall": {
+                    "type": "number"
+                },
+                "rugRate": {
+                    "type": "number"
                 }
             }
         },
-        "handler.WithdrawResponse": {
+        "service.Consensus": {
             "type": "object",
             "properties": {
-                "address": {
+                "agents": {
+                    "type": "integer"
+                },
+                "computedAt": {
                     "type": "string"
                 },
-                "balance": {
+                "disagreement": {
+                    "type": "boolean"
+                },
+                "disagreementReasons": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "goldenDogScore": {
+                    "type": "integer"
+                },
+                "goldenDogVote": {
+                    "description": "GoldenDogVote is the weighted share of agents calling a golden dog.",
                     "type": "number"
                 },
-                "userId": {
+                "honeypotAgents": {
+                    "type": "integer"
+                },
+                "honeypotVote": {
+                    "type": "number"
+                },
+                "isGoldenDog": {
+                    "type": "boolean"
+                },
+                "isHoneypot": {
+                    "description": "IsHoneypot is a weighted majority of the agents that assessed the\nhoneypot risk, HoneypotAgents of them; HIGH counts as a honeypot call.",
+                    "type": "boolean"
+                },
+                "riskLevel": {
                     "type": "string"
+                },
+                "riskScore": {
+                    "type": "integer"
+                },
+                "verdicts": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.AgentVerdict"
+                    }
                 }
             }
         },
-        "model.AITrade": {
+        "service.PortfolioValuation": {
             "type": "object",
             "properties": {
-                "amount_in": {
-                    "type": "string"
+                "bnb_price_usd": {
+                    "type": "number"
                 },
-                "amount_out": {
-                    "type": "string"
+                "cash_bnb": {
+                    "type": "number"
                 },
-                "block_number": {
-                    "type": "integer"
+                "cost_bnb": {
+                    "type": "number"
                 },
-                "current_value": {
-                    "type": "string"
+                "positions": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/service.PositionValuation"
+                    }
                 },
-                "decision_reason": {
-                    "type": "string"
+                "positions_bnb": {
+                    "type": "number"
                 },
-                "error_message": {
-                    "type": "string"
+                "total_bnb": {
+                    "type": "number"
                 },
-                "gas_used": {
-                    "type": "string"
+                "total_usd": {
+                    "type": "number"
                 },
-                "golden_dog_score": {
-                    "type": "integer"
+                "unrealized_pl": {
+                    "type": "number"
                 },
-                "id": {
+                "unrealized_pl_bnb": {
+                    "type": "number"
+                },
+                "user_id": {
                     "type": "string"
                 },
-                "profit_loss": {
+                "valued_at": {
+                    "type": "string"
+                }
+            }
+        },
+        "service.PositionValuation": {
+            "type": "object",
+            "properties": {
+                "cost_bnb": {
                     "type": "number"
                 },
-                "status": {
-                    "description": "pending | success | failed",
-                    "type": "string"
+                "entry_price_bnb": {
+                    "type": "number"
                 },
-                "strategy_used": {
+                "error": {
                     "type": "string"
                 },
-                "timestamp": {
+                "marked_at": {
                     "type": "string"
                 },
+                "price_bnb": {
+                    "type": "number"
+                },
+                "price_change": {
+                    "description": "spot price against entry price",
+                    "type": "number"
+                },
+                "quantity": {
+                    "type": "number"
+                },
                 "token_address": {
                     "type": "string"
                 },
                 "token_symbol": {
                     "type": "string"
                 },
-                "tx_hash": {
-                    "type": "string"
+                "unrealized_pl": {
+                    "description": "ratio of cost",
+                    "type": "number"
                 },
-                "type": {
-                    "description": "BUY | SELL",
-                    "type": "string"
+                "unrealized_pl_bnb": {
+                    "type": "number"
                 },
-                "user_id": {
-                    "type": "string"
+                "value_bnb": {
+                    "description": "ValueBNB is what selling the whole position would return now.",
+                    "type": "number"
+                },
+                "value_usd": {
+                    "type": "number"
                 }
             }
         },
-        "model.Trade": {
+        "service.SpendingPolicy": {
             "type": "object",
             "properties": {
-                "amount_in": {
-                    "type": "number"
+                "allowedContracts": {
+                    "description": "AllowedContracts extends the set of contracts that may be called or approved as spenders.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "amount_out": {
-                    "type": "number"
+                "allowedRouters": {
+                    "description": "AllowedRouters restricts swap routers and approval spenders.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "created_at": {
-                    "type": "string"
+                "maxOpenPositions": {
+                    "type": "integer"
                 },
-                "gas_used": {
+                "maxPriceImpact": {
                     "type": "number"
                 },
-                "id": {
-                    "type": "string"
+                "maxSellTax": {
+                    "type": "number"
                 },
-                "status": {
-                    "description": "pending, success, failed",
-                    "type": "string"
+                "maxSlippage": {
+                    "type": "number"
                 },
-                "token_address": {
-                    "type": "string"
+                "maxTokenExposureBnb": {
+                    "type": "number"
                 },
-                "token_symbol": {
-                    "type": "string"
+                "maxWithdrawalBnb": {
+                    "type": "number"
                 },
-                "tx_hash": {
-                    "type": "string"
+                "tokenAllowlist": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "type": {
-                    "description": "buy, sell",
-                    "type": "string"
+                "tokenDenylist": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 },
-                "user_address": {
-                    "type": "string"
+                "tradingHours": {
+                    "description": "TradingHours are UTC windows such as \"08:00-22:00\"; windows may wrap midnight.\nThey gate buys and withdrawals only, so exits are never blocked.",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
                 }
             }
         }
@@ -19,6 +19,18 @@ type Config struct {
 	ApiUserID          string
 	ApiHmacSecret      string
 	CorsAllowedOrigins []string
+	// AdminApiKey guards the admin endpoints, which answer 503 while it is
+	// unset.
+	AdminApiKey string
+	// AgentApiKeys maps agent IDs to their own API keys. An agent is known
+	// by the key it presents; the shared ApiKey stands for the default agent.
+	AgentApiKeys map[string]string
+
+	BreakerMaxFailures     int
+	BreakerLossRate        float64
+	BreakerLossWindow      int
+	BreakerRPCFailures     int
+	BreakerRPCMaxLatencyMs int
 }
 
 func Load() (*Config, error) {
@@ -43,6 +55,13 @@ func Load() (*Config, error) {
 	v.SetDefault("api_user_id", "")
 	v.SetDefault("api_hmac_secret", "")
 	v.SetDefault("cors_allowed_origins", "http://localhost:3000")
+	v.SetDefault("admin_api_key", "")
+	v.SetDefault("agent_api_keys", "")
+	v.SetDefault("breaker_max_failures", 3)
+	v.SetDefault("breaker_loss_rate", 0.8)
+	v.SetDefault("breaker_loss_window", 10)
+	v.SetDefault("breaker_rpc_failures", 5)
+	v.SetDefault("breaker_rpc_max_latency_ms", 5000)
 
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	v.AutomaticEnv()
@@ -55,12 +74,23 @@ func Load() (*Config, error) {
 	_ = v.BindEnv("api_user_id", "api_user_id", "EASYMEME_USER_ID")
 	_ = v.BindEnv("api_hmac_secret", "api_hmac_secret", "EASYMEME_API_HMAC_SECRET")
 	_ = v.BindEnv("cors_allowed_origins", "cors_allowed_origins", "CORS_ALLOWED_ORIGINS")
+	_ = v.BindEnv("admin_api_key", "admin_api_key", "EASYMEME_ADMIN_API_KEY")
+	_ = v.BindEnv("agent_api_keys", "agent_api_keys", "EASYMEME_AGENT_API_KEYS")
+	_ = v.BindEnv("breaker_max_failures", "breaker_max_failures", "BREAKER_MAX_FAILURES")
+	_ = v.BindEnv("breaker_loss_rate", "breaker_loss_rate", "BREAKER_LOSS_RATE")
+	_ = v.BindEnv("breaker_loss_window", "breaker_loss_window", "BREAKER_LOSS_WINDOW")
+	_ = v.BindEnv("breaker_rpc_failures", "breaker_rpc_failures", "BREAKER_RPC_FAILURES")
+	_ = v.BindEnv("breaker_rpc_max_latency_ms", "breaker_rpc_max_latency_ms", "BREAKER_RPC_MAX_LATENCY_MS")
 
 	if err := v.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return nil, fmt.Errorf("load config: %w", err)
 		}
 	}
+	agentKeys, err := splitAgentKeys(v.GetString("agent_api_keys"))
+	if err != nil {
+		return nil, err
+	}
 
 	return &Config{
 		Port:               v.GetString("port"),
@@ -72,6 +102,14 @@ func Load() (*Config, error) {
 		ApiUserID:          v.GetString("api_user_id"),
 		ApiHmacSecret:      v.GetString("api_hmac_secret"),
 		CorsAllowedOrigins: splitOrigins(v.GetString("cors_allowed_origins")),
+		AdminApiKey:        v.GetString("admin_api_key"),
+		AgentApiKeys:       agentKeys,
+
+		BreakerMaxFailures:     v.GetInt("breaker_max_failures"),
+		BreakerLossRate:        v.GetFloat64("breaker_loss_rate"),
+		BreakerLossWindow:      v.GetInt("breaker_loss_window"),
+		BreakerRPCFailures:     v.GetInt("breaker_rpc_failures"),
+		BreakerRPCMaxLatencyMs: v.GetInt("breaker_rpc_max_latency_ms"),
 	}, nil
 }
 
@@ -86,3 +124,27 @@ func splitOrigins(raw string) []string {
 	}
 	return origins
 }
+
+// splitAgentKeys parses "agent=key,agent=key". Every agent and every key must
+// be unique.
+func splitAgentKeys(raw string) (map[string]string, error) {
+	keys := map[string]string{}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		agentID, key, ok := strings.Cut(part, "=")
+		agentID, key = strings.TrimSpace(agentID), strings.TrimSpace(key)
+		if !ok || agentID == "" || key == "" {
+			return nil, fmt.Errorf("agent_api_keys: expected agent=key, got %q", part)
+		}
+		if _, dup := keys[agentID]; dup || seen[key] {
+			return nil, fmt.Errorf("agent_api_keys: duplicate agent or key for %q", agentID)
+		}
+		keys[agentID] = key
+		seen[key] = true
+	}
+	return keys, nil
+}
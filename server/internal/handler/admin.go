@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+	"easymeme/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AdminHandler struct {
+	repo    *repository.Repository
+	breaker *service.CircuitBreaker
+	scoring *service.ScoringModels
+}
+
+func NewAdminHandler(repo *repository.Repository, breaker *service.CircuitBreaker, scoring *service.ScoringModels) *AdminHandler {
+	return &AdminHandler{repo: repo, breaker: breaker, scoring: scoring}
+}
+
+type TradingHaltsResponse struct {
+	Active  []model.TradingHalt `json:"active"`
+	History []model.TradingHalt `json:"history"`
+}
+
+// GetTradingHalts godoc
+// @Summary List trading halts
+// @Description Active kill switches and circuit breakers plus recent history
+// @Tags admin
+// @Param limit query int false "History limit" default(50)
+// @Success 200 {object} map[string]TradingHaltsResponse
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/trading-halts [get]
+func (h *AdminHandler) GetTradingHalts(c *gin.Context) {
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	history, err := h.repo.ListTradingHalts(c.Request.Context(), limit)
+	if err != nil {
+		log.Printf("list trading halts: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": TradingHaltsResponse{Active: h.breaker.ActiveHalts(), History: history}})
+}
+
+type KillSwitchRequest struct {
+	// UserID limits the kill switch to one user; empty halts all trading.
+	UserID string `json:"userId"`
+	Reason string `json:"reason"`
+	Actor  string `json:"actor"`
+}
+
+// EngageKillSwitch godoc
+// @Summary Engage kill switch
+// @Description Block ExecuteTrade and every automated execution globally or for one user until reset
+// @Tags admin
+// @Param payload body KillSwitchRequest true "Kill switch payload"
+// @Success 200 {object} map[string]model.TradingHalt
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/kill-switch [post]
+func (h *AdminHandler) EngageKillSwitch(c *gin.Context) {
+	var req KillSwitchRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Actor) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	halt, err := h.breaker.Trip(c.Request.Context(), haltScope(req.UserID), service.HaltKindKillSwitch, req.Reason, req.Actor)
+	if err != nil {
+		log.Printf("engage kill switch: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to engage kill switch"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": halt})
+}
+
+type ResetHaltRequest struct {
+	// UserID selects a per-user halt; empty resets global halts.
+	UserID string `json:"userId"`
+	// Kind resets one kill switch or breaker; empty resets all of the scope.
+	Kind  string `json:"kind"`
+	Actor string `json:"actor"`
+}
+
+// ResetTradingHalt godoc
+// @Summary Reset kill switch or breaker
+// @Description Release a kill switch or reset tripped circuit breakers; breakers never reset on their own
+// @Tags admin
+// @Param payload body ResetHaltRequest true "Reset payload"
+// @Success 200 {object} map[string]int64
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/trading-halts/reset [post]
+func (h *AdminHandler) ResetTradingHalt(c *gin.Context) {
+	var req ResetHaltRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Actor) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if req.Kind != "" && !service.IsValidHaltKind(req.Kind) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid kind"})
+		return
+	}
+	n, err := h.breaker.Reset(c.Request.Context(), haltScope(req.UserID), req.Kind, req.Actor)
+	if err != nil {
+		log.Printf("reset trading halt: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reset"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"reset": n}})
+}
+
+func haltScope(userID string) string {
+	if userID = strings.TrimSpace(userID); userID != "" {
+		return service.UserHaltScope(userID)
+	}
+	return service.HaltScopeGlobal
+}
@@ -0,0 +1,120 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"easymeme/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type ScoringModelsResponse struct {
+	Active   *model.ScoringConfig `json:"active"`
+	Versions []model.ScoringModel `json:"versions"`
+}
+
+// GetScoringModels godoc
+// @Summary List scoring model versions
+// @Description The scoring config in effect and the stored versions, newest first
+// @Tags admin
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {object} map[string]ScoringModelsResponse
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/scoring-models [get]
+func (h *AdminHandler) GetScoringModels(c *gin.Context) {
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	versions, err := h.repo.ListScoringModels(c.Request.Context(), limit)
+	if err != nil {
+		log.Printf("list scoring models: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": ScoringModelsResponse{Active: h.scoring.Current(), Versions: versions}})
+}
+
+type PublishScoringModelRequest struct {
+	// Config holds only the fields that differ from the built-in defaults.
+	Config   json.RawMessage `json:"config" swaggertype:"object"`
+	Note     string          `json:"note"`
+	Actor    string          `json:"actor"`
+	Activate bool            `json:"activate"`
+}
+
+// PublishScoringModel godoc
+// @Summary Publish scoring model version
+// @Description Validate a scoring config and store it as the next version, optionally activating it on every instance
+// @Tags admin
+// @Param payload body PublishScoringModelRequest true "Scoring model"
+// @Success 200 {object} map[string]model.ScoringModel
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/scoring-models [post]
+func (h *AdminHandler) PublishScoringModel(c *gin.Context) {
+	var req PublishScoringModelRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.Actor) == "" || len(req.Config) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if _, err := model.ParseScoringConfig(req.Config); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid config: " + err.Error()})
+		return
+	}
+	m, err := h.scoring.Publish(c.Request.Context(), req.Config, req.Note, req.Actor, req.Activate)
+	if err != nil {
+		log.Printf("publish scoring model: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to publish scoring model"})
+		return
+	}
+	log.Printf("scoring model version=%d published by %s activate=%t", m.Version, req.Actor, req.Activate)
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
+
+type ActivateScoringModelRequest struct {
+	Actor string `json:"actor"`
+}
+
+// ActivateScoringModel godoc
+// @Summary Activate scoring model version
+// @Description Switch every instance to a stored scoring model version, for example to roll back
+// @Tags admin
+// @Param version path int true "Version"
+// @Param payload body ActivateScoringModelRequest true "Activation payload"
+// @Success 200 {object} map[string]model.ScoringModel
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/admin/scoring-models/{version}/activate [post]
+func (h *AdminHandler) ActivateScoringModel(c *gin.Context) {
+	version, err := strconv.Atoi(c.Param("version"))
+	var req ActivateScoringModelRequest
+	if err != nil || c.ShouldBindJSON(&req) != nil || strings.TrimSpace(req.Actor) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	m, err := h.scoring.Activate(c.Request.Context(), version)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "scoring model not found"})
+		return
+	}
+	if err != nil {
+		log.Printf("activate scoring model: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to activate scoring model"})
+		return
+	}
+	log.Printf("scoring model version=%d activated by %s", m.Version, req.Actor)
+	c.JSON(http.StatusOK, gin.H{"data": m})
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"easymeme/internal/model"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Webhook secrets shorter than this are rejected; an empty one is generated.
+const minSubscriptionSecretLen = 16
+
+// PendingTokenPayload renders a token as GetPendingTokens serves it, for
+// pushing to agent subscriptions.
+func PendingTokenPayload(token model.Token) interface{} {
+	return toPendingTokenResponse(token)
+}
+
+type AgentSubscriptionRequest struct {
+	AgentID     string  `json:"agentId"`
+	URL         string  `json:"url"`
+	Secret      string  `json:"secret"`
+	MinPriority float64 `json:"minPriority"`
+}
+
+type RemoveAgentSubscriptionRequest struct {
+	AgentID string `json:"agentId"`
+	ID      string `json:"id"`
+}
+
+// AgentSubscriptionCreatedResponse is the only response that includes the
+// webhook secret.
+type AgentSubscriptionCreatedResponse struct {
+	model.AgentSubscription
+	Secret string `json:"secret"`
+}
+
+type AgentSubscriptionResponseEnvelope struct {
+	Data AgentSubscriptionCreatedResponse `json:"data"`
+}
+
+type AgentSubscriptionListResponseEnvelope struct {
+	Data []model.AgentSubscription `json:"data"`
+}
+
+// GetAgentSubscriptions godoc
+// @Summary List agent subscriptions
+// @Description List the enriched-token webhooks of the calling agent. Without API keys configured the agent is named by X-Agent-Id or agentId, and every agent is listed when none is given.
+// @Tags tokens
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys"
+// @Param agentId query string false "Agent identity when the header is absent"
+// @Success 200 {object} AgentSubscriptionListResponseEnvelope
+// @Failure 500 {object} map[string]string
+// @Router /api/agent-subscriptions [get]
+func (h *TokenHandler) GetAgentSubscriptions(c *gin.Context) {
+	agentID := c.GetString(AgentIDContextKey)
+	if agentID == "" {
+		agentID = strings.TrimSpace(c.GetHeader("X-Agent-Id"))
+	}
+	if agentID == "" {
+		agentID = strings.TrimSpace(c.Query("agentId"))
+	}
+	subs, err := h.repo.ListAgentSubscriptions(c.Request.Context(), agentID)
+	if err != nil {
+		log.Printf("list agent subscriptions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": subs})
+}
+
+// CreateAgentSubscription godoc
+// @Summary Subscribe to enriched tokens
+// @Description Register a webhook that receives every token entering the analysis queue with at least minPriority, as {"type":"token_enriched","data":PendingTokenResponse}, unless another agent holds its lease. Bodies are signed with HMAC-SHA256 of secret in X-EasyMeme-Signature; a secret of at least 16 characters is required, or one is generated and returned only in this response. Failed deliveries are retried with backoff.
+// @Tags tokens
+// @Accept json
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides"
+// @Param payload body AgentSubscriptionRequest true "Subscription"
+// @Success 200 {object} AgentSubscriptionResponseEnvelope
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/agent-subscriptions [post]
+func (h *TokenHandler) CreateAgentSubscription(c *gin.Context) {
+	var req AgentSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || !validWebhookURL(req.URL) || req.MinPriority < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	secret := strings.TrimSpace(req.Secret)
+	if secret == "" {
+		generated, err := generateSubscriptionSecret()
+		if err != nil {
+			log.Printf("generate subscription secret: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		secret = generated
+	} else if len(secret) < minSubscriptionSecretLen {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "secret must be at least 16 characters"})
+		return
+	}
+	sub := &model.AgentSubscription{
+		AgentID:     requestAgentID(c, req.AgentID),
+		URL:         strings.TrimSpace(req.URL),
+		Secret:      secret,
+		MinPriority: req.MinPriority,
+		Active:      true,
+	}
+	if err := h.repo.CreateAgentSubscription(c.Request.Context(), sub); err != nil {
+		log.Printf("create agent subscription: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": AgentSubscriptionCreatedResponse{AgentSubscription: *sub, Secret: secret}})
+}
+
+// RemoveAgentSubscription godoc
+// @Summary Remove agent subscription
+// @Description Stop pushing enriched tokens to a webhook
+// @Tags tokens
+// @Accept json
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides"
+// @Param payload body RemoveAgentSubscriptionRequest true "Subscription"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/agent-subscriptions/remove [post]
+func (h *TokenHandler) RemoveAgentSubscription(c *gin.Context) {
+	var req RemoveAgentSubscriptionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.ID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	removed, err := h.repo.DeleteAgentSubscription(c.Request.Context(), req.ID, requestAgentID(c, req.AgentID))
+	if err != nil {
+		log.Printf("delete agent subscription: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "subscription not found"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+func validWebhookURL(raw string) bool {
+	u, err := url.Parse(strings.TrimSpace(raw))
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return u.Scheme == "http" || u.Scheme == "https"
+}
+
+func generateSubscriptionSecret() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(buf), nil
+}
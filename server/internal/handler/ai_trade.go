@@ -4,11 +4,13 @@ import (
 	"log"
 	"net/http"
 	"strconv"
+	"strings"
 
 	"easymeme/internal/model"
 	"easymeme/internal/repository"
 
 	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
 )
 
 type AITradeHandler struct {
@@ -23,19 +25,25 @@ type AITradeListResponseEnvelope struct {
 	Data []model.AITrade `json:"data"`
 }
 
+// CreateAITradeRequest amounts are in display units and accept JSON strings
+// or numbers; strings keep full precision.
 type CreateAITradeRequest struct {
-	UserID         string  `json:"userId"`
-	TokenAddress   string  `json:"tokenAddress"`
-	TokenSymbol    string  `json:"tokenSymbol"`
-	Type           string  `json:"type"`
-	AmountIn       string  `json:"amountIn"`
-	AmountOut      string  `json:"amountOut"`
-	TxHash         string  `json:"txHash"`
-	GoldenDogScore int     `json:"goldenDogScore"`
-	DecisionReason string  `json:"decisionReason"`
-	StrategyUsed   string  `json:"strategyUsed"`
-	CurrentValue   string  `json:"currentValue"`
-	ProfitLoss     float64 `json:"profitLoss"`
+	UserID       string          `json:"userId"`
+	TokenAddress string          `json:"tokenAddress"`
+	TokenSymbol  string          `json:"tokenSymbol"`
+	Type         string          `json:"type"`
+	AmountIn     decimal.Decimal `json:"amountIn" swaggertype:"string"`
+	AmountOut    decimal.Decimal `json:"amountOut" swaggertype:"string"`
+	// TokenDecimals defaults to the indexed token's decimals, else 18.
+	TokenDecimals  int             `json:"tokenDecimals"`
+	TxHash         string          `json:"txHash"`
+	GoldenDogScore int             `json:"goldenDogScore"`
+	DecisionReason string          `json:"decisionReason"`
+	StrategyUsed   string          `json:"strategyUsed"`
+	CurrentValue   decimal.Decimal `json:"currentValue" swaggertype:"string"`
+	ProfitLoss     decimal.Decimal `json:"profitLoss" swaggertype:"number"`
+	// Simulated records a paper trade.
+	Simulated bool `json:"simulated"`
 }
 
 type AITradeResponseEnvelope struct {
@@ -58,6 +66,18 @@ func (h *AITradeHandler) CreateAITrade(c *gin.Context) {
 		return
 	}
 
+	decimals := req.TokenDecimals
+	if decimals <= 0 {
+		decimals = 18
+		if token, err := h.repo.GetTokenByAddress(c.Request.Context(), req.TokenAddress); err == nil && token.Decimals > 0 {
+			decimals = token.Decimals
+		}
+	}
+	inDecimals, outDecimals := 18, decimals
+	if strings.EqualFold(req.Type, "SELL") {
+		inDecimals, outDecimals = decimals, 18
+	}
+
 	trade := &model.AITrade{
 		UserID:         req.UserID,
 		TokenAddress:   req.TokenAddress,
@@ -65,12 +85,16 @@ func (h *AITradeHandler) CreateAITrade(c *gin.Context) {
 		Type:           req.Type,
 		AmountIn:       req.AmountIn,
 		AmountOut:      req.AmountOut,
+		AmountInWei:    toBaseUnits(req.AmountIn, inDecimals),
+		AmountOutWei:   toBaseUnits(req.AmountOut, outDecimals),
+		TokenDecimals:  decimals,
 		TxHash:         req.TxHash,
 		GoldenDogScore: req.GoldenDogScore,
 		DecisionReason: req.DecisionReason,
 		StrategyUsed:   req.StrategyUsed,
 		CurrentValue:   req.CurrentValue,
 		ProfitLoss:     req.ProfitLoss,
+		Simulated:      req.Simulated,
 	}
 	if err := h.repo.CreateAITrade(c.Request.Context(), trade); err != nil {
 		log.Printf("create ai trade: %v", err)
@@ -83,9 +107,10 @@ func (h *AITradeHandler) CreateAITrade(c *gin.Context) {
 
 // GetAITrades godoc
 // @Summary Get AI trades
-// @Description List AI trades
+// @Description List AI trades; paper trades are flagged simulated
 // @Tags ai-trades
 // @Param limit query int false "Limit" default(50)
+// @Param mode query string false "live, paper or all" default(all)
 // @Success 200 {object} AITradeListResponseEnvelope
 // @Failure 500 {object} map[string]string
 // @Router /api/ai-trades [get]
@@ -96,7 +121,7 @@ func (h *AITradeHandler) GetAITrades(c *gin.Context) {
 			limit = parsed
 		}
 	}
-	trades, err := h.repo.GetAITrades(c.Request.Context(), limit)
+	trades, err := h.repo.GetAITrades(c.Request.Context(), limit, tradeMode(c, "all"))
 	if err != nil {
 		log.Printf("get ai trades: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
@@ -106,6 +131,7 @@ func (h *AITradeHandler) GetAITrades(c *gin.Context) {
 }
 
 type AITradeStatsResponse struct {
+	Mode       string         `json:"mode"`
 	Count      int64          `json:"count"`
 	WinRate    float64        `json:"winRate"`
 	AvgPL      float64        `json:"avgPL"`
@@ -132,42 +158,54 @@ type PeriodStat struct {
 
 // GetAITradeStats godoc
 // @Summary Get AI trade stats
-// @Description Aggregate AI trade statistics
+// @Description Aggregate AI trade statistics; paper trades are excluded unless mode is paper or all
 // @Tags ai-trades
+// @Param mode query string false "live, paper or all" default(live)
 // @Success 200 {object} map[string]AITradeStatsResponse
 // @Failure 500 {object} map[string]string
 // @Router /api/ai-trades/stats [get]
 func (h *AITradeHandler) GetAITradeStats(c *gin.Context) {
-	count, winRate, avgPL, err := h.repo.GetAITradeStats(c.Request.Context())
+	mode := tradeMode(c, model.WalletModeLive)
+	count, winRate, avgPL, err := h.repo.GetAITradeStats(c.Request.Context(), mode)
 	if err != nil {
 		log.Printf("get ai trade stats: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
 
-	trades, _ := h.repo.GetAllAITrades(c.Request.Context())
+	trades, _ := h.repo.GetAllAITrades(c.Request.Context(), mode)
 	byStrategy := aggregateByStrategy(trades)
 	byPeriod := aggregateByPeriod(trades)
-	totalPL := 0.0
+	totalPL := decimal.Zero
 	for _, t := range trades {
-		totalPL += t.ProfitLoss
+		totalPL = totalPL.Add(t.ProfitLoss)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": AITradeStatsResponse{
+		Mode:       mode,
 		Count:      count,
 		WinRate:    winRate,
 		AvgPL:      avgPL,
-		TotalPL:    totalPL,
+		TotalPL:    totalPL.InexactFloat64(),
 		ByStrategy: byStrategy,
 		ByPeriod:   byPeriod,
 	}})
 }
 
+// tradeMode reads the mode query parameter: live, paper or all.
+func tradeMode(c *gin.Context, fallback string) string {
+	switch mode := strings.ToLower(strings.TrimSpace(c.Query("mode"))); mode {
+	case model.WalletModeLive, model.WalletModePaper, "all":
+		return mode
+	}
+	return fallback
+}
+
 func aggregateByStrategy(trades []model.AITrade) []StrategyStat {
 	type agg struct {
 		count int64
 		wins  int64
-		sumPL float64
+		sumPL decimal.Decimal
 	}
 	m := map[string]*agg{}
 	for _, t := range trades {
@@ -179,10 +217,10 @@ func aggregateByStrategy(trades []model.AITrade) []StrategyStat {
 			m[key] = &agg{}
 		}
 		m[key].count++
-		if t.ProfitLoss > 0 {
+		if t.ProfitLoss.IsPositive() {
 			m[key].wins++
 		}
-		m[key].sumPL += t.ProfitLoss
+		m[key].sumPL = m[key].sumPL.Add(t.ProfitLoss)
 	}
 	out := make([]StrategyStat, 0, len(m))
 	for key, a := range m {
@@ -192,14 +230,14 @@ func aggregateByStrategy(trades []model.AITrade) []StrategyStat {
 		}
 		avg := 0.0
 		if a.count > 0 {
-			avg = a.sumPL / float64(a.count)
+			avg = a.sumPL.Div(decimal.NewFromInt(a.count)).InexactFloat64()
 		}
 		out = append(out, StrategyStat{
 			Strategy: key,
 			Count:    a.count,
 			WinRate:  winRate,
 			AvgPL:    avg,
-			TotalPL:  a.sumPL,
+			TotalPL:  a.sumPL.InexactFloat64(),
 		})
 	}
 	return out
@@ -209,7 +247,7 @@ func aggregateByPeriod(trades []model.AITrade) []PeriodStat {
 	type agg struct {
 		count int64
 		wins  int64
-		sumPL float64
+		sumPL decimal.Decimal
 	}
 	m := map[string]*agg{}
 	for _, t := range trades {
@@ -218,10 +256,10 @@ func aggregateByPeriod(trades []model.AITrade) []PeriodStat {
 			m[period] = &agg{}
 		}
 		m[period].count++
-		if t.ProfitLoss > 0 {
+		if t.ProfitLoss.IsPositive() {
 			m[period].wins++
 		}
-		m[period].sumPL += t.ProfitLoss
+		m[period].sumPL = m[period].sumPL.Add(t.ProfitLoss)
 	}
 	out := make([]PeriodStat, 0, len(m))
 	for period, a := range m {
@@ -231,14 +269,14 @@ func aggregateByPeriod(trades []model.AITrade) []PeriodStat {
 		}
 		avg := 0.0
 		if a.count > 0 {
-			avg = a.sumPL / float64(a.count)
+			avg = a.sumPL.Div(decimal.NewFromInt(a.count)).InexactFloat64()
 		}
 		out = append(out, PeriodStat{
 			Period:  period,
 			Count:   a.count,
 			WinRate: winRate,
 			AvgPL:   avg,
-			TotalPL: a.sumPL,
+			TotalPL: a.sumPL.InexactFloat64(),
 		})
 	}
 	return out
@@ -0,0 +1,112 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"easymeme/internal/repository"
+	"easymeme/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBacktestSignals bounds a synchronous backtest request.
+const maxBacktestSignals = 500
+
+type BacktestHandler struct {
+	repo       *repository.Repository
+	backtester *service.Backtester
+	valuator   *service.Valuator
+}
+
+func NewBacktestHandler(repo *repository.Repository, valuator *service.Valuator) *BacktestHandler {
+	return &BacktestHandler{
+		repo:       repo,
+		backtester: service.NewBacktester(repo),
+		valuator:   valuator,
+	}
+}
+
+type BacktestRequest struct {
+	From time.Time `json:"from"`
+	To   time.Time `json:"to"`
+	// Config is the auto-trade config to test; when omitted the stored config
+	// of UserID is used.
+	Config *service.AutoTradeConfig `json:"config"`
+	UserID string                   `json:"userId"`
+	// Slippage is applied to every fill on top of modeled price impact (default 0.01).
+	Slippage float64 `json:"slippage"`
+	GasBNB   float64 `json:"gasBnb"`
+	// BNBPriceUSD defaults to the current WBNB/USDT price.
+	BNBPriceUSD       float64 `json:"bnbPriceUsd"`
+	InitialBalanceBNB float64 `json:"initialBalanceBnb"`
+	HorizonMinutes    int     `json:"horizonMinutes"`
+	// MaxSignals defaults to and is capped at 500; each signal reads its own
+	// snapshot series.
+	MaxSignals int `json:"maxSignals"`
+}
+
+// RunBacktest godoc
+// @Summary Run backtest
+// @Description Replay the first golden dog verdict on each token stored between from and to, as it stood then, against stored price and liquidity snapshots, applying the config's entry, take-profit, stop-loss and trailing rules with modeled tax and slippage
+// @Tags backtest
+// @Param payload body BacktestRequest true "Backtest payload"
+// @Success 200 {object} map[string]service.BacktestReport
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/backtest [post]
+func (h *BacktestHandler) RunBacktest(c *gin.Context) {
+	var req BacktestRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	params := service.BacktestParams{
+		From:              req.From,
+		To:                req.To,
+		Slippage:          req.Slippage,
+		GasBNB:            req.GasBNB,
+		BNBPriceUSD:       req.BNBPriceUSD,
+		InitialBalanceBNB: req.InitialBalanceBNB,
+		HorizonMinutes:    req.HorizonMinutes,
+		MaxSignals:        min(req.MaxSignals, maxBacktestSignals),
+	}
+	if req.Config != nil {
+		params.Config = *req.Config
+	} else if userID := strings.TrimSpace(req.UserID); userID != "" {
+		cfg, err := service.LoadAutoTradeConfig(ctx, h.repo, userID)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "config not found"})
+			return
+		}
+		params.Config = cfg
+	} else {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "config or userId is required"})
+		return
+	}
+	if params.BNBPriceUSD <= 0 {
+		price, err := h.valuator.BNBPriceUSD(ctx)
+		if err != nil {
+			log.Printf("backtest bnb price: %v", err)
+			c.JSON(http.StatusBadRequest, gin.H{"error": "bnbPriceUsd is required while the BNB price is unavailable"})
+			return
+		}
+		params.BNBPriceUSD = price.InexactFloat64()
+	}
+	if err := params.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	report, err := h.backtester.Run(ctx, params)
+	if err != nil {
+		log.Printf("run backtest: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "backtest failed"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
@@ -11,16 +11,23 @@ import (
 
 	"easymeme/internal/model"
 	"easymeme/internal/repository"
+	"easymeme/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
 
 type TokenHandler struct {
-	repo *repository.Repository
+	repo      *repository.Repository
+	goldenDog GoldenDogListener
 }
 
-func NewTokenHandler(repo *repository.Repository) *TokenHandler {
-	return &TokenHandler{repo: repo}
+// GoldenDogListener is notified when an analysis flags a token as a golden dog.
+type GoldenDogListener interface {
+	OnGoldenDog(tokenAddress string)
+}
+
+func NewTokenHandler(repo *repository.Repository, goldenDog GoldenDogListener) *TokenHandler {
+	return &TokenHandler{repo: repo, goldenDog: goldenDog}
 }
 
 func toTokenDTO(token model.Token) TokenResponseDTO {
@@ -34,6 +41,10 @@ func toTokenDTO(token model.Token) TokenResponseDTO {
 		Dex:              token.Dex,
 		InitialLiquidity: token.InitialLiquidity.String(),
 		AnalysisStatus:   token.AnalysisStatus,
+		AnalysisSource:   token.AnalysisSource,
+		ScoringVersion:   token.ScoringVersion,
+		AgentCount:       token.AgentCount,
+		Disagreement:     token.AgentDisagreement,
 		RiskScore:        token.RiskScore,
 		RiskLevel:        token.RiskLevel,
 		IsGoldenDog:      token.IsGoldenDog,
@@ -66,6 +77,10 @@ type TokenResponseDTO struct {
 	Dex              string     `json:"dex"`
 	InitialLiquidity string     `json:"initial_liquidity"`
 	AnalysisStatus   string     `json:"analysis_status"`
+	AnalysisSource   string     `json:"analysis_source"`
+	ScoringVersion   int        `json:"scoring_version"`
+	AgentCount       int        `json:"agent_count"`
+	Disagreement     bool       `json:"agent_disagreement"`
 	RiskScore        int        `json:"risk_score"`
 	RiskLevel        string     `json:"risk_level"`
 	IsGoldenDog      bool       `json:"is_golden_dog"`
@@ -133,6 +148,11 @@ type TokenDetailResponse struct {
 	CreatorAddress     string                 `json:"creatorAddress"`
 	CreatedAt          time.Time              `json:"createdAt"`
 	AnalyzedAt         *time.Time             `json:"analyzedAt"`
+	AnalysisSource     string                 `json:"analysisSource"`
+	ScoringVersion     int                    `json:"scoringVersion"`
+	AgentCount         int                    `json:"agentCount"`
+	AgentDisagreement  bool                   `json:"agentDisagreement"`
+	Consensus          *service.Consensus     `json:"consensus,omitempty"`
 	RiskScore          int                    `json:"riskScore"`
 	RiskLevel          string                 `json:"riskLevel"`
 	IsGoldenDog        bool                   `json:"isGoldenDog"`
@@ -177,6 +197,13 @@ func (h *TokenHandler) GetTokenDetail(c *gin.Context) {
 	if len(token.AnalysisResult) > 0 {
 		_ = json.Unmarshal(token.AnalysisResult, &analysisResult)
 	}
+	var consensus *service.Consensus
+	if len(token.Consensus) > 0 {
+		consensus = &service.Consensus{}
+		if err := json.Unmarshal(token.Consensus, consensus); err != nil {
+			consensus = nil
+		}
+	}
 	goplusData := map[string]interface{}{}
 	if len(token.RiskDetails) > 0 {
 		if normalized, ok := riskDetails["normalized"].(map[string]interface{}); ok {
@@ -216,6 +243,11 @@ func (h *TokenHandler) GetTokenDetail(c *gin.Context) {
 		CreatorAddress:     token.CreatorAddress,
 		CreatedAt:          token.CreatedAt,
 		AnalyzedAt:         token.AnalyzedAt,
+		AnalysisSource:     token.AnalysisSource,
+		ScoringVersion:     token.ScoringVersion,
+		AgentCount:         token.AgentCount,
+		AgentDisagreement:  token.AgentDisagreement,
+		Consensus:          consensus,
 		RiskScore:          token.RiskScore,
 		RiskLevel:          token.RiskLevel,
 		IsGoldenDog:        token.IsGoldenDog,
@@ -250,6 +282,9 @@ type PendingTokenResponse struct {
 	MarketAlerts       any       `json:"marketAlerts"`
 	SocialSignals      any       `json:"socialSignals"`
 	SmartMoneySignals  any       `json:"smartMoneySignals"`
+	Priority           float64   `json:"priority"` // queue priority including aging
+	// Baseline is the server's rule-based analysis, when one exists.
+	Baseline any `json:"baseline,omitempty"`
 }
 
 type PendingTokenListResponseEnvelope struct {
@@ -258,7 +293,7 @@ type PendingTokenListResponseEnvelope struct {
 
 // GetPendingTokens godoc
 // @Summary Get pending tokens
-// @Description List tokens pending analysis
+// @Description List tokens pending analysis, highest queue priority first
 // @Tags tokens
 // @Param limit query int false "Limit" default(10)
 // @Param min_liquidity query number false "Min liquidity"
@@ -280,7 +315,7 @@ func (h *TokenHandler) GetPendingTokens(c *gin.Context) {
 		}
 	}
 
-	tokens, err := h.repo.GetPendingTokens(c.Request.Context(), limit, minLiquidity)
+	tokens, err := h.repo.GetPendingTokens(c.Request.Context(), limit, minLiquidity, pendingQueue())
 	if err != nil {
 		log.Printf("get pending tokens: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
@@ -289,64 +324,74 @@ func (h *TokenHandler) GetPendingTokens(c *gin.Context) {
 
 	resp := make([]PendingTokenResponse, 0, len(tokens))
 	for _, token := range tokens {
-		goplusData := map[string]interface{}{}
-		if len(token.RiskDetails) > 0 {
-			var details map[string]interface{}
-			_ = json.Unmarshal(token.RiskDetails, &details)
-			if normalized, ok := details["normalized"].(map[string]interface{}); ok {
-				goplusData = normalized
-				if raw, exists := details["raw"]; exists {
-					goplusData["raw"] = raw
-				}
-			} else {
-				goplusData = details
-			}
-		}
-		marketData := map[string]interface{}{}
-		if len(token.MarketData) > 0 {
-			_ = json.Unmarshal(token.MarketData, &marketData)
-		}
-		holderData := map[string]interface{}{}
-		if len(token.HolderData) > 0 {
-			_ = json.Unmarshal(token.HolderData, &holderData)
-		}
-		creatorData := map[string]interface{}{}
-		if len(token.CreatorHistory) > 0 {
-			_ = json.Unmarshal(token.CreatorHistory, &creatorData)
-		}
-		alertData := []map[string]interface{}{}
-		if len(token.MarketAlerts) > 0 {
-			_ = json.Unmarshal(token.MarketAlerts, &alertData)
-		}
-		socialSignals := map[string]interface{}{}
-		if len(token.SocialSignals) > 0 {
-			_ = json.Unmarshal(token.SocialSignals, &socialSignals)
-		}
-		smartMoneySignals := map[string]interface{}{}
-		if len(token.SmartMoneySignals) > 0 {
-			_ = json.Unmarshal(token.SmartMoneySignals, &smartMoneySignals)
-		}
-		resp = append(resp, PendingTokenResponse{
-			Address:            token.Address,
-			Name:               token.Name,
-			Symbol:             token.Symbol,
-			Liquidity:          token.InitialLiquidity.InexactFloat64(),
-			CreatorAddress:     token.CreatorAddress,
-			CreatedAt:          token.CreatedAt,
-			PairAddress:        token.PairAddress,
-			GoPlus:             goplusData,
-			DEXScreener:        marketData,
-			HolderDistribution: holderData,
-			CreatorHistory:     creatorData,
-			MarketAlerts:       alertData,
-			SocialSignals:      socialSignals,
-			SmartMoneySignals:  smartMoneySignals,
-		})
+		resp = append(resp, toPendingTokenResponse(token))
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
+func toPendingTokenResponse(token model.Token) PendingTokenResponse {
+	goplusData := map[string]interface{}{}
+	if len(token.RiskDetails) > 0 {
+		var details map[string]interface{}
+		_ = json.Unmarshal(token.RiskDetails, &details)
+		if normalized, ok := details["normalized"].(map[string]interface{}); ok {
+			goplusData = normalized
+			if raw, exists := details["raw"]; exists {
+				goplusData["raw"] = raw
+			}
+		} else {
+			goplusData = details
+		}
+	}
+	marketData := map[string]interface{}{}
+	if len(token.MarketData) > 0 {
+		_ = json.Unmarshal(token.MarketData, &marketData)
+	}
+	holderData := map[string]interface{}{}
+	if len(token.HolderData) > 0 {
+		_ = json.Unmarshal(token.HolderData, &holderData)
+	}
+	creatorData := map[string]interface{}{}
+	if len(token.CreatorHistory) > 0 {
+		_ = json.Unmarshal(token.CreatorHistory, &creatorData)
+	}
+	alertData := []map[string]interface{}{}
+	if len(token.MarketAlerts) > 0 {
+		_ = json.Unmarshal(token.MarketAlerts, &alertData)
+	}
+	socialSignals := map[string]interface{}{}
+	if len(token.SocialSignals) > 0 {
+		_ = json.Unmarshal(token.SocialSignals, &socialSignals)
+	}
+	smartMoneySignals := map[string]interface{}{}
+	if len(token.SmartMoneySignals) > 0 {
+		_ = json.Unmarshal(token.SmartMoneySignals, &smartMoneySignals)
+	}
+	var baseline map[string]interface{}
+	if token.AnalysisSource == model.AnalysisSourceBaseline && len(token.AnalysisResult) > 0 {
+		_ = json.Unmarshal(token.AnalysisResult, &baseline)
+	}
+	return PendingTokenResponse{
+		Address:            token.Address,
+		Name:               token.Name,
+		Symbol:             token.Symbol,
+		Liquidity:          token.InitialLiquidity.InexactFloat64(),
+		CreatorAddress:     token.CreatorAddress,
+		CreatedAt:          token.CreatedAt,
+		PairAddress:        token.PairAddress,
+		GoPlus:             goplusData,
+		DEXScreener:        marketData,
+		HolderDistribution: holderData,
+		CreatorHistory:     creatorData,
+		MarketAlerts:       alertData,
+		SocialSignals:      socialSignals,
+		SmartMoneySignals:  smartMoneySignals,
+		Priority:           token.QueuePriorityAt(time.Now(), model.ActiveScoring().Priority.AgingPerMinute, model.ActiveScoring().Priority.MaxAging),
+		Baseline:           baseline,
+	}
+}
+
 type AnalyzedTokenListResponseEnvelope struct {
 	Data []TokenResponseDTO `json:"data"`
 }
@@ -449,14 +494,7 @@ func (h *TokenHandler) GetGoldenDogs(c *gin.Context) {
 		}
 	}
 
-	fetchLimit := limit * 5
-	if fetchLimit < 20 {
-		fetchLimit = 20
-	}
-	if fetchLimit > 200 {
-		fetchLimit = 200
-	}
-
+	fetchLimit := model.ActiveScoring().GoldenDogFetchLimit(limit)
 	tokens, err := h.repo.GetGoldenDogTokens(c.Request.Context(), fetchLimit)
 	if err != nil {
 		log.Printf("get golden dog tokens: %v", err)
@@ -570,6 +608,75 @@ func (h *TokenHandler) GetGoldenDogScoreDistribution(c *gin.Context) {
 	})
 }
 
+type CalibrationReportResponseEnvelope struct {
+	Data service.CalibrationReport `json:"data"`
+}
+
+// GetCalibrationReport godoc
+// @Summary Get golden dog score calibration
+// @Description Score the first analysis of each token against its labeled outcome: precision, recall, median max return and rug rate per score bucket and risk level, plus a calibration curve
+// @Tags tokens
+// @Param from query string false "RFC3339 start of analysis time, 30 days before to by default"
+// @Param to query string false "RFC3339 end of analysis time"
+// @Param horizon query string false "Outcome horizon: 1h, 6h or 24h" default(24h)
+// @Param good query string false "Good outcome label: 2x, 10x or survived" default(2x)
+// @Param source query string false "baseline or agent"
+// @Param agent query string false "Agent id"
+// @Param model_version query string false "Model version of the analysis; the scoring model version for baseline analyses"
+// @Param bucket query int false "Bucket size" default(10)
+// @Success 200 {object} CalibrationReportResponseEnvelope
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens/stats/calibration [get]
+func (h *TokenHandler) GetCalibrationReport(c *gin.Context) {
+	to := time.Now().UTC()
+	if v := c.Query("to"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			to = t
+		}
+	}
+	from := to.Add(-30 * 24 * time.Hour)
+	if v := c.Query("from"); v != "" {
+		if t, err := time.Parse(time.RFC3339, v); err == nil {
+			from = t
+		}
+	}
+	bucket := 10
+	if v := c.Query("bucket"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			bucket = parsed
+		}
+	}
+	filter := repository.OutcomeFilter{
+		From:         from,
+		To:           to,
+		Horizon:      c.DefaultQuery("horizon", "24h"),
+		Source:       c.Query("source"),
+		AgentID:      c.Query("agent"),
+		ModelVersion: c.Query("model_version"),
+	}
+	if filter.AgentID != "" && filter.Source == "" {
+		filter.Source = model.AnalysisSourceAgent
+	}
+	good := c.DefaultQuery("good", model.Outcome2x)
+	if !service.ValidOutcomeHorizon(filter.Horizon) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "horizon must be 1h, 6h or 24h"})
+		return
+	}
+	if good != model.Outcome2x && good != model.Outcome10x && good != model.OutcomeSurvived {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "good must be 2x, 10x or survived"})
+		return
+	}
+
+	report, err := service.BuildCalibrationReport(c.Request.Context(), h.repo, filter, bucket, good)
+	if err != nil {
+		log.Printf("build calibration report: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": report})
+}
+
 type TokenPricePoint struct {
 	TS           time.Time `json:"ts"`
 	PriceUSD     float64   `json:"price"`
@@ -707,13 +814,15 @@ type AnalysisStatusResponseEnvelope struct {
 
 // PostTokenAnalysis godoc
 // @Summary Submit token analysis
-// @Description Submit AI analysis for a token
+// @Description Submit an agent's analysis for a token. The token's scores become the accuracy-weighted consensus of every agent's latest analysis.
 // @Tags tokens
 // @Param address path string true "Token address"
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides"
 // @Param payload body AnalyzeTokenRiskPayload true "Analysis payload"
 // @Success 200 {object} AnalysisStatusResponseEnvelope
 // @Failure 400 {object} map[string]string
 // @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Security ApiKeyAuth
 // @Router /api/tokens/{address}/analysis [post]
@@ -830,25 +939,191 @@ func (h *TokenHandler) PostTokenAnalysis(c *gin.Context) {
 	riskLevel := strings.ToLower(payload.RiskLevel)
 	analysisJSON, _ := json.Marshal(raw)
 
-	updates := map[string]interface{}{
-		"risk_score":       payload.RiskScore,
-		"risk_level":       riskLevel,
-		"analysis_result":  analysisJSON,
-		"analysis_status":  "analyzed",
-		"is_golden_dog":    payload.IsGoldenDog,
-		"golden_dog_score": payload.GoldenDogScore,
-		"analyzed_at":      now,
+	modelVersion := getString("modelVersion", "model_version")
+	if modelVersion == "" {
+		if v, ok := getIntWithPresence("modelVersion", "model_version"); ok {
+			modelVersion = strconv.Itoa(v)
+		}
 	}
-
-	if payload.RiskFactors.HoneypotRisk != "" {
-		updates["is_honeypot"] = strings.EqualFold(payload.RiskFactors.HoneypotRisk, "HIGH")
+	agentID := requestAgentID(c, getString("agentId", "agent_id"))
+	record := &model.TokenAnalysis{
+		TokenAddress:   address,
+		Source:         model.AnalysisSourceAgent,
+		AgentID:        agentID,
+		Model:          getString("model"),
+		ModelVersion:   modelVersion,
+		RiskScore:      payload.RiskScore,
+		RiskLevel:      riskLevel,
+		IsGoldenDog:    payload.IsGoldenDog,
+		GoldenDogScore: payload.GoldenDogScore,
+		HoneypotRisk:   strings.ToUpper(strings.TrimSpace(payload.RiskFactors.HoneypotRisk)),
+		Payload:        analysisJSON,
+		CreatedAt:      now,
 	}
 
-	if err := h.repo.UpdateTokenAnalysis(c.Request.Context(), address, updates); err != nil {
+	weights, err := service.AgentWeights(c.Request.Context(), h.repo)
+	if err != nil {
+		log.Printf("load agent weights: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	var consensus service.Consensus
+	applied, err := h.repo.RecordAgentAnalysis(c.Request.Context(), record, func(latest []model.TokenAnalysis) (map[string]interface{}, error) {
+		consensus = service.BuildConsensus(latest, weights, now)
+		consensusJSON, err := json.Marshal(consensus)
+		if err != nil {
+			return nil, err
+		}
+		updates := map[string]interface{}{
+			"risk_score":         consensus.RiskScore,
+			"risk_level":         consensus.RiskLevel,
+			"analysis_result":    analysisJSON,
+			"analysis_status":    "analyzed",
+			"analysis_source":    model.AnalysisSourceAgent,
+			"scoring_version":    0,
+			"is_golden_dog":      consensus.IsGoldenDog,
+			"golden_dog_score":   consensus.GoldenDogScore,
+			"consensus":          consensusJSON,
+			"agent_count":        consensus.Agents,
+			"agent_disagreement": consensus.Disagreement,
+			"analyzed_at":        now,
+		}
+		if consensus.HoneypotAgents > 0 {
+			updates["is_honeypot"] = consensus.IsHoneypot
+		}
+		return updates, nil
+	})
+	if err != nil {
 		log.Printf("update token analysis: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
 		return
 	}
+	if !applied {
+		c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+		return
+	}
+	if err := h.repo.TouchAnalysisAgent(c.Request.Context(), record.AgentID); err != nil {
+		log.Printf("touch analysis agent %s: %v", record.AgentID, err)
+	}
+	if consensus.Disagreement {
+		log.Printf("agents disagree on %s: %s", address, strings.Join(consensus.DisagreementReasons, "; "))
+	}
+
+	if consensus.IsGoldenDog && h.goldenDog != nil {
+		h.goldenDog.OnGoldenDog(address)
+	}
 
 	c.JSON(http.StatusOK, gin.H{"status": "ok"})
 }
+
+type TokenAnalysisResponse struct {
+	model.TokenAnalysis
+	Current bool `json:"current"`
+}
+
+type TokenAnalysisListResponseEnvelope struct {
+	Data []TokenAnalysisResponse `json:"data"`
+}
+
+// GetTokenAnalyses godoc
+// @Summary Get token analysis history
+// @Description Every stored analysis of a token, newest first, with the current one flagged
+// @Tags tokens
+// @Param address path string true "Token address"
+// @Param source query string false "baseline or agent"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} TokenAnalysisListResponseEnvelope
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens/{address}/analyses [get]
+func (h *TokenHandler) GetTokenAnalyses(c *gin.Context) {
+	address := c.Param("address")
+	token, err := h.repo.GetTokenByAddress(c.Request.Context(), address)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "token not found"})
+		return
+	}
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	analyses, err := h.repo.ListTokenAnalyses(c.Request.Context(), token.Address, c.Query("source"), limit)
+	if err != nil {
+		log.Printf("list token analyses: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	resp := make([]TokenAnalysisResponse, 0, len(analyses))
+	for _, analysis := range analyses {
+		resp = append(resp, TokenAnalysisResponse{
+			TokenAnalysis: analysis,
+			Current:       token.CurrentAnalysisID != nil && *token.CurrentAnalysisID == analysis.ID,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+type TokenOutcomeListResponseEnvelope struct {
+	Data []model.TokenOutcome `json:"data"`
+}
+
+// GetTokenOutcomes godoc
+// @Summary Get token outcomes
+// @Description Outcome labels of a token at 1h, 6h and 24h after launch with the metrics behind them
+// @Tags tokens
+// @Param address path string true "Token address"
+// @Success 200 {object} TokenOutcomeListResponseEnvelope
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens/{address}/outcomes [get]
+func (h *TokenHandler) GetTokenOutcomes(c *gin.Context) {
+	outcomes, err := h.repo.ListTokenOutcomes(c.Request.Context(), c.Param("address"))
+	if err != nil {
+		log.Printf("list token outcomes: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": outcomes})
+}
+
+type AnalysisAgentListResponseEnvelope struct {
+	Data []model.AnalysisAgent `json:"data"`
+}
+
+// GetAnalysisAgents godoc
+// @Summary List analysis agents
+// @Description Agents that submitted analyses with the accuracy that weighs them in the consensus
+// @Tags tokens
+// @Success 200 {object} AnalysisAgentListResponseEnvelope
+// @Failure 500 {object} map[string]string
+// @Router /api/analysis-agents [get]
+func (h *TokenHandler) GetAnalysisAgents(c *gin.Context) {
+	agents, err := h.repo.ListAnalysisAgents(c.Request.Context())
+	if err != nil {
+		log.Printf("list analysis agents: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": agents})
+}
+
+// AgentIDContextKey holds the agent identified by its API key.
+const AgentIDContextKey = "agentID"
+
+// requestAgentID identifies the calling agent by its API key. Only when the
+// server requires no key does it fall back to the X-Agent-Id header, then the
+// id in the payload, then the default agent.
+func requestAgentID(c *gin.Context, fromPayload string) string {
+	if agentID := c.GetString(AgentIDContextKey); agentID != "" {
+		return agentID
+	}
+	if agentID := strings.TrimSpace(c.GetHeader("X-Agent-Id")); agentID != "" {
+		return agentID
+	}
+	if agentID := strings.TrimSpace(fromPayload); agentID != "" {
+		return agentID
+	}
+	return model.DefaultAgentID
+}
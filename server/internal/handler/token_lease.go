@@ -0,0 +1,180 @@
+package handler
+
+import (
+	"errors"
+	"io"
+	"log"
+	"net/http"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	defaultLeaseTTL = 5 * time.Minute
+	maxLeaseTTL     = 30 * time.Minute
+	maxClaimLimit   = 50
+)
+
+type ClaimPendingTokensRequest struct {
+	AgentID      string  `json:"agentId"`
+	Limit        int     `json:"limit"`
+	TTLSeconds   int     `json:"ttlSeconds"`
+	MinLiquidity float64 `json:"minLiquidity"`
+}
+
+type TokenLeaseRequest struct {
+	AgentID    string   `json:"agentId"`
+	Addresses  []string `json:"addresses"`
+	TTLSeconds int      `json:"ttlSeconds"`
+}
+
+type PendingTokenClaimResponse struct {
+	AgentID        string                 `json:"agentId"`
+	LeaseExpiresAt *time.Time             `json:"leaseExpiresAt"`
+	Tokens         []PendingTokenResponse `json:"tokens"`
+}
+
+type PendingTokenClaimResponseEnvelope struct {
+	Data PendingTokenClaimResponse `json:"data"`
+}
+
+type TokenLeaseRenewResponse struct {
+	AgentID        string    `json:"agentId"`
+	Renewed        []string  `json:"renewed"`
+	LeaseExpiresAt time.Time `json:"leaseExpiresAt"`
+}
+
+type TokenLeaseRenewResponseEnvelope struct {
+	Data TokenLeaseRenewResponse `json:"data"`
+}
+
+type TokenLeaseReleaseResponse struct {
+	AgentID  string `json:"agentId"`
+	Released int64  `json:"released"`
+}
+
+type TokenLeaseReleaseResponseEnvelope struct {
+	Data TokenLeaseReleaseResponse `json:"data"`
+}
+
+// ClaimPendingTokens godoc
+// @Summary Claim pending tokens
+// @Description Lease up to limit pending tokens to the calling agent so other agents skip them. Leases expire after ttlSeconds (default 300, max 1800) unless renewed; submitting an analysis as the lease holder releases the token.
+// @Tags tokens
+// @Accept json
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides"
+// @Param payload body ClaimPendingTokensRequest false "Claim"
+// @Success 200 {object} PendingTokenClaimResponseEnvelope
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens/pending/claim [post]
+func (h *TokenHandler) ClaimPendingTokens(c *gin.Context) {
+	var req ClaimPendingTokensRequest
+	if err := c.ShouldBindJSON(&req); err != nil && !errors.Is(err, io.EOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > maxClaimLimit {
+		limit = maxClaimLimit
+	}
+	agentID := requestAgentID(c, req.AgentID)
+
+	tokens, err := h.repo.ClaimPendingTokens(c.Request.Context(), agentID, limit, req.MinLiquidity, pendingQueue(), leaseTTL(req.TTLSeconds))
+	if err != nil {
+		log.Printf("claim pending tokens: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	resp := PendingTokenClaimResponse{AgentID: agentID, Tokens: make([]PendingTokenResponse, 0, len(tokens))}
+	for _, token := range tokens {
+		resp.LeaseExpiresAt = token.LeaseExpiresAt
+		resp.Tokens = append(resp.Tokens, toPendingTokenResponse(token))
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// RenewTokenLeases godoc
+// @Summary Renew token leases
+// @Description Heartbeat: extend the leases the calling agent still holds. Addresses missing from renewed were claimed by another agent or already analyzed.
+// @Tags tokens
+// @Accept json
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides"
+// @Param payload body TokenLeaseRequest true "Leases"
+// @Success 200 {object} TokenLeaseRenewResponseEnvelope
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens/pending/heartbeat [post]
+func (h *TokenHandler) RenewTokenLeases(c *gin.Context) {
+	var req TokenLeaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Addresses) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	agentID := requestAgentID(c, req.AgentID)
+
+	renewed, expiresAt, err := h.repo.RenewTokenLeases(c.Request.Context(), agentID, req.Addresses, leaseTTL(req.TTLSeconds))
+	if err != nil {
+		log.Printf("renew token leases: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": TokenLeaseRenewResponse{AgentID: agentID, Renewed: renewed, LeaseExpiresAt: expiresAt}})
+}
+
+// ReleaseTokenLeases godoc
+// @Summary Release token leases
+// @Description Return tokens the calling agent will not analyze to the queue
+// @Tags tokens
+// @Accept json
+// @Param X-Agent-Id header string false "Agent identity, honoured only when the server has no API keys; otherwise the X-API-Key decides"
+// @Param payload body TokenLeaseRequest true "Leases"
+// @Success 200 {object} TokenLeaseReleaseResponseEnvelope
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/tokens/pending/release [post]
+func (h *TokenHandler) ReleaseTokenLeases(c *gin.Context) {
+	var req TokenLeaseRequest
+	if err := c.ShouldBindJSON(&req); err != nil || len(req.Addresses) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	agentID := requestAgentID(c, req.AgentID)
+
+	released, err := h.repo.ReleaseTokenLeases(c.Request.Context(), agentID, req.Addresses)
+	if err != nil {
+		log.Printf("release token leases: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": TokenLeaseReleaseResponse{AgentID: agentID, Released: released}})
+}
+
+// pendingQueue is the analysis queue configuration of the active scoring model.
+func pendingQueue() repository.PendingQueue {
+	scoring := model.ActiveScoring()
+	return repository.PendingQueue{
+		AgingPerMinute: scoring.Priority.AgingPerMinute,
+		MaxAging:       scoring.Priority.MaxAging,
+		Window:         scoring.AnalysisWindow(),
+	}
+}
+
+func leaseTTL(seconds int) time.Duration {
+	if seconds <= 0 {
+		return defaultLeaseTTL
+	}
+	ttl := time.Duration(seconds) * time.Second
+	if ttl > maxLeaseTTL {
+		return maxLeaseTTL
+	}
+	return ttl
+}
@@ -21,6 +21,7 @@ import (
 
 	"easymeme/internal/model"
 	"easymeme/internal/repository"
+	"easymeme/internal/service"
 	"easymeme/pkg/ethereum"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -31,31 +32,54 @@ import (
 )
 
 type WalletHandler struct {
-	repo *repository.Repository
-	eth  *ethereum.Client
+	repo     *repository.Repository
+	eth      *ethereum.Client
+	hub      service.Broadcaster
+	webhook  *service.WebhookClient
+	breaker  *service.CircuitBreaker
+	valuator *service.Valuator
+	ledger   *service.Ledger
+	// approvals expires overdue trade approvals on read, ahead of the
+	// background expirer.
+	approvals *service.ApprovalExpirer
 }
 
-func NewWalletHandler(repo *repository.Repository, eth *ethereum.Client) *WalletHandler {
-	return &WalletHandler{repo: repo, eth: eth}
+func NewWalletHandler(repo *repository.Repository, eth *ethereum.Client, hub service.Broadcaster, breaker *service.CircuitBreaker, valuator *service.Valuator) *WalletHandler {
+	return &WalletHandler{
+		repo:      repo,
+		eth:       eth,
+		hub:       hub,
+		webhook:   service.NewWebhookClient(),
+		breaker:   breaker,
+		valuator:  valuator,
+		ledger:    service.NewLedger(repo, valuator),
+		approvals: service.NewApprovalExpirer(repo, hub),
+	}
 }
 
 type CreateWalletRequest struct {
 	UserID string `json:"userId"`
+	// Mode is live (default) or paper.
+	Mode string `json:"mode"`
+	// PaperBalance is the virtual BNB a paper wallet starts with (default 10).
+	PaperBalance decimal.Decimal `json:"paperBalance" swaggertype:"string"`
 }
 
 type CreateWalletResponse struct {
 	ID      string `json:"id"`
 	UserID  string `json:"userId"`
 	Address string `json:"address"`
+	Mode    string `json:"mode"`
 }
 
 // CreateWallet godoc
 // @Summary Create managed wallet
-// @Description Create a managed wallet and store encrypted private key
+// @Description Create a managed wallet and store encrypted private key; paper wallets trade virtual BNB and never broadcast. Each user has one wallet, live or paper
 // @Tags wallet
 // @Param payload body CreateWalletRequest true "Create wallet payload"
 // @Success 200 {object} map[string]CreateWalletResponse
 // @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/wallet/create [post]
 func (h *WalletHandler) CreateWallet(c *gin.Context) {
@@ -64,6 +88,29 @@ func (h *WalletHandler) CreateWallet(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
 		return
 	}
+	mode := strings.ToLower(strings.TrimSpace(req.Mode))
+	if mode == "" {
+		mode = model.WalletModeLive
+	}
+	if mode != model.WalletModeLive && mode != model.WalletModePaper {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "mode must be live or paper"})
+		return
+	}
+	balance := decimal.Zero
+	if mode == model.WalletModePaper {
+		balance = req.PaperBalance
+		if balance.IsZero() {
+			balance = decimal.NewFromInt(defaultPaperBalance)
+		}
+		if balance.IsNegative() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "paperBalance must be positive"})
+			return
+		}
+	}
+	if _, err := h.repo.GetManagedWalletByUser(c.Request.Context(), req.UserID); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "user already has a wallet"})
+		return
+	}
 
 	privateKey, err := crypto.GenerateKey()
 	if err != nil {
@@ -89,10 +136,15 @@ func (h *WalletHandler) CreateWallet(c *gin.Context) {
 		UserID:       req.UserID,
 		Address:      address,
 		EncryptedKey: encrypted,
-		Balance:      0,
-		MaxBalance:   5,
+		Mode:         mode,
+		Balance:      balance,
+		MaxBalance:   decimal.NewFromInt(5),
 	}
 	if err := h.repo.CreateManagedWallet(c.Request.Context(), wallet); err != nil {
+		if errors.Is(err, repository.ErrWalletExists) {
+			c.JSON(http.StatusConflict, gin.H{"error": "user already has a wallet"})
+			return
+		}
 		log.Printf("create wallet: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create wallet"})
 		return
@@ -102,14 +154,17 @@ func (h *WalletHandler) CreateWallet(c *gin.Context) {
 		ID:      wallet.ID,
 		UserID:  wallet.UserID,
 		Address: wallet.Address,
+		Mode:    wallet.Mode,
 	}
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
+// WalletBalanceResponse.Balance is in BNB, encoded as a decimal string.
 type WalletBalanceResponse struct {
-	UserID  string  `json:"userId"`
-	Address string  `json:"address"`
-	Balance float64 `json:"balance"`
+	UserID  string          `json:"userId"`
+	Address string          `json:"address"`
+	Mode    string          `json:"mode"`
+	Balance decimal.Decimal `json:"balance" swaggertype:"string"`
 }
 
 // GetWalletBalance godoc
@@ -138,6 +193,7 @@ func (h *WalletHandler) GetWalletBalance(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": WalletBalanceResponse{
 		UserID:  wallet.UserID,
 		Address: wallet.Address,
+		Mode:    wallet.Mode,
 		Balance: wallet.Balance,
 	}})
 }
@@ -171,27 +227,39 @@ func (h *WalletHandler) GetWalletInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"data": WalletBalanceResponse{
 		UserID:  wallet.UserID,
 		Address: wallet.Address,
+		Mode:    wallet.Mode,
 		Balance: wallet.Balance,
 	}})
 }
 
 type WithdrawRequest struct {
-	UserID string  `json:"userId"`
-	Amount float64 `json:"amount"`
+	UserID string `json:"userId"`
+	// Amount is in BNB; send it as a string to keep full precision.
+	Amount    decimal.Decimal `json:"amount" swaggertype:"string"`
+	ToAddress string          `json:"toAddress"`
+	TOTPCode  string          `json:"totpCode"`
 }
 
 type AIPositionResponse struct {
-	UserID       string `json:"user_id"`
-	TokenAddress string `json:"token_address"`
-	TokenSymbol  string `json:"token_symbol"`
-	Quantity     string `json:"quantity"`
-	CostBNB      string `json:"cost_bnb"`
-	UpdatedAt    string `json:"updated_at"`
+	UserID          string  `json:"user_id"`
+	TokenAddress    string  `json:"token_address"`
+	TokenSymbol     string  `json:"token_symbol"`
+	Quantity        string  `json:"quantity"`
+	CostBNB         string  `json:"cost_bnb"`
+	EntryPriceBNB   string  `json:"entry_price_bnb"`
+	PriceBNB        string  `json:"price_bnb"`
+	ValueBNB        string  `json:"value_bnb"`
+	ValueUSD        string  `json:"value_usd"`
+	UnrealizedPLBNB string  `json:"unrealized_pl_bnb"`
+	UnrealizedPL    float64 `json:"unrealized_pl"`
+	PriceChange     float64 `json:"price_change"`
+	MarkedAt        string  `json:"marked_at"`
+	UpdatedAt       string  `json:"updated_at"`
 }
 
 // GetAIPositions godoc
 // @Summary Get AI positions
-// @Description Get AI positions by user (userId optional, fallback to EASYMEME_USER_ID)
+// @Description Get AI positions by user with mark-to-market value in BNB and USD (userId optional, fallback to EASYMEME_USER_ID)
 // @Tags ai-trades
 // @Param userId query string false "User ID"
 // @Success 200 {object} map[string][]AIPositionResponse
@@ -208,72 +276,218 @@ func (h *WalletHandler) GetAIPositions(c *gin.Context) {
 		return
 	}
 
-	positions, err := h.repo.ListAIPositionsByUser(c.Request.Context(), userID)
+	ctx := c.Request.Context()
+	positions, err := h.repo.ListAIPositionsByUser(ctx, userID)
 	if err != nil {
 		log.Printf("list ai positions: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load positions"})
 		return
 	}
+	bnbUSD, _ := h.valuator.BNBPriceUSD(ctx)
 
 	resp := make([]AIPositionResponse, 0, len(positions))
-	for _, pos := range positions {
-		resp = append(resp, AIPositionResponse{
+	for i := range positions {
+		pos := &positions[i]
+		item := AIPositionResponse{
 			UserID:       pos.UserID,
 			TokenAddress: pos.TokenAddress,
 			TokenSymbol:  pos.TokenSymbol,
 			Quantity:     pos.Quantity.String(),
 			CostBNB:      pos.CostBNB.String(),
 			UpdatedAt:    pos.UpdatedAt.Format(time.RFC3339),
-		})
+		}
+		if pos.Quantity.GreaterThan(decimal.Zero) {
+			val := h.valuator.ValuePosition(ctx, pos, bnbUSD)
+			item.EntryPriceBNB = val.EntryPriceBNB.String()
+			if val.Error == "" {
+				item.PriceBNB = val.PriceBNB.String()
+				item.ValueBNB = val.ValueBNB.String()
+				item.ValueUSD = val.ValueUSD.StringFixed(2)
+				item.UnrealizedPLBNB = val.UnrealizedPLBNB.String()
+				item.UnrealizedPL = val.UnrealizedPL
+				item.PriceChange = val.PriceChange
+				item.MarkedAt = val.MarkedAt.Format(time.RFC3339)
+			}
+		}
+		resp = append(resp, item)
 	}
 
 	c.JSON(http.StatusOK, gin.H{"data": resp})
 }
 
 type WithdrawResponse struct {
-	UserID  string  `json:"userId"`
-	Address string  `json:"address"`
-	Balance float64 `json:"balance"`
+	UserID    string          `json:"userId"`
+	Address   string          `json:"address"`
+	ToAddress string          `json:"toAddress"`
+	TxHash    string          `json:"txHash"`
+	Status    string          `json:"status"`
+	Balance   decimal.Decimal `json:"balance" swaggertype:"string"`
 }
 
 // Withdraw godoc
 // @Summary Withdraw from managed wallet
-// @Description Decrease managed wallet balance (placeholder, no on-chain tx)
+// @Description Send BNB to an active allowlisted address; requires a TOTP code and respects the daily withdrawal limit
 // @Tags wallet
 // @Param payload body WithdrawRequest true "Withdraw payload"
 // @Success 200 {object} map[string]WithdrawResponse
 // @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 404 {object} map[string]string
+// @Failure 429 {object} map[string]string
 // @Failure 500 {object} map[string]string
 // @Router /api/wallet/withdraw [post]
 func (h *WalletHandler) Withdraw(c *gin.Context) {
 	var req WithdrawRequest
-	if err := c.ShouldBindJSON(&req); err != nil || req.Amount <= 0 || strings.TrimSpace(req.UserID) == "" {
+	if err := c.ShouldBindJSON(&req); err != nil || !req.Amount.IsPositive() || strings.TrimSpace(req.UserID) == "" {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
 		return
 	}
+	if !common.IsHexAddress(req.ToAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid toAddress"})
+		return
+	}
+	ctx := c.Request.Context()
 
-	wallet, err := h.repo.GetManagedWalletByUser(c.Request.Context(), req.UserID)
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, req.UserID)
 	if err != nil {
 		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
 		return
 	}
-	if req.Amount > wallet.Balance {
+	if wallet.IsPaper() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "paper wallets cannot withdraw"})
+		return
+	}
+
+	if _, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode); err != nil {
+		respondTOTPError(c, err)
+		return
+	}
+
+	entry, err := h.repo.GetWithdrawalAddress(ctx, req.UserID, req.ToAddress)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"error": "address not in withdrawal allowlist"})
+		return
+	}
+	if time.Now().Before(entry.ActiveAt) {
+		c.JSON(http.StatusForbidden, gin.H{"error": "withdrawal address is time-locked until " + entry.ActiveAt.UTC().Format(time.RFC3339)})
+		return
+	}
+
+	amount := req.Amount
+	txCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+
+	walletAddr := common.HexToAddress(wallet.Address)
+	amountWei := amount.Shift(18).BigInt()
+	balanceWei, err := h.eth.GetBalance(txCtx, walletAddr)
+	if err != nil {
+		log.Printf("get balance: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read balance"})
+		return
+	}
+	if balanceWei.Cmp(amountWei) < 0 {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "insufficient balance"})
 		return
 	}
 
-	newBalance := wallet.Balance - req.Amount
-	if err := h.repo.UpdateManagedWalletBalance(c.Request.Context(), wallet.ID, newBalance); err != nil {
-		log.Printf("update wallet balance: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update balance"})
+	toAddr := common.HexToAddress(req.ToAddress)
+	policy, err := h.loadSpendingPolicy(ctx, wallet.ID)
+	if err != nil {
+		log.Printf("load spending policy: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load spending policy"})
 		return
 	}
+	if !policy.IsEmpty() {
+		action := service.PolicyAction{
+			Kind:      service.PolicyActionWithdraw,
+			Target:    toAddr.Hex(),
+			AmountBNB: amount,
+			Now:       time.Now(),
+		}
+		if violation := h.enforcePolicy(ctx, wallet, policy, action); violation != nil {
+			respondPolicyViolation(c, violation)
+			return
+		}
+	}
+
+	privateKey, err := decryptPrivateKey(wallet.EncryptedKey)
+	if err != nil {
+		log.Printf("decrypt key: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt key"})
+		return
+	}
+
+	// Reserve the amount against the daily limit before broadcasting so
+	// concurrent withdrawals cannot each pass the check.
+	withdrawal := &model.WalletWithdrawal{
+		UserID:    req.UserID,
+		WalletID:  wallet.ID,
+		ToAddress: toAddr.Hex(),
+		Amount:    amount,
+		Status:    "reserved",
+	}
+	reserved, err := h.repo.ReserveWalletWithdrawal(ctx, withdrawal)
+	if err != nil {
+		log.Printf("reserve withdrawal: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to check withdrawal limit"})
+		return
+	}
+	if !reserved {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "daily withdrawal limit exceeded"})
+		return
+	}
+
+	txHash, err := h.eth.TransferBNB(txCtx, privateKey, toAddr, amountWei)
+	if err != nil {
+		log.Printf("withdraw transfer: %v", err)
+		if err := h.repo.UpdateWalletWithdrawal(ctx, withdrawal.ID, map[string]interface{}{"status": "failed", "error_message": err.Error()}); err != nil {
+			log.Printf("release withdrawal id=%s: %v", withdrawal.ID, err)
+		}
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "withdrawal failed"})
+		return
+	}
+
+	status := "pending"
+	errorMessage := ""
+	receipt, receiptErr := waitForReceipt(txCtx, h.eth, txHash)
+	if receiptErr == nil && receipt != nil {
+		if receipt.Status == 1 {
+			status = "success"
+		} else {
+			status = "failed"
+		}
+	} else if receiptErr != nil {
+		errorMessage = receiptErr.Error()
+	}
+
+	if err := h.repo.UpdateWalletWithdrawal(ctx, withdrawal.ID, map[string]interface{}{
+		"tx_hash":       txHash.Hex(),
+		"status":        status,
+		"error_message": errorMessage,
+	}); err != nil {
+		// The reservation still counts against the limit; report the hash so
+		// the transfer can be reconciled.
+		log.Printf("record withdrawal id=%s tx=%s: %v", withdrawal.ID, txHash.Hex(), err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "withdrawal sent but not recorded", "tx_hash": txHash.Hex()})
+		return
+	}
+
+	balance := wallet.Balance
+	if postBNB, err := h.eth.GetBalance(ctx, walletAddr); err == nil {
+		if value, err := weiToBNB(postBNB); err == nil {
+			balance = value
+			_ = h.repo.UpdateManagedWalletBalance(ctx, wallet.ID, balance)
+		}
+	}
 
 	c.JSON(http.StatusOK, gin.H{"data": WithdrawResponse{
-		UserID:  wallet.UserID,
-		Address: wallet.Address,
-		Balance: newBalance,
+		UserID:    wallet.UserID,
+		Address:   wallet.Address,
+		ToAddress: toAddr.Hex(),
+		TxHash:    txHash.Hex(),
+		Status:    status,
+		Balance:   balance,
 	}})
 }
 
@@ -314,24 +528,36 @@ type ExecuteTradeRequest struct {
 	UserID       string  `json:"userId"`
 	TokenAddress string  `json:"tokenAddress"`
 	TokenSymbol  string  `json:"tokenSymbol"`
-	Type         string  `json:"type"`     // BUY | SELL
-	AmountIn     string  `json:"amountIn"` // BNB for BUY, Token for SELL
-	AmountOut    string  `json:"amountOut"`
+	Type         string  `json:"type"`      // BUY | SELL
+	AmountIn     string  `json:"amountIn"`  // BNB for BUY, Token for SELL
+	AmountOut    string  `json:"amountOut"` // minimum received: Token for BUY, BNB for SELL
 	Reason       string  `json:"decisionReason"`
 	StrategyUsed string  `json:"strategyUsed"`
 	GoldenScore  int     `json:"goldenDogScore"`
 	ProfitLoss   float64 `json:"profitLoss"`
 	Force        bool    `json:"force"`
+	// DecisionTrail is stored on the AITrade to explain automated decisions.
+	DecisionTrail map[string]interface{} `json:"decisionTrail"`
+
+	// approvalID is set when executing a trade a human already approved.
+	approvalID string
+	// emergency marks liquidation sells, which still run while trading is halted.
+	emergency bool
+	// orderID is set when a resting order places the trade.
+	orderID string
 }
 
 // ExecuteTrade godoc
 // @Summary Execute trade
-// @Description Execute managed wallet trade on BSC
+// @Description Execute managed wallet trade on BSC; BUYs above ConfirmThreshold are queued for approval (202), which needs TOTP enabled (403 otherwise). amountOut is the minimum received, in tokens for a BUY and BNB for a SELL
 // @Tags wallet
 // @Param payload body ExecuteTradeRequest true "Execute trade payload"
 // @Success 200 {object} map[string]string
+// @Success 202 {object} map[string]string
 // @Failure 400 {object} map[string]string
+// @Failure 403 {object} map[string]string
 // @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
 // @Router /api/wallet/execute-trade [post]
 func (h *WalletHandler) ExecuteTrade(c *gin.Context) {
 	var req ExecuteTradeRequest
@@ -344,197 +570,226 @@ func (h *WalletHandler) ExecuteTrade(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "missing required fields"})
 		return
 	}
+	req.UserID = userID
 
-	wallet, err := h.repo.GetManagedWalletByUser(c.Request.Context(), userID)
+	trade, err := h.executeTrade(c.Request.Context(), req)
 	if err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		respondTradeError(c, err)
 		return
 	}
 
-	config, _ := h.loadWalletConfig(c.Request.Context(), userID)
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"tx_hash": trade.TxHash}})
+}
+
+// executeTrade runs a managed wallet swap end to end and returns the recorded trade.
+func (h *WalletHandler) executeTrade(ctx context.Context, req ExecuteTradeRequest) (*model.AITrade, error) {
+	if !req.emergency {
+		if err := h.breaker.Check(req.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, req.UserID)
+	if err != nil {
+		return nil, newTradeError(http.StatusNotFound, "wallet not found")
+	}
+
+	config, _ := h.loadWalletConfig(ctx, req.UserID)
+	// Paper wallets skip approvals, spending policies and the breaker's
+	// transaction accounting since nothing reaches the chain.
+	if wallet.IsPaper() {
+		return h.executePaperTrade(ctx, req, wallet, config)
+	}
+	policy, err := h.loadSpendingPolicy(ctx, wallet.ID)
+	if err != nil {
+		log.Printf("load spending policy: %v", err)
+		return nil, newTradeError(http.StatusInternalServerError, "failed to load spending policy")
+	}
 
 	privateKey, err := decryptPrivateKey(wallet.EncryptedKey)
 	if err != nil {
 		log.Printf("decrypt key: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt key"})
-		return
+		return nil, newTradeError(http.StatusInternalServerError, "failed to decrypt key")
 	}
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 45*time.Second)
+	chainCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
 	defer cancel()
 
 	tokenAddr := common.HexToAddress(req.TokenAddress)
 	walletAddr := common.HexToAddress(wallet.Address)
-	preBNB, _ := h.eth.GetBalance(ctx, walletAddr)
-	preToken, _ := h.eth.TokenBalance(ctx, tokenAddr, walletAddr)
+	router := common.HexToAddress(ethereum.PancakeRouterV2)
+	preBNB, _ := h.eth.GetBalance(chainCtx, walletAddr)
+	preToken, _ := h.eth.TokenBalance(chainCtx, tokenAddr, walletAddr)
+	_, _, decimals, infoErr := h.eth.GetTokenInfo(chainCtx, tokenAddr)
+	if infoErr != nil {
+		decimals = 18
+	}
 
 	var txHash common.Hash
+	var amountInWei *big.Int
 	switch strings.ToUpper(req.Type) {
 	case "BUY":
-		amountInWei, err := parseAmountToWei(req.AmountIn, req.Type, h.eth, tokenAddr)
+		amountInWei, err = parseAmountToWei(req.AmountIn, 18)
 		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amountIn"})
-			return
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountIn")
 		}
-		minOutWei, _ := parseAmountToWei(req.AmountOut, req.Type, h.eth, tokenAddr)
-		if config.Enabled {
-			if config.MinGoldenDogScore > 0 && req.GoldenScore < config.MinGoldenDogScore {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "golden dog score below threshold"})
-				return
-			}
-			if config.MaxAmountPerTrade > 0 {
-				if amountIn, err := decimal.NewFromString(req.AmountIn); err == nil {
-					if amountIn.GreaterThan(decimal.NewFromFloat(config.MaxAmountPerTrade)) {
-						c.JSON(http.StatusBadRequest, gin.H{"error": "amount exceeds max per trade"})
-						return
-					}
-				}
-			}
-			if config.DailyBudget > 0 {
-				used, err := h.sumDailyBuyAmount(c.Request.Context(), userID)
-				if err == nil {
-					if current, err := decimal.NewFromString(req.AmountIn); err == nil {
-						if used.Add(current).GreaterThan(decimal.NewFromFloat(config.DailyBudget)) {
-							c.JSON(http.StatusBadRequest, gin.H{"error": "daily budget exceeded"})
-							return
-						}
-					}
-				}
-			}
-			if config.MaxDailyLoss > 0 {
-				loss, err := h.sumDailyLoss(c.Request.Context(), userID)
-				if err == nil && loss.GreaterThan(decimal.NewFromFloat(config.MaxDailyLoss)) {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "max daily loss exceeded"})
-					return
-				}
+		minOutWei, err := parseAmountToWei(req.AmountOut, int32(decimals))
+		if err != nil {
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountOut")
+		}
+		if err := h.checkBuyLimits(ctx, req, config, wallet, amountInWei); err != nil {
+			return nil, err
+		}
+		if balanceWei, err := h.eth.GetBalance(chainCtx, walletAddr); err == nil {
+			if balanceWei.Cmp(amountInWei) < 0 {
+				return nil, newTradeError(http.StatusBadRequest, "insufficient balance")
 			}
 		}
-		if wallet.MaxBalance > 0 {
-			if amountIn, err := decimal.NewFromString(req.AmountIn); err == nil {
-				if amountIn.GreaterThan(decimal.NewFromFloat(wallet.MaxBalance)) {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "amount exceeds max balance limit"})
-					return
-				}
+		if !policy.IsEmpty() {
+			var action service.PolicyAction
+			action, minOutWei = h.buildSwapAction(chainCtx, req.UserID, tokenAddr, true, amountInWei, minOutWei, policy)
+			if violation := h.enforcePolicy(ctx, wallet, policy, action); violation != nil {
+				return nil, violation
 			}
 		}
-		if balanceWei, err := h.eth.GetBalance(ctx, walletAddr); err == nil {
-			if balanceWei.Cmp(amountInWei) < 0 {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "insufficient balance"})
-				return
+		if config.Enabled && config.ConfirmThreshold > 0 && req.approvalID == "" {
+			if amountIn, err := decimal.NewFromString(req.AmountIn); err == nil && amountIn.GreaterThan(decimal.NewFromFloat(config.ConfirmThreshold)) {
+				return nil, h.queueApproval(ctx, req, config)
 			}
 		}
-		txHash, err = h.eth.SwapExactETHForTokens(ctx, privateKey, tokenAddr, amountInWei, minOutWei)
+		txHash, err = h.eth.SwapExactETHForTokens(chainCtx, privateKey, tokenAddr, amountInWei, minOutWei)
 	case "SELL":
-		tokenBalance, balErr := h.eth.TokenBalance(ctx, tokenAddr, walletAddr)
+		tokenBalance, balErr := h.eth.TokenBalance(chainCtx, tokenAddr, walletAddr)
 		if balErr != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read token balance"})
-			return
+			return nil, newTradeError(http.StatusInternalServerError, "failed to read token balance")
 		}
 
-		var amountInWei *big.Int
-		var err error
-		minOutWei, _ := parseAmountToWei(req.AmountOut, req.Type, h.eth, tokenAddr)
-		_, _, decimals, _ := h.eth.GetTokenInfo(ctx, tokenAddr)
+		minOutWei, err := parseAmountToWei(req.AmountOut, 18)
+		if err != nil {
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountOut")
+		}
 
-		if strings.EqualFold(req.AmountIn, "ALL") || strings.EqualFold(req.AmountIn, "100%") {
-			amountInWei = tokenBalance
-			req.AmountIn = formatAmount(amountInWei, int32(decimals))
-		} else if ratio, ok := parseRatioAmount(req.AmountIn); ok {
-			amountInWei = applyRatio(tokenBalance, ratio)
-			req.AmountIn = formatAmount(amountInWei, int32(decimals))
-		} else {
-			amountInWei, err = parseAmountToWei(req.AmountIn, req.Type, h.eth, tokenAddr)
-			if err != nil {
-				c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amountIn"})
-				return
-			}
+		amountInWei, err = resolveSellAmount(req, config, tokenBalance, decimals)
+		if err != nil {
+			return nil, err
 		}
 
-		if config.Enabled && !req.Force {
-			if config.StopLoss < 0 && req.ProfitLoss <= config.StopLoss {
-				amountInWei = tokenBalance
-				req.AmountIn = formatAmount(amountInWei, int32(decimals))
-			} else if len(config.TakeProfitLevels) > 0 {
-				levelIndex := matchedTakeProfitIndex(req.ProfitLoss, config.TakeProfitLevels)
-				if levelIndex < 0 {
-					c.JSON(http.StatusBadRequest, gin.H{"error": "profit target not met"})
-					return
-				}
-				ratio := pickTakeProfitRatio(levelIndex, config.TakeProfitAmounts)
-				if ratio > 0 && ratio < 1 {
-					amountInWei = applyRatio(tokenBalance, ratio)
-					req.AmountIn = formatAmount(amountInWei, int32(decimals))
-				}
+		if !policy.IsEmpty() {
+			var action service.PolicyAction
+			action, minOutWei = h.buildSwapAction(chainCtx, req.UserID, tokenAddr, false, amountInWei, minOutWei, policy)
+			action.Emergency = req.emergency
+			if violation := h.enforcePolicy(ctx, wallet, policy, action); violation != nil {
+				return nil, violation
 			}
 		}
 
-		if tokenBalance.Cmp(amountInWei) < 0 {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "insufficient token balance"})
-			return
+		// Approve exactly what this sell needs rather than leaving a standing allowance.
+		if err := h.ensureAllowance(chainCtx, wallet, policy, privateKey, tokenAddr, router, amountInWei); err != nil {
+			var violation *service.PolicyViolation
+			if errors.As(err, &violation) {
+				return nil, violation
+			}
+			log.Printf("approve token: %v", err)
+			return nil, newTradeError(http.StatusInternalServerError, "approval failed")
 		}
-
-		approveAmount := new(big.Int).Mul(amountInWei, big.NewInt(2))
-		_, _ = h.eth.ApproveToken(ctx, privateKey, tokenAddr, common.HexToAddress(ethereum.PancakeRouterV2), approveAmount)
-		txHash, err = h.eth.SwapExactTokensForETH(ctx, privateKey, tokenAddr, amountInWei, minOutWei)
+		txHash, err = h.eth.SwapExactTokensForETH(chainCtx, privateKey, tokenAddr, amountInWei, minOutWei)
 	default:
-		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid trade type"})
-		return
+		return nil, newTradeError(http.StatusBadRequest, "invalid trade type")
 	}
 	if err != nil {
 		log.Printf("execute trade: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": "trade failed"})
-		return
+		h.breaker.RecordTransaction(ctx, req.UserID, false)
+		return nil, newTradeError(http.StatusInternalServerError, "trade failed")
 	}
 
-	receipt, receiptErr := waitForReceipt(ctx, h.eth, txHash)
+	receipt, receiptErr := waitForReceipt(chainCtx, h.eth, txHash)
 	status := "pending"
 	errorMessage := ""
-	var gasUsed string
+	var gasUsed uint64
 	var blockNumber uint64
+	gasWei := big.NewInt(0)
 	if receiptErr == nil && receipt != nil {
 		if receipt.Status == 1 {
 			status = "success"
 		} else {
 			status = "failed"
 		}
-		gasUsed = strconv.FormatUint(receipt.GasUsed, 10)
+		gasUsed = receipt.GasUsed
 		blockNumber = receipt.BlockNumber.Uint64()
+		if receipt.EffectiveGasPrice != nil {
+			gasWei = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		}
 	} else if receiptErr != nil {
 		errorMessage = receiptErr.Error()
 	}
+	gasFee := decimal.NewFromBigInt(gasWei, -18)
 
-	postBNB, _ := h.eth.GetBalance(ctx, walletAddr)
-	postToken, _ := h.eth.TokenBalance(ctx, tokenAddr, walletAddr)
-	amountOut := ""
-	profitLoss := 0.0
+	postBNB, _ := h.eth.GetBalance(chainCtx, walletAddr)
+	postToken, _ := h.eth.TokenBalance(chainCtx, tokenAddr, walletAddr)
+	// Token amounts use the token's decimals and BNB amounts 18.
+	inDecimals, outDecimals := int32(18), int32(decimals)
+	amountOutWei := big.NewInt(0)
+	var realized *model.RealizedPnL
 	if strings.ToUpper(req.Type) == "BUY" {
-		if postToken != nil && preToken != nil {
+		// Transfer logs give the exact fill even when other trades move the balance.
+		if received := ethereum.TokensReceived(receipt, tokenAddr, walletAddr); received != nil && received.Sign() > 0 {
+			amountOutWei = received
+		} else if postToken != nil && preToken != nil {
 			if delta := new(big.Int).Sub(postToken, preToken); delta.Sign() > 0 {
-				_, _, decimals, _ := h.eth.GetTokenInfo(ctx, tokenAddr)
-				amountOut = formatAmount(delta, int32(decimals))
+				amountOutWei = delta
 			}
 		}
-		h.upsertPositionAfterBuy(c.Request.Context(), userID, req.TokenAddress, req.TokenSymbol, req.AmountIn, amountOut)
+		// A pending buy is booked by the receipt poller once it settles.
+		if status == "success" {
+			h.bookBuy(ctx, req.UserID, req.TokenAddress, req.TokenSymbol, txHash.Hex(),
+				decimal.NewFromBigInt(amountInWei, -inDecimals), decimal.NewFromBigInt(amountOutWei, -outDecimals), gasFee, false)
+		}
 	} else {
-		if postBNB != nil && preBNB != nil {
-			if delta := new(big.Int).Sub(postBNB, preBNB); delta.Sign() > 0 {
-				amountOut = formatAmount(delta, 18)
+		inDecimals, outDecimals = int32(decimals), 18
+		// Receipt logs give the exact proceeds even when other trades move the balance.
+		if proceeds := ethereum.SellProceeds(receipt); proceeds != nil && proceeds.Sign() > 0 {
+			amountOutWei = proceeds
+		} else if postBNB != nil && preBNB != nil {
+			// The balance already paid for gas; add it back so the ledger
+			// does not charge it twice.
+			delta := new(big.Int).Sub(postBNB, preBNB)
+			if delta.Add(delta, gasWei); delta.Sign() > 0 {
+				amountOutWei = delta
 			}
 		}
-		profitLoss = h.applyPositionAfterSell(c.Request.Context(), userID, req.TokenAddress, amountOut, req.AmountIn)
+		if status == "success" {
+			sellQty := decimal.NewFromBigInt(amountInWei, -inDecimals)
+			proceeds := decimal.NewFromBigInt(amountOutWei, -outDecimals)
+			realized = h.applyPositionAfterSell(ctx, req.UserID, req.TokenAddress, req.TokenSymbol, txHash.Hex(), proceeds, sellQty, gasFee, config.CostBasis, false)
+		}
+		h.syncAllowance(chainCtx, wallet, tokenAddr, router, "")
+	}
+	if status == "failed" {
+		if err := h.ledger.RecordGasLoss(ctx, req.UserID, req.TokenAddress, req.TokenSymbol, txHash.Hex(), gasFee, false); err != nil {
+			log.Printf("record gas loss: %v", err)
+		}
 	}
 
 	if balance, err := weiToBNB(postBNB); err == nil {
-		_ = h.repo.UpdateManagedWalletBalance(c.Request.Context(), wallet.ID, balance)
+		_ = h.repo.UpdateManagedWalletBalance(ctx, wallet.ID, balance)
 	}
 
+	amountIn := decimal.NewFromBigInt(amountInWei, -inDecimals)
+	// BUYs start valued at their cost; the valuator re-marks them periodically.
+	currentValue := decimal.Zero
+	if strings.ToUpper(req.Type) == "BUY" && status == "success" {
+		currentValue = amountIn
+	}
 	aiTrade := &model.AITrade{
-		UserID:         userID,
+		UserID:         req.UserID,
 		TokenAddress:   req.TokenAddress,
 		TokenSymbol:    req.TokenSymbol,
 		Type:           strings.ToUpper(req.Type),
-		AmountIn:       req.AmountIn,
-		AmountOut:      amountOut,
+		AmountIn:       amountIn,
+		AmountOut:      decimal.NewFromBigInt(amountOutWei, -outDecimals),
+		AmountInWei:    decimal.NewFromBigInt(amountInWei, 0),
+		AmountOutWei:   decimal.NewFromBigInt(amountOutWei, 0),
+		TokenDecimals:  int(decimals),
 		TxHash:         txHash.Hex(),
 		Status:         status,
 		GasUsed:        gasUsed,
@@ -542,16 +797,104 @@ func (h *WalletHandler) ExecuteTrade(c *gin.Context) {
 		GoldenDogScore: req.GoldenScore,
 		DecisionReason: req.Reason,
 		StrategyUsed:   req.StrategyUsed,
-		CurrentValue:   "",
-		ProfitLoss:     profitLoss,
+		OrderID:        req.orderID,
+		CurrentValue:   currentValue,
+		GasFeeBNB:      gasFee,
 		ErrorMessage:   errorMessage,
 	}
-	_ = h.repo.CreateAITrade(c.Request.Context(), aiTrade)
+	if realized != nil {
+		aiTrade.ProfitLoss = service.ReturnRatio(realized)
+		aiTrade.RealizedPLBNB = realized.RealizedBNB
+		aiTrade.RealizedPLUSD = realized.RealizedUSD.Round(2)
+	}
+	if len(req.DecisionTrail) > 0 {
+		aiTrade.DecisionTrail, _ = json.Marshal(req.DecisionTrail)
+	}
+	_ = h.repo.CreateAITrade(ctx, aiTrade)
+
+	if status != "pending" {
+		h.breaker.RecordTransaction(ctx, req.UserID, status == "success")
+	}
+	if status == "success" && aiTrade.Type == "SELL" {
+		h.breaker.RecordSell(ctx, req.UserID, aiTrade.ProfitLoss.InexactFloat64(), config.MaxDailyLoss)
+	}
+
+	return aiTrade, nil
+}
+
+// checkBuyLimits applies the auto-trade config and max balance limits to a
+// BUY of amountInWei.
+func (h *WalletHandler) checkBuyLimits(ctx context.Context, req ExecuteTradeRequest, config AutoTradeConfig, wallet *model.ManagedWallet, amountInWei *big.Int) error {
+	amountIn := decimal.NewFromBigInt(amountInWei, -18)
+	if config.Enabled {
+		if config.MinGoldenDogScore > 0 && req.GoldenScore < config.MinGoldenDogScore {
+			return newTradeError(http.StatusBadRequest, "golden dog score below threshold")
+		}
+		if config.MaxAmountPerTrade > 0 && amountIn.GreaterThan(decimal.NewFromFloat(config.MaxAmountPerTrade)) {
+			return newTradeError(http.StatusBadRequest, "amount exceeds max per trade")
+		}
+		if config.DailyBudget > 0 {
+			used, err := h.sumDailyBuyAmount(ctx, req.UserID)
+			if err == nil && used.Add(amountIn).GreaterThan(decimal.NewFromFloat(config.DailyBudget)) {
+				return newTradeError(http.StatusBadRequest, "daily budget exceeded")
+			}
+		}
+		if config.MaxDailyLoss > 0 {
+			loss, err := h.sumDailyLoss(ctx, req.UserID)
+			if err == nil && loss.GreaterThan(decimal.NewFromFloat(config.MaxDailyLoss)) {
+				return newTradeError(http.StatusBadRequest, "max daily loss exceeded")
+			}
+		}
+	}
+	if wallet.MaxBalance.IsPositive() && amountIn.GreaterThan(wallet.MaxBalance) {
+		return newTradeError(http.StatusBadRequest, "amount exceeds max balance limit")
+	}
+	return nil
+}
+
+// resolveSellAmount turns a SELL amountIn (a token amount, a ratio or ALL)
+// into base units of tokenBalance and applies the stop-loss and take-profit
+// rules unless the sell is forced.
+func resolveSellAmount(req ExecuteTradeRequest, config AutoTradeConfig, tokenBalance *big.Int, decimals uint8) (*big.Int, error) {
+	var amountInWei *big.Int
+	if strings.EqualFold(req.AmountIn, "ALL") || strings.EqualFold(req.AmountIn, "100%") {
+		amountInWei = tokenBalance
+	} else if ratio, ok := parseRatioAmount(req.AmountIn); ok {
+		amountInWei = applyRatio(tokenBalance, ratio)
+	} else {
+		amount, err := decimal.NewFromString(strings.TrimSpace(req.AmountIn))
+		if err != nil {
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountIn")
+		}
+		amountInWei = amount.Shift(int32(decimals)).BigInt()
+	}
+
+	if config.Enabled && !req.Force {
+		if config.StopLoss < 0 && req.ProfitLoss <= config.StopLoss {
+			amountInWei = tokenBalance
+		} else if len(config.TakeProfitLevels) > 0 {
+			levelIndex := matchedTakeProfitIndex(req.ProfitLoss, config.TakeProfitLevels)
+			if levelIndex < 0 {
+				return nil, newTradeError(http.StatusBadRequest, "profit target not met")
+			}
+			ratio := pickTakeProfitRatio(levelIndex, config.TakeProfitAmounts)
+			if ratio > 0 && ratio < 1 {
+				amountInWei = applyRatio(tokenBalance, ratio)
+			}
+		}
+	}
 
-	c.JSON(http.StatusOK, gin.H{"data": gin.H{"tx_hash": txHash.Hex()}})
+	if tokenBalance.Cmp(amountInWei) < 0 {
+		return nil, newTradeError(http.StatusBadRequest, "insufficient token balance")
+	}
+	return amountInWei, nil
 }
 
 func encryptPrivateKey(privateKey []byte) ([]byte, error) {
+	return encryptSecret(privateKey)
+}
+
+func encryptSecret(plain []byte) ([]byte, error) {
 	master := strings.TrimSpace(os.Getenv("WALLET_MASTER_KEY"))
 	if master == "" {
 		return nil, ErrMissingMasterKey
@@ -569,7 +912,7 @@ func encryptPrivateKey(privateKey []byte) ([]byte, error) {
 	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
 		return nil, err
 	}
-	ciphertext := gcm.Seal(nil, nonce, privateKey, nil)
+	ciphertext := gcm.Seal(nil, nonce, plain, nil)
 	out := make([]byte, 0, len(nonce)+len(ciphertext))
 	out = append(out, nonce...)
 	out = append(out, ciphertext...)
@@ -582,19 +925,82 @@ type errorString struct{ s string }
 
 func (e *errorString) Error() string { return e.s }
 
-type AutoTradeConfig struct {
-	Enabled           bool      `json:"enabled"`
-	MaxAmountPerTrade float64   `json:"maxAmountPerTrade"`
-	MinGoldenDogScore int       `json:"minGoldenDogScore"`
-	DailyBudget       float64   `json:"dailyBudget"`
-	ConfirmThreshold  float64   `json:"confirmThreshold"`
-	MaxDailyLoss      float64   `json:"maxDailyLoss"`
-	TakeProfitLevels  []float64 `json:"takeProfitLevels"`
-	TakeProfitAmounts []float64 `json:"takeProfitAmounts"`
-	StopLoss          float64   `json:"stopLoss"`
+// tradeError is a trade rejection that maps onto an HTTP status.
+type tradeError struct {
+	status  int
+	message string
 }
 
+func (e *tradeError) Error() string { return e.message }
+
+func newTradeError(status int, message string) error {
+	return &tradeError{status: status, message: message}
+}
+
+func respondTradeError(c *gin.Context, err error) {
+	var violation *service.PolicyViolation
+	if errors.As(err, &violation) {
+		respondPolicyViolation(c, violation)
+		return
+	}
+	var halted *service.TradingHalted
+	if errors.As(err, &halted) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": halted.Error()})
+		return
+	}
+	var pending *service.ApprovalRequired
+	if errors.As(err, &pending) {
+		c.JSON(http.StatusAccepted, gin.H{"status": "pending_approval", "approvalId": pending.ApprovalID})
+		return
+	}
+	var te *tradeError
+	if errors.As(err, &te) {
+		c.JSON(te.status, gin.H{"error": te.message})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": "trade failed"})
+}
+
+// ExecuteManagedTrade lets background services trade through the same checks as the API.
+func (h *WalletHandler) ExecuteManagedTrade(ctx context.Context, req service.ManagedTradeRequest) (*service.ManagedTradeResult, error) {
+	trade, err := h.executeTrade(ctx, ExecuteTradeRequest{
+		UserID:        req.UserID,
+		TokenAddress:  req.TokenAddress,
+		TokenSymbol:   req.TokenSymbol,
+		Type:          req.Type,
+		AmountIn:      req.AmountIn,
+		AmountOut:     req.AmountOut,
+		Reason:        req.Reason,
+		StrategyUsed:  req.StrategyUsed,
+		GoldenScore:   req.GoldenScore,
+		Force:         req.Force,
+		DecisionTrail: req.DecisionTrail,
+		orderID:       req.OrderID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &service.ManagedTradeResult{
+		TradeID:    trade.ID,
+		TxHash:     trade.TxHash,
+		Status:     trade.Status,
+		AmountIn:   trade.AmountIn.String(),
+		AmountOut:  trade.AmountOut.String(),
+		ProfitLoss: trade.ProfitLoss.InexactFloat64(),
+	}, nil
+}
+
+type AutoTradeConfig = service.AutoTradeConfig
+
 func decryptPrivateKey(cipherHex []byte) (*ecdsa.PrivateKey, error) {
+	plain, err := decryptSecret(cipherHex)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.ToECDSA(plain)
+}
+
+func decryptSecret(cipherHex []byte) ([]byte, error) {
 	master := strings.TrimSpace(os.Getenv("WALLET_MASTER_KEY"))
 	if master == "" {
 		return nil, ErrMissingMasterKey
@@ -617,53 +1023,46 @@ func decryptPrivateKey(cipherHex []byte) (*ecdsa.PrivateKey, error) {
 	}
 	nonce := raw[:gcm.NonceSize()]
 	ciphertext := raw[gcm.NonceSize():]
-	plain, err := gcm.Open(nil, nonce, ciphertext, nil)
-	if err != nil {
-		return nil, err
-	}
-	return crypto.ToECDSA(plain)
+	return gcm.Open(nil, nonce, ciphertext, nil)
 }
 
-func parseAmountToWei(amount string, tradeType string, client *ethereum.Client, tokenAddr common.Address) (*big.Int, error) {
+// parseAmountToWei converts a display amount into base units of an asset
+// with the given decimals; BNB has 18. An empty amount is 0.
+func parseAmountToWei(amount string, decimals int32) (*big.Int, error) {
 	if strings.TrimSpace(amount) == "" {
 		return big.NewInt(0), nil
 	}
-	decimals := int32(18)
-	if strings.ToUpper(tradeType) == "SELL" {
-		if client == nil {
-			return nil, errors.New("missing client")
-		}
-		_, _, tokenDecimals, err := client.GetTokenInfo(context.Background(), tokenAddr)
-		if err == nil {
-			decimals = int32(tokenDecimals)
-		}
-	}
-	value, err := decimal.NewFromString(amount)
+	value, err := decimal.NewFromString(strings.TrimSpace(amount))
 	if err != nil {
 		return nil, err
 	}
+	if value.IsNegative() {
+		return nil, errors.New("negative amount")
+	}
 	scale := decimal.NewFromInt(1).Shift(decimals)
 	wei := value.Mul(scale).BigInt()
 	return wei, nil
 }
 
-func weiToBNB(value *big.Int) (float64, error) {
+func weiToBNB(value *big.Int) (decimal.Decimal, error) {
 	if value == nil {
-		return 0, errors.New("nil value")
+		return decimal.Zero, errors.New("nil value")
 	}
-	dec := decimal.NewFromBigInt(value, 0)
-	bnb := dec.Div(decimal.NewFromInt(1).Shift(18))
-	f, _ := bnb.Float64()
-	return f, nil
+	return decimal.NewFromBigInt(value, -18), nil
 }
 
+// formatAmount renders base units in display units without rounding.
 func formatAmount(value *big.Int, decimals int32) string {
 	if value == nil {
 		return ""
 	}
-	dec := decimal.NewFromBigInt(value, 0)
-	out := dec.Div(decimal.NewFromInt(1).Shift(decimals))
-	return out.String()
+	return decimal.NewFromBigInt(value, -decimals).String()
+}
+
+// toBaseUnits converts a display amount to on-chain base units, dropping
+// anything below one base unit.
+func toBaseUnits(amount decimal.Decimal, decimals int) decimal.Decimal {
+	return amount.Shift(int32(decimals)).Truncate(0)
 }
 
 func waitForReceipt(ctx context.Context, client *ethereum.Client, hash common.Hash) (*types.Receipt, error) {
@@ -691,8 +1090,8 @@ func (h *WalletHandler) estimateProfitLoss(ctx context.Context, userID, tokenAdd
 	if err != nil || lastBuy == nil {
 		return 0
 	}
-	buyIn, err := decimal.NewFromString(lastBuy.AmountIn)
-	if err != nil || buyIn.Equal(decimal.Zero) {
+	buyIn := lastBuy.AmountIn
+	if buyIn.IsZero() {
 		return 0
 	}
 	sellOut, err := decimal.NewFromString(amountOut)
@@ -704,28 +1103,28 @@ func (h *WalletHandler) estimateProfitLoss(ctx context.Context, userID, tokenAdd
 	return f
 }
 
+// bookBuy opens a lot for a settled buy and adds it to the position; both
+// carry gas in their cost basis.
+func (h *WalletHandler) bookBuy(ctx context.Context, userID, tokenAddress, tokenSymbol, txHash string, amountIn, qty, gasFee decimal.Decimal, simulated bool) {
+	cost := amountIn.Add(gasFee)
+	h.upsertPositionAfterBuy(ctx, userID, tokenAddress, tokenSymbol, cost, qty)
+	if err := h.ledger.RecordBuy(ctx, userID, tokenAddress, txHash, qty, cost, simulated); err != nil {
+		log.Printf("record position lot: %v", err)
+	}
+}
+
 func (h *WalletHandler) upsertPositionAfterBuy(
 	ctx context.Context,
 	userID string,
 	tokenAddress string,
 	tokenSymbol string,
-	amountInBNB string,
-	amountOutToken string,
+	buyCost decimal.Decimal,
+	buyQty decimal.Decimal,
 ) {
-	if amountOutToken == "" || amountInBNB == "" {
-		return
-	}
-	buyCost, err := decimal.NewFromString(amountInBNB)
-	if err != nil {
-		return
-	}
-	buyQty, err := decimal.NewFromString(amountOutToken)
-	if err != nil {
-		return
-	}
 	if buyQty.LessThanOrEqual(decimal.Zero) {
 		return
 	}
+	now := time.Now().UTC()
 	pos, err := h.repo.GetAIPosition(ctx, userID, tokenAddress)
 	if err != nil || pos == nil {
 		pos = &model.AIPosition{
@@ -734,58 +1133,79 @@ func (h *WalletHandler) upsertPositionAfterBuy(
 			TokenSymbol:  tokenSymbol,
 			Quantity:     buyQty,
 			CostBNB:      buyCost,
+			OpenedAt:     &now,
 		}
 		_ = h.repo.UpsertAIPosition(ctx, pos)
 		return
 	}
+	if pos.Quantity.LessThanOrEqual(decimal.Zero) {
+		// Re-entry after a full exit starts a fresh take-profit ladder, peak and holding clock.
+		pos.TakeProfitHits = 0
+		pos.PeakPriceBNB = decimal.Zero
+		pos.PeakAt = nil
+		pos.OpenedAt = &now
+	}
 	pos.Quantity = pos.Quantity.Add(buyQty)
 	pos.CostBNB = pos.CostBNB.Add(buyCost)
 	pos.TokenSymbol = tokenSymbol
 	_ = h.repo.UpsertAIPosition(ctx, pos)
 }
 
+// applyPositionAfterSell books the sell in the lot ledger and reduces the
+// position by the cost basis of the tokens sold.
 func (h *WalletHandler) applyPositionAfterSell(
 	ctx context.Context,
 	userID string,
 	tokenAddress string,
-	amountOutBNB string,
-	amountInToken string,
-) float64 {
-	if amountOutBNB == "" || amountInToken == "" {
-		return 0
-	}
-	sellOut, err := decimal.NewFromString(amountOutBNB)
-	if err != nil {
-		return 0
-	}
-	sellQty, err := decimal.NewFromString(amountInToken)
-	if err != nil {
-		return 0
-	}
-	if sellQty.LessThanOrEqual(decimal.Zero) {
-		return 0
+	tokenSymbol string,
+	txHash string,
+	sellOut decimal.Decimal,
+	sellQty decimal.Decimal,
+	gasFee decimal.Decimal,
+	costBasis string,
+	simulated bool,
+) *model.RealizedPnL {
+	if sellOut.LessThanOrEqual(decimal.Zero) || sellQty.LessThanOrEqual(decimal.Zero) {
+		return nil
 	}
 	pos, err := h.repo.GetAIPosition(ctx, userID, tokenAddress)
 	if err != nil || pos == nil || pos.Quantity.LessThanOrEqual(decimal.Zero) {
-		return 0
-	}
-	avgCost := pos.CostBNB.Div(pos.Quantity)
-	costSold := avgCost.Mul(sellQty)
-	if costSold.LessThanOrEqual(decimal.Zero) {
-		return 0
+		pos = nil
+	}
+	fallback := decimal.Zero
+	if pos != nil {
+		fallback = pos.CostBNB.Div(pos.Quantity)
+	}
+
+	entry, err := h.ledger.RecordSell(ctx, service.SellLedgerInput{
+		UserID:               userID,
+		TokenAddress:         tokenAddress,
+		TokenSymbol:          tokenSymbol,
+		TxHash:               txHash,
+		Quantity:             sellQty,
+		ProceedsBNB:          sellOut,
+		GasBNB:               gasFee,
+		CostBasis:            costBasis,
+		FallbackCostPerToken: fallback,
+		Simulated:            simulated,
+	})
+	if err != nil {
+		log.Printf("record realized pnl: %v", err)
+		return nil
 	}
-	pl := sellOut.Sub(costSold).Div(costSold)
-	f, _ := pl.Float64()
 
-	pos.Quantity = pos.Quantity.Sub(sellQty)
-	pos.CostBNB = pos.CostBNB.Sub(costSold)
-	if pos.Quantity.LessThan(decimal.Zero) {
-		pos.Quantity = decimal.Zero
-		pos.CostBNB = decimal.Zero
+	if pos != nil {
+		pos.Quantity = pos.Quantity.Sub(sellQty)
+		pos.CostBNB = pos.CostBNB.Sub(entry.CostBNB)
+		if pos.Quantity.LessThanOrEqual(decimal.Zero) || pos.CostBNB.LessThan(decimal.Zero) {
+			pos.CostBNB = decimal.Zero
+		}
+		if pos.Quantity.LessThan(decimal.Zero) {
+			pos.Quantity = decimal.Zero
+		}
+		_ = h.repo.UpsertAIPosition(ctx, pos)
 	}
-	_ = h.repo.UpsertAIPosition(ctx, pos)
-
-	return f
+	return entry
 }
 
 func matchedTakeProfitIndex(value float64, levels []float64) int {
@@ -853,36 +1273,11 @@ func (h *WalletHandler) loadWalletConfig(ctx context.Context, userID string) (Au
 }
 
 func (h *WalletHandler) sumDailyBuyAmount(ctx context.Context, userID string) (decimal.Decimal, error) {
-	since := time.Now().Add(-24 * time.Hour)
-	trades, err := h.repo.GetAITradesByUserSince(ctx, userID, since)
-	if err != nil {
-		return decimal.Zero, err
-	}
-	total := decimal.Zero
-	for _, t := range trades {
-		if strings.ToUpper(t.Type) != "BUY" {
-			continue
-		}
-		if v, err := decimal.NewFromString(t.AmountIn); err == nil {
-			total = total.Add(v)
-		}
-	}
-	return total, nil
+	return service.DailyBuyAmount(ctx, h.repo, userID)
 }
 
 func (h *WalletHandler) sumDailyLoss(ctx context.Context, userID string) (decimal.Decimal, error) {
-	since := time.Now().Add(-24 * time.Hour)
-	trades, err := h.repo.GetAITradesByUserSince(ctx, userID, since)
-	if err != nil {
-		return decimal.Zero, err
-	}
-	total := decimal.Zero
-	for _, t := range trades {
-		if t.ProfitLoss < 0 {
-			total = total.Add(decimal.NewFromFloat(-t.ProfitLoss))
-		}
-	}
-	return total, nil
+	return service.DailyLoss(ctx, h.repo, userID)
 }
 
 func profitMeetsTakeProfit(value float64, levels []float64) bool {
@@ -0,0 +1,323 @@
+package handler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+var errApprovalFailed = errors.New("approval transaction failed")
+
+type AllowanceResponse struct {
+	TokenAddress  string    `json:"tokenAddress"`
+	TokenSymbol   string    `json:"tokenSymbol"`
+	Spender       string    `json:"spender"`
+	Amount        string    `json:"amount"`
+	LastTxHash    string    `json:"lastTxHash"`
+	LastCheckedAt time.Time `json:"lastCheckedAt"`
+}
+
+// ListAllowances godoc
+// @Summary List token allowances
+// @Description List outstanding token approvals of the managed wallet, refreshed from chain
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Success 200 {object} map[string][]AllowanceResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/allowances [get]
+func (h *WalletHandler) ListAllowances(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+
+	allowances, err := h.refreshAllowances(ctx, wallet)
+	if err != nil {
+		log.Printf("refresh allowances: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load allowances"})
+		return
+	}
+
+	symbols := map[string]string{}
+	if positions, err := h.repo.ListAIPositionsByUser(ctx, userID); err == nil {
+		for _, pos := range positions {
+			symbols[strings.ToLower(pos.TokenAddress)] = pos.TokenSymbol
+		}
+	}
+
+	resp := make([]AllowanceResponse, 0, len(allowances))
+	for _, a := range allowances {
+		if a.Amount.Sign() <= 0 {
+			continue
+		}
+		resp = append(resp, AllowanceResponse{
+			TokenAddress:  a.TokenAddress,
+			TokenSymbol:   symbols[strings.ToLower(a.TokenAddress)],
+			Spender:       a.Spender,
+			Amount:        a.Amount.String(),
+			LastTxHash:    a.LastTxHash,
+			LastCheckedAt: a.LastCheckedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+type RevokeAllowanceRequest struct {
+	UserID       string `json:"userId"`
+	TokenAddress string `json:"tokenAddress"` // empty revokes every outstanding approval
+	Spender      string `json:"spender"`      // defaults to the PancakeSwap router
+}
+
+type RevokeAllowanceResult struct {
+	TokenAddress string `json:"tokenAddress"`
+	Spender      string `json:"spender"`
+	TxHash       string `json:"txHash"`
+	Status       string `json:"status"`
+	Error        string `json:"error,omitempty"`
+}
+
+// RevokeAllowance godoc
+// @Summary Revoke token allowances
+// @Description Reset approvals to zero for one token or for every outstanding approval
+// @Tags wallet
+// @Param payload body RevokeAllowanceRequest true "Revoke payload"
+// @Success 200 {object} map[string][]RevokeAllowanceResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/allowances/revoke [post]
+func (h *WalletHandler) RevokeAllowance(c *gin.Context) {
+	var req RevokeAllowanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if req.TokenAddress != "" && !common.IsHexAddress(req.TokenAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tokenAddress"})
+		return
+	}
+	if req.Spender != "" && !common.IsHexAddress(req.Spender) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid spender"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, req.UserID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+
+	targets := []model.TokenAllowance{}
+	if req.TokenAddress != "" {
+		spender := common.HexToAddress(ethereum.PancakeRouterV2).Hex()
+		if req.Spender != "" {
+			spender = common.HexToAddress(req.Spender).Hex()
+		}
+		targets = append(targets, model.TokenAllowance{
+			TokenAddress: common.HexToAddress(req.TokenAddress).Hex(),
+			Spender:      spender,
+		})
+	} else {
+		allowances, err := h.refreshAllowances(ctx, wallet)
+		if err != nil {
+			log.Printf("refresh allowances: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load allowances"})
+			return
+		}
+		for _, a := range allowances {
+			if a.Amount.Sign() > 0 {
+				targets = append(targets, a)
+			}
+		}
+	}
+
+	privateKey, err := decryptPrivateKey(wallet.EncryptedKey)
+	if err != nil {
+		log.Printf("decrypt key: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt key"})
+		return
+	}
+
+	results := make([]RevokeAllowanceResult, 0, len(targets))
+	for _, target := range targets {
+		result := RevokeAllowanceResult{TokenAddress: target.TokenAddress, Spender: target.Spender}
+		txCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+		// Revocations only shrink exposure, so they bypass the spending policy.
+		txHash, err := h.approveAndWait(txCtx, privateKey, common.HexToAddress(target.TokenAddress), common.HexToAddress(target.Spender), big.NewInt(0))
+		cancel()
+		result.TxHash = txHash.Hex()
+		if err != nil {
+			result.Status = "failed"
+			result.Error = err.Error()
+		} else {
+			result.Status = "success"
+		}
+		h.syncAllowance(ctx, wallet, common.HexToAddress(target.TokenAddress), common.HexToAddress(target.Spender), result.TxHash)
+		results = append(results, result)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+// ensureAllowance approves spender for amount unless the on-chain allowance
+// already covers it, and waits for the approval to confirm.
+func (h *WalletHandler) ensureAllowance(
+	ctx context.Context,
+	wallet *model.ManagedWallet,
+	policy service.SpendingPolicy,
+	privateKey *ecdsa.PrivateKey,
+	tokenAddr common.Address,
+	spender common.Address,
+	amount *big.Int,
+) error {
+	walletAddr := common.HexToAddress(wallet.Address)
+	if current, err := h.eth.Allowance(ctx, tokenAddr, walletAddr, spender); err == nil {
+		h.recordAllowance(ctx, wallet, tokenAddr, spender, current, "")
+		if current.Cmp(amount) >= 0 {
+			return nil
+		}
+	}
+
+	if !policy.IsEmpty() {
+		approval := service.PolicyAction{
+			Kind:         service.PolicyActionApprove,
+			Target:       tokenAddr.Hex(),
+			Router:       spender.Hex(),
+			TokenAddress: tokenAddr.Hex(),
+			Now:          time.Now(),
+		}
+		if violation := h.enforcePolicy(ctx, wallet, policy, approval); violation != nil {
+			return violation
+		}
+	}
+
+	txHash, err := h.approveAndWait(ctx, privateKey, tokenAddr, spender, amount)
+	if err != nil {
+		return err
+	}
+	h.syncAllowance(ctx, wallet, tokenAddr, spender, txHash.Hex())
+	return nil
+}
+
+func (h *WalletHandler) approveAndWait(ctx context.Context, privateKey *ecdsa.PrivateKey, tokenAddr, spender common.Address, amount *big.Int) (common.Hash, error) {
+	txHash, err := h.eth.ApproveToken(ctx, privateKey, tokenAddr, spender, amount)
+	if err != nil {
+		return txHash, err
+	}
+	receipt, err := waitForReceipt(ctx, h.eth, txHash)
+	if err != nil {
+		return txHash, err
+	}
+	if receipt.Status != 1 {
+		return txHash, errApprovalFailed
+	}
+	return txHash, nil
+}
+
+// syncAllowance stores the current on-chain allowance for token and spender.
+func (h *WalletHandler) syncAllowance(ctx context.Context, wallet *model.ManagedWallet, tokenAddr, spender common.Address, txHash string) {
+	current, err := h.eth.Allowance(ctx, tokenAddr, common.HexToAddress(wallet.Address), spender)
+	if err != nil {
+		log.Printf("read allowance token=%s spender=%s: %v", tokenAddr.Hex(), spender.Hex(), err)
+		return
+	}
+	h.recordAllowance(ctx, wallet, tokenAddr, spender, current, txHash)
+}
+
+func (h *WalletHandler) recordAllowance(ctx context.Context, wallet *model.ManagedWallet, tokenAddr, spender common.Address, amount *big.Int, txHash string) {
+	if err := h.repo.UpsertTokenAllowance(ctx, &model.TokenAllowance{
+		WalletID:      wallet.ID,
+		UserID:        wallet.UserID,
+		TokenAddress:  tokenAddr.Hex(),
+		Spender:       spender.Hex(),
+		Amount:        decimal.NewFromBigInt(amount, 0),
+		LastTxHash:    txHash,
+		LastCheckedAt: time.Now().UTC(),
+	}); err != nil {
+		log.Printf("record allowance: %v", err)
+	}
+}
+
+// refreshAllowances re-reads every tracked approval plus the router approval of
+// each held token, so approvals made before tracking existed are found too.
+func (h *WalletHandler) refreshAllowances(ctx context.Context, wallet *model.ManagedWallet) ([]model.TokenAllowance, error) {
+	tracked, err := h.repo.ListTokenAllowances(ctx, wallet.ID)
+	if err != nil {
+		return nil, err
+	}
+	type pairKey struct{ token, spender string }
+	seen := map[pairKey]bool{}
+	pairs := make([]pairKey, 0, len(tracked))
+	for _, a := range tracked {
+		key := pairKey{a.TokenAddress, a.Spender}
+		if !seen[key] {
+			seen[key] = true
+			pairs = append(pairs, key)
+		}
+	}
+	router := common.HexToAddress(ethereum.PancakeRouterV2).Hex()
+	if positions, err := h.repo.ListAIPositionsByUser(ctx, wallet.UserID); err == nil {
+		for _, pos := range positions {
+			key := pairKey{common.HexToAddress(pos.TokenAddress).Hex(), router}
+			if !seen[key] {
+				seen[key] = true
+				pairs = append(pairs, key)
+			}
+		}
+	}
+
+	walletAddr := common.HexToAddress(wallet.Address)
+	now := time.Now().UTC()
+	out := make([]model.TokenAllowance, 0, len(pairs))
+	for _, key := range pairs {
+		tokenAddr := common.HexToAddress(key.token)
+		spender := common.HexToAddress(key.spender)
+		current, err := h.eth.Allowance(ctx, tokenAddr, walletAddr, spender)
+		if err != nil {
+			log.Printf("read allowance token=%s spender=%s: %v", key.token, key.spender, err)
+			continue
+		}
+		h.recordAllowance(ctx, wallet, tokenAddr, spender, current, "")
+		out = append(out, model.TokenAllowance{
+			WalletID:      wallet.ID,
+			UserID:        wallet.UserID,
+			TokenAddress:  tokenAddr.Hex(),
+			Spender:       spender.Hex(),
+			Amount:        decimal.NewFromBigInt(current, 0),
+			LastCheckedAt: now,
+		})
+	}
+	for i := range out {
+		for _, a := range tracked {
+			if a.TokenAddress == out[i].TokenAddress && a.Spender == out[i].Spender {
+				out[i].LastTxHash = a.LastTxHash
+			}
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,404 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	approvalStatusPending  = "pending"
+	approvalStatusApproved = "approved"
+	approvalStatusRejected = "rejected"
+	approvalStatusExpired  = "expired"
+	approvalStatusExecuted = "executed"
+	approvalStatusFailed   = "failed"
+)
+
+const defaultApprovalTTL = 30 * time.Minute
+
+// queueApproval holds a trade above ConfirmThreshold until a human approves it.
+// A pending approval for the same token and side is reused instead of duplicated.
+// Only a TOTP code can release a held trade, so users without TOTP enabled
+// cannot queue one.
+func (h *WalletHandler) queueApproval(ctx context.Context, req ExecuteTradeRequest, config AutoTradeConfig) error {
+	if sec, err := h.repo.GetWalletSecurity(ctx, req.UserID); err != nil || !sec.TOTPEnabled {
+		return newTradeError(http.StatusForbidden, "trades above confirmThreshold require totp to be enabled")
+	}
+	tradeType := strings.ToUpper(req.Type)
+	if existing, err := h.repo.GetPendingTradeApproval(ctx, req.UserID, req.TokenAddress, tradeType); err == nil {
+		return &service.ApprovalRequired{ApprovalID: existing.ID}
+	}
+
+	ttl := defaultApprovalTTL
+	if config.ApprovalTTLMinutes > 0 {
+		ttl = time.Duration(config.ApprovalTTLMinutes) * time.Minute
+	}
+	approval := &model.TradeApproval{
+		UserID:       req.UserID,
+		TokenAddress: req.TokenAddress,
+		TokenSymbol:  req.TokenSymbol,
+		Type:         tradeType,
+		AmountIn:     req.AmountIn,
+		AmountOut:    req.AmountOut,
+		GoldenScore:  req.GoldenScore,
+		Reason:       req.Reason,
+		StrategyUsed: req.StrategyUsed,
+		OrderID:      req.orderID,
+		Status:       approvalStatusPending,
+		ExpiresAt:    time.Now().UTC().Add(ttl),
+	}
+	if len(req.DecisionTrail) > 0 {
+		approval.DecisionTrail, _ = json.Marshal(req.DecisionTrail)
+	}
+	if err := h.repo.CreateTradeApproval(ctx, approval); err != nil {
+		log.Printf("create trade approval: %v", err)
+		return newTradeError(http.StatusInternalServerError, "failed to queue trade for approval")
+	}
+	h.recordApprovalEvent(ctx, approval, "created", "system", req.Reason)
+	log.Printf("trade queued for approval id=%s user=%s token=%s amount=%s", approval.ID, approval.UserID, approval.TokenAddress, approval.AmountIn)
+
+	payload := map[string]interface{}{
+		"type":           "trade_approval",
+		"userId":         approval.UserID,
+		"approvalId":     approval.ID,
+		"tokenAddress":   approval.TokenAddress,
+		"tokenSymbol":    approval.TokenSymbol,
+		"side":           approval.Type,
+		"amountIn":       approval.AmountIn,
+		"decisionReason": approval.Reason,
+		"strategyUsed":   approval.StrategyUsed,
+		"expiresAt":      approval.ExpiresAt,
+	}
+	// Approvals carry user IDs and amounts, so only the owner's
+	// authenticated connections receive them.
+	if h.hub != nil {
+		h.hub.BroadcastToUser(approval.UserID, payload)
+	}
+	if config.ApprovalWebhookURL != "" {
+		go func() {
+			if err := h.webhook.Post(context.Background(), config.ApprovalWebhookURL, config.WebhookSecret, payload); err != nil {
+				log.Printf("approval webhook id=%s: %v", approval.ID, err)
+			}
+		}()
+	}
+	return &service.ApprovalRequired{ApprovalID: approval.ID}
+}
+
+func (h *WalletHandler) recordApprovalEvent(ctx context.Context, approval *model.TradeApproval, action, actor, note string) {
+	event := &model.TradeApprovalEvent{
+		ApprovalID: approval.ID,
+		UserID:     approval.UserID,
+		Action:     action,
+		Actor:      actor,
+		Note:       note,
+	}
+	if err := h.repo.CreateTradeApprovalEvent(ctx, event); err != nil {
+		log.Printf("record approval event id=%s action=%s: %v", approval.ID, action, err)
+	}
+}
+
+// expireApprovals expires overdue approvals of a user ahead of the
+// background expirer so reads never show them pending.
+func (h *WalletHandler) expireApprovals(ctx context.Context, userID string) {
+	h.approvals.Expire(ctx, userID)
+}
+
+// approvalActor records who decided an approval by the credential that was
+// verified rather than by a name the caller supplies.
+func approvalActor(userID string, totpVerified bool) string {
+	if totpVerified {
+		return "totp:" + userID
+	}
+	return "user:" + userID
+}
+
+// settleApprovalOrders moves the resting orders waiting on an approval to
+// the status its decision implies.
+func (h *WalletHandler) settleApprovalOrders(ctx context.Context, approvalID, status string, updates map[string]interface{}) {
+	if n, err := h.repo.SettleApprovalOrders(ctx, approvalID, status, updates); err != nil {
+		log.Printf("settle orders of approval id=%s: %v", approvalID, err)
+	} else if n > 0 {
+		log.Printf("settled %d orders of approval id=%s as %s", n, approvalID, status)
+	}
+}
+
+// ListApprovals godoc
+// @Summary List trade approvals
+// @Description List trades held back by ConfirmThreshold; overdue approvals are expired first
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Param status query string false "Status filter (pending, approved, rejected, expired, executed, failed)"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string][]model.TradeApproval
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/approvals [get]
+func (h *WalletHandler) ListApprovals(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	ctx := c.Request.Context()
+
+	h.expireApprovals(ctx, userID)
+	approvals, err := h.repo.ListTradeApprovals(ctx, userID, strings.TrimSpace(c.Query("status")), limit)
+	if err != nil {
+		log.Printf("list trade approvals: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": approvals})
+}
+
+type ApprovalDecisionRequest struct {
+	UserID     string `json:"userId"`
+	ApprovalID string `json:"approvalId"`
+	Note       string `json:"note"`
+	TOTPCode   string `json:"totpCode"`
+}
+
+// ApproveTrade godoc
+// @Summary Approve trade
+// @Description Approve a pending trade and execute it; always requires a TOTP code
+// @Tags wallet
+// @Param payload body ApprovalDecisionRequest true "Approval payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Failure 503 {object} map[string]string
+// @Router /api/wallet/approvals/approve [post]
+func (h *WalletHandler) ApproveTrade(c *gin.Context) {
+	var req ApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" || strings.TrimSpace(req.ApprovalID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	if _, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode); err != nil {
+		respondTOTPError(c, err)
+		return
+	}
+	approver := approvalActor(req.UserID, true)
+
+	if err := h.breaker.Check(req.UserID); err != nil {
+		respondTradeError(c, err)
+		return
+	}
+
+	approval, ok := h.loadPendingApproval(c, req)
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	won, err := h.repo.TransitionTradeApproval(ctx, approval.ID, approvalStatusPending, approvalStatusApproved, map[string]interface{}{
+		"decided_by":    approver,
+		"decided_at":    now,
+		"decision_note": req.Note,
+	})
+	if err != nil {
+		log.Printf("approve trade: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to approve trade"})
+		return
+	}
+	if !won {
+		c.JSON(http.StatusConflict, gin.H{"error": "approval is no longer pending"})
+		return
+	}
+	h.recordApprovalEvent(ctx, approval, approvalStatusApproved, approver, req.Note)
+
+	trail := map[string]interface{}{}
+	if len(approval.DecisionTrail) > 0 {
+		_ = json.Unmarshal(approval.DecisionTrail, &trail)
+	}
+	trail["approvalId"] = approval.ID
+	trail["approvedBy"] = approver
+	trail["approvedAt"] = now
+
+	trade, tradeErr := h.executeTrade(ctx, ExecuteTradeRequest{
+		UserID:        approval.UserID,
+		TokenAddress:  approval.TokenAddress,
+		TokenSymbol:   approval.TokenSymbol,
+		Type:          approval.Type,
+		AmountIn:      approval.AmountIn,
+		AmountOut:     approval.AmountOut,
+		Reason:        approval.Reason,
+		StrategyUsed:  approval.StrategyUsed,
+		GoldenScore:   approval.GoldenScore,
+		DecisionTrail: trail,
+		approvalID:    approval.ID,
+		orderID:       approval.OrderID,
+	})
+
+	status := approvalStatusExecuted
+	updates := map[string]interface{}{}
+	switch {
+	case tradeErr != nil:
+		status = approvalStatusFailed
+		updates["error_message"] = tradeErr.Error()
+	case trade.Status != "success":
+		status = approvalStatusFailed
+		updates["trade_id"] = trade.ID
+		updates["tx_hash"] = trade.TxHash
+		updates["error_message"] = "trade status " + trade.Status
+	default:
+		updates["trade_id"] = trade.ID
+		updates["tx_hash"] = trade.TxHash
+	}
+	if _, err := h.repo.TransitionTradeApproval(ctx, approval.ID, approvalStatusApproved, status, updates); err != nil {
+		log.Printf("update approval id=%s: %v", approval.ID, err)
+	}
+	note, _ := updates["error_message"].(string)
+	h.recordApprovalEvent(ctx, approval, status, "system", note)
+
+	orderStatus := service.OrderStatusFilled
+	orderUpdates := map[string]interface{}{}
+	for k, v := range updates {
+		orderUpdates[k] = v
+	}
+	if status == approvalStatusFailed {
+		orderStatus = service.OrderStatusFailed
+	} else {
+		orderUpdates["amount_out"] = trade.AmountOut.String()
+		orderUpdates["filled_at"] = time.Now().UTC()
+	}
+	h.settleApprovalOrders(ctx, approval.ID, orderStatus, orderUpdates)
+
+	if tradeErr != nil {
+		respondTradeError(c, tradeErr)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": gin.H{"tx_hash": trade.TxHash, "status": status}})
+}
+
+// RejectTrade godoc
+// @Summary Reject trade
+// @Description Reject a pending trade so it never executes; requires a TOTP code once TOTP is enabled
+// @Tags wallet
+// @Param payload body ApprovalDecisionRequest true "Rejection payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/approvals/reject [post]
+func (h *WalletHandler) RejectTrade(c *gin.Context) {
+	var req ApprovalDecisionRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" || strings.TrimSpace(req.ApprovalID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	// Rejecting never spends, so approvals queued before TOTP became
+	// mandatory can still be turned down without it.
+	verified := false
+	if sec, err := h.repo.GetWalletSecurity(ctx, req.UserID); err == nil && sec.TOTPEnabled {
+		if _, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode); err != nil {
+			respondTOTPError(c, err)
+			return
+		}
+		verified = true
+	}
+	rejecter := approvalActor(req.UserID, verified)
+
+	approval, ok := h.loadPendingApproval(c, req)
+	if !ok {
+		return
+	}
+	won, err := h.repo.TransitionTradeApproval(ctx, approval.ID, approvalStatusPending, approvalStatusRejected, map[string]interface{}{
+		"decided_by":    rejecter,
+		"decided_at":    time.Now().UTC(),
+		"decision_note": req.Note,
+	})
+	if err != nil {
+		log.Printf("reject trade: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to reject trade"})
+		return
+	}
+	if !won {
+		c.JSON(http.StatusConflict, gin.H{"error": "approval is no longer pending"})
+		return
+	}
+	h.recordApprovalEvent(ctx, approval, approvalStatusRejected, rejecter, req.Note)
+	h.settleApprovalOrders(ctx, approval.ID, service.OrderStatusCancelled, map[string]interface{}{"error_message": "approval rejected"})
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// loadPendingApproval loads the approval named in req and writes the error
+// response when it is missing, owned by another user or no longer pending.
+func (h *WalletHandler) loadPendingApproval(c *gin.Context, req ApprovalDecisionRequest) (*model.TradeApproval, bool) {
+	ctx := c.Request.Context()
+	approval, err := h.repo.GetTradeApproval(ctx, req.ApprovalID)
+	if err != nil || approval.UserID != req.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "approval not found"})
+		return nil, false
+	}
+	if approval.Status == approvalStatusPending && !time.Now().Before(approval.ExpiresAt) {
+		h.expireApprovals(ctx, req.UserID)
+		c.JSON(http.StatusConflict, gin.H{"error": "approval expired"})
+		return nil, false
+	}
+	if approval.Status != approvalStatusPending {
+		c.JSON(http.StatusConflict, gin.H{"error": "approval is " + approval.Status})
+		return nil, false
+	}
+	return approval, true
+}
+
+// GetApprovalEvents godoc
+// @Summary Trade approval audit trail
+// @Description List who created, approved, rejected or executed an approval and when
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Param approvalId query string true "Approval ID"
+// @Success 200 {object} map[string][]model.TradeApprovalEvent
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/approvals/events [get]
+func (h *WalletHandler) GetApprovalEvents(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	approvalID := strings.TrimSpace(c.Query("approvalId"))
+	if userID == "" || approvalID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId and approvalId are required"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	approval, err := h.repo.GetTradeApproval(ctx, approvalID)
+	if err != nil || approval.UserID != userID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "approval not found"})
+		return
+	}
+	events, err := h.repo.ListTradeApprovalEvents(ctx, approval.ID)
+	if err != nil {
+		log.Printf("list approval events: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": events})
+}
@@ -0,0 +1,194 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type CreateLadderRequest struct {
+	UserID       string `json:"userId"`
+	TokenAddress string `json:"tokenAddress"`
+	TokenSymbol  string `json:"tokenSymbol"`
+	TotalAmount  string `json:"totalAmount"` // BNB across all tranches
+	Tranches     int    `json:"tranches"`
+	Mode         string `json:"mode"` // time | block | drawdown
+	// IntervalSeconds spaces time tranches; IntervalBlocks spaces block tranches.
+	IntervalSeconds int `json:"intervalSeconds"`
+	IntervalBlocks  int `json:"intervalBlocks"`
+	// DrawdownStep is the extra drop below the spot price at the first fill for each later tranche (0.1 = 10%).
+	DrawdownStep     float64 `json:"drawdownStep"`
+	ExpiresInMinutes int     `json:"expiresInMinutes"`
+}
+
+type LadderResponse struct {
+	Ladder   model.OrderLadder  `json:"ladder"`
+	Tranches []model.TradeOrder `json:"tranches"`
+}
+
+// CreateLadder godoc
+// @Summary Create laddered entry
+// @Description Split a BUY into tranches spaced by time, blocks or price drawdown from the spot price at the first fill
+// @Tags wallet
+// @Param payload body CreateLadderRequest true "Ladder payload"
+// @Success 200 {object} map[string]LadderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/ladders [post]
+func (h *WalletHandler) CreateLadder(c *gin.Context) {
+	var req CreateLadderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if !common.IsHexAddress(req.TokenAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tokenAddress"})
+		return
+	}
+	total, err := decimal.NewFromString(strings.TrimSpace(req.TotalAmount))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid totalAmount"})
+		return
+	}
+	if req.ExpiresInMinutes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expiresInMinutes"})
+		return
+	}
+	ladder := &model.OrderLadder{
+		UserID:          req.UserID,
+		TokenAddress:    common.HexToAddress(req.TokenAddress).Hex(),
+		TokenSymbol:     req.TokenSymbol,
+		Mode:            strings.ToLower(strings.TrimSpace(req.Mode)),
+		TotalAmountBNB:  total,
+		Tranches:        req.Tranches,
+		IntervalSeconds: req.IntervalSeconds,
+		IntervalBlocks:  req.IntervalBlocks,
+		DrawdownStep:    req.DrawdownStep,
+		Status:          service.LadderStatusOpen,
+	}
+	if err := service.ValidateLadder(ladder); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+
+	if _, err := h.repo.GetManagedWalletByUser(ctx, req.UserID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+
+	now := time.Now().UTC()
+	if req.ExpiresInMinutes > 0 {
+		expiresAt := now.Add(time.Duration(req.ExpiresInMinutes) * time.Minute)
+		ladder.ExpiresAt = &expiresAt
+	}
+	var block uint64
+	if ladder.Mode == service.LadderModeBlock {
+		block, err = h.eth.LatestBlockNumber(ctx)
+		if err != nil {
+			log.Printf("latest block: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to read block number"})
+			return
+		}
+	}
+
+	tranches := service.BuildLadderTranches(ladder, now, block)
+	if err := h.repo.CreateOrderLadder(ctx, ladder, tranches); err != nil {
+		log.Printf("create ladder: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create ladder"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": LadderResponse{Ladder: *ladder, Tranches: tranches}})
+}
+
+// ListLadders godoc
+// @Summary List laddered entries
+// @Description List laddered entries with their tranches
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Param limit query int false "Limit" default(20)
+// @Success 200 {object} map[string][]LadderResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/ladders [get]
+func (h *WalletHandler) ListLadders(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	limit := 20
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	ctx := c.Request.Context()
+
+	ladders, err := h.repo.ListOrderLadders(ctx, userID, limit)
+	if err != nil {
+		log.Printf("list ladders: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	resp := make([]LadderResponse, 0, len(ladders))
+	for _, ladder := range ladders {
+		tranches, err := h.repo.ListLadderTranches(ctx, ladder.ID)
+		if err != nil {
+			log.Printf("list ladder tranches: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+			return
+		}
+		resp = append(resp, LadderResponse{Ladder: ladder, Tranches: tranches})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+type CancelLadderRequest struct {
+	UserID   string `json:"userId"`
+	LadderID string `json:"ladderId"`
+}
+
+// CancelLadder godoc
+// @Summary Cancel laddered entry
+// @Description Cancel every tranche that has not executed yet; filled tranches stay in the position
+// @Tags wallet
+// @Param payload body CancelLadderRequest true "Cancel payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/ladders/cancel [post]
+func (h *WalletHandler) CancelLadder(c *gin.Context) {
+	var req CancelLadderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" || strings.TrimSpace(req.LadderID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	ladder, err := h.repo.GetOrderLadder(ctx, req.LadderID)
+	if err != nil || ladder.UserID != req.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "ladder not found"})
+		return
+	}
+	// The order engine settles the ladder status once no tranche is pending.
+	if _, err := h.repo.CancelLadderTranches(ctx, ladder.ID); err != nil {
+		log.Printf("cancel ladder: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel ladder"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
@@ -0,0 +1,251 @@
+package handler
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	StrategyEmergencyExit    = "emergency_exit"
+	defaultLiquidateSlippage = 0.2
+)
+
+type LiquidateRequest struct {
+	UserID string `json:"userId"`
+	// Slippage applies to every token without an override (0.2 = 20%).
+	Slippage float64 `json:"slippage"`
+	// SlippageOverrides maps token address to its slippage.
+	SlippageOverrides map[string]float64 `json:"slippageOverrides"`
+	Reason            string             `json:"reason"`
+}
+
+type LiquidationResult struct {
+	TokenAddress string          `json:"tokenAddress"`
+	TokenSymbol  string          `json:"tokenSymbol"`
+	Status       string          `json:"status"` // sold | skipped | failed
+	AmountIn     string          `json:"amountIn"`
+	MinAmountOut string          `json:"minAmountOut"`
+	AmountOut    string          `json:"amountOut"`
+	Slippage     float64         `json:"slippage"`
+	ProfitLoss   decimal.Decimal `json:"profitLoss" swaggertype:"string"`
+	TradeID      string          `json:"tradeId"`
+	TxHash       string          `json:"txHash"`
+	Error        string          `json:"error,omitempty"`
+}
+
+// LiquidateAll godoc
+// @Summary Liquidate all positions
+// @Description Sell every open AI position in parallel; tokens whose sell simulation reverts are skipped and any allowance raised for them is reset. Runs even while trading is halted, and bypasses the policy's slippage and price impact caps, recording each bypass as a violation.
+// @Tags wallet
+// @Param payload body LiquidateRequest true "Liquidation payload"
+// @Success 200 {object} map[string][]LiquidationResult
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/liquidate [post]
+func (h *WalletHandler) LiquidateAll(c *gin.Context) {
+	var req LiquidateRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if req.Slippage == 0 {
+		req.Slippage = defaultLiquidateSlippage
+	}
+	if req.Slippage < 0 || req.Slippage >= 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "slippage must be between 0 and 1"})
+		return
+	}
+	overrides := make(map[string]float64, len(req.SlippageOverrides))
+	for addr, slippage := range req.SlippageOverrides {
+		if !common.IsHexAddress(addr) || slippage < 0 || slippage >= 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid slippage override for " + addr})
+			return
+		}
+		overrides[strings.ToLower(addr)] = slippage
+	}
+	// Finish the sells even if the caller disconnects.
+	ctx := context.WithoutCancel(c.Request.Context())
+
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, req.UserID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+	positions, err := h.repo.ListAIPositionsByUser(ctx, req.UserID)
+	if err != nil {
+		log.Printf("list positions: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+
+	open := make([]model.AIPosition, 0, len(positions))
+	for _, pos := range positions {
+		if pos.Quantity.GreaterThan(decimal.Zero) {
+			open = append(open, pos)
+		}
+	}
+	log.Printf("liquidating %d positions user=%s", len(open), req.UserID)
+
+	reason := "emergency liquidation"
+	if strings.TrimSpace(req.Reason) != "" {
+		reason += ": " + strings.TrimSpace(req.Reason)
+	}
+	results := make([]LiquidationResult, len(open))
+	var wg sync.WaitGroup
+	for i := range open {
+		slippage, ok := overrides[strings.ToLower(open[i].TokenAddress)]
+		if !ok {
+			slippage = req.Slippage
+		}
+		wg.Add(1)
+		go func(i int, slippage float64) {
+			defer wg.Done()
+			results[i] = h.liquidatePosition(ctx, wallet, &open[i], slippage, reason)
+		}(i, slippage)
+	}
+	wg.Wait()
+
+	c.JSON(http.StatusOK, gin.H{"data": results})
+}
+
+// liquidatePosition sells the whole wallet balance of one position after a
+// dry run with the slippage-adjusted minimum output. The router is approved
+// for the balance before the dry run, since sells approve exact amounts and
+// the simulation would otherwise revert on the missing allowance; when the
+// token is then not sold the allowance is reset to what it was. Paper
+// positions are sold directly since their fills are already simulated.
+func (h *WalletHandler) liquidatePosition(ctx context.Context, wallet *model.ManagedWallet, pos *model.AIPosition, slippage float64, reason string) (result LiquidationResult) {
+	result = LiquidationResult{
+		TokenAddress: pos.TokenAddress,
+		TokenSymbol:  pos.TokenSymbol,
+		Status:       "skipped",
+		Slippage:     slippage,
+	}
+	if wallet.IsPaper() {
+		return h.sellPosition(ctx, pos, reason, result)
+	}
+	walletAddr := common.HexToAddress(wallet.Address)
+	tokenAddr := common.HexToAddress(pos.TokenAddress)
+
+	balance, err := h.eth.TokenBalance(ctx, tokenAddr, walletAddr)
+	if err != nil {
+		result.Status = "failed"
+		result.Error = "failed to read token balance"
+		return result
+	}
+	if balance.Sign() <= 0 {
+		result.Error = "no token balance"
+		return result
+	}
+	tokenReserve, bnbReserve, err := h.eth.TokenReserves(ctx, tokenAddr)
+	if err != nil {
+		result.Error = "no liquidity pair"
+		return result
+	}
+	expectedOut := decimal.NewFromBigInt(ethereum.GetAmountOut(balance, tokenReserve, bnbReserve), 0)
+	if token, err := h.repo.GetTokenByAddress(ctx, pos.TokenAddress); err == nil && token.EnrichedAt != nil {
+		expectedOut = expectedOut.Mul(decimal.NewFromFloat(1 - token.SellTax))
+	}
+	minOutWei := expectedOut.Mul(decimal.NewFromFloat(1 - slippage)).BigInt()
+	result.MinAmountOut = formatAmount(minOutWei, 18)
+
+	policy, err := h.loadSpendingPolicy(ctx, wallet.ID)
+	if err != nil {
+		log.Printf("load spending policy: %v", err)
+		result.Status = "failed"
+		result.Error = "failed to load spending policy"
+		return result
+	}
+	privateKey, err := decryptPrivateKey(wallet.EncryptedKey)
+	if err != nil {
+		log.Printf("decrypt key: %v", err)
+		result.Status = "failed"
+		result.Error = "failed to decrypt key"
+		return result
+	}
+	router := common.HexToAddress(ethereum.PancakeRouterV2)
+	// An unreadable allowance is treated as none, so a skip revokes fully.
+	prior, err := h.eth.Allowance(ctx, tokenAddr, walletAddr, router)
+	if err != nil {
+		prior = big.NewInt(0)
+	}
+	approveCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+	if err := h.ensureAllowance(approveCtx, wallet, policy, privateKey, tokenAddr, router, balance); err != nil {
+		result.Status = "failed"
+		result.Error = "approval failed: " + err.Error()
+		return result
+	}
+	if prior.Cmp(balance) < 0 {
+		defer func() {
+			if result.Status != "sold" {
+				h.restoreAllowance(ctx, wallet, privateKey, tokenAddr, router, prior)
+			}
+		}()
+	}
+
+	if err := h.eth.SimulateSellFrom(ctx, walletAddr, tokenAddr, balance, minOutWei); err != nil {
+		result.Error = "sell simulation reverted: " + err.Error()
+		return result
+	}
+	return h.sellPosition(ctx, pos, reason, result)
+}
+
+// restoreAllowance resets an allowance raised for a sell that did not happen.
+// Like revocations it only shrinks exposure, so it bypasses the spending policy.
+func (h *WalletHandler) restoreAllowance(ctx context.Context, wallet *model.ManagedWallet, privateKey *ecdsa.PrivateKey, tokenAddr, spender common.Address, amount *big.Int) {
+	txCtx, cancel := context.WithTimeout(ctx, 45*time.Second)
+	defer cancel()
+	txHash, err := h.approveAndWait(txCtx, privateKey, tokenAddr, spender, amount)
+	if err != nil {
+		log.Printf("restore allowance token=%s: %v", tokenAddr.Hex(), err)
+	}
+	h.syncAllowance(ctx, wallet, tokenAddr, spender, txHash.Hex())
+}
+
+// sellPosition sells all of pos at no less than result.MinAmountOut.
+func (h *WalletHandler) sellPosition(ctx context.Context, pos *model.AIPosition, reason string, result LiquidationResult) LiquidationResult {
+	trade, err := h.executeTrade(ctx, ExecuteTradeRequest{
+		UserID:       pos.UserID,
+		TokenAddress: pos.TokenAddress,
+		TokenSymbol:  pos.TokenSymbol,
+		Type:         "SELL",
+		AmountIn:     "ALL",
+		AmountOut:    result.MinAmountOut,
+		Reason:       reason,
+		StrategyUsed: StrategyEmergencyExit,
+		Force:        true,
+		emergency:    true,
+	})
+	if err != nil {
+		log.Printf("liquidate token=%s: %v", pos.TokenAddress, err)
+		result.Status = "failed"
+		result.Error = err.Error()
+		return result
+	}
+	result.AmountIn = trade.AmountIn.String()
+	result.AmountOut = trade.AmountOut.String()
+	result.ProfitLoss = trade.ProfitLoss
+	result.TradeID = trade.ID
+	result.TxHash = trade.TxHash
+	result.Status = "sold"
+	if trade.Status != "success" {
+		result.Status = "failed"
+		result.Error = "trade status " + trade.Status
+	}
+	return result
+}
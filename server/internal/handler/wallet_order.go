@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+type CreateOrderRequest struct {
+	UserID       string `json:"userId"`
+	TokenAddress string `json:"tokenAddress"`
+	TokenSymbol  string `json:"tokenSymbol"`
+	Side         string `json:"side"`         // BUY | SELL
+	Trigger      string `json:"trigger"`      // price_below | price_above | liquidity_above | golden_score_above
+	TriggerValue string `json:"triggerValue"` // BNB per token, USD liquidity or score
+	AmountIn     string `json:"amountIn"`     // BNB for BUY; token amount, ratio or ALL for SELL
+	AmountOutMin string `json:"amountOutMin"`
+	// ExpiresInMinutes is optional; zero keeps the order open until filled or cancelled.
+	ExpiresInMinutes int `json:"expiresInMinutes"`
+}
+
+// CreateOrder godoc
+// @Summary Create resting order
+// @Description Create a limit or conditional order executed by the managed wallet when its trigger holds
+// @Tags wallet
+// @Param payload body CreateOrderRequest true "Order payload"
+// @Success 200 {object} map[string]model.TradeOrder
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/orders [post]
+func (h *WalletHandler) CreateOrder(c *gin.Context) {
+	var req CreateOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if !common.IsHexAddress(req.TokenAddress) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid tokenAddress"})
+		return
+	}
+	side := strings.ToUpper(strings.TrimSpace(req.Side))
+	if side != "BUY" && side != "SELL" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "side must be BUY or SELL"})
+		return
+	}
+	trigger := strings.ToLower(strings.TrimSpace(req.Trigger))
+	if !service.IsValidOrderTrigger(trigger) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid trigger"})
+		return
+	}
+	triggerValue, err := decimal.NewFromString(strings.TrimSpace(req.TriggerValue))
+	if err != nil || triggerValue.LessThanOrEqual(decimal.Zero) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid triggerValue"})
+		return
+	}
+	if !validOrderAmount(side, req.AmountIn) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amountIn"})
+		return
+	}
+	if req.AmountOutMin != "" {
+		if v, err := decimal.NewFromString(req.AmountOutMin); err != nil || v.IsNegative() {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid amountOutMin"})
+			return
+		}
+	}
+	if req.ExpiresInMinutes < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid expiresInMinutes"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	if _, err := h.repo.GetManagedWalletByUser(ctx, req.UserID); err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+
+	order := &model.TradeOrder{
+		UserID:       req.UserID,
+		TokenAddress: common.HexToAddress(req.TokenAddress).Hex(),
+		TokenSymbol:  req.TokenSymbol,
+		Side:         side,
+		Trigger:      trigger,
+		TriggerValue: triggerValue,
+		AmountIn:     strings.TrimSpace(req.AmountIn),
+		AmountOutMin: strings.TrimSpace(req.AmountOutMin),
+		Status:       service.OrderStatusOpen,
+	}
+	if req.ExpiresInMinutes > 0 {
+		expiresAt := time.Now().UTC().Add(time.Duration(req.ExpiresInMinutes) * time.Minute)
+		order.ExpiresAt = &expiresAt
+	}
+	if err := h.repo.CreateTradeOrder(ctx, order); err != nil {
+		log.Printf("create order: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to create order"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": order})
+}
+
+// ListOrders godoc
+// @Summary List resting orders
+// @Description List limit and conditional orders for the managed wallet
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Param status query string false "Status filter"
+// @Param limit query int false "Limit" default(100)
+// @Success 200 {object} map[string][]model.TradeOrder
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/orders [get]
+func (h *WalletHandler) ListOrders(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	orders, err := h.repo.ListTradeOrders(c.Request.Context(), userID, strings.TrimSpace(c.Query("status")), limit)
+	if err != nil {
+		log.Printf("list orders: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": orders})
+}
+
+type CancelOrderRequest struct {
+	UserID  string `json:"userId"`
+	OrderID string `json:"orderId"`
+}
+
+// CancelOrder godoc
+// @Summary Cancel resting order
+// @Description Cancel an open order, or one awaiting approval; the approval is rejected once no other order waits on it
+// @Tags wallet
+// @Param payload body CancelOrderRequest true "Cancel payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/orders/cancel [post]
+func (h *WalletHandler) CancelOrder(c *gin.Context) {
+	var req CancelOrderRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" || strings.TrimSpace(req.OrderID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	order, err := h.repo.GetTradeOrder(ctx, req.OrderID)
+	if err != nil || order.UserID != req.UserID {
+		c.JSON(http.StatusNotFound, gin.H{"error": "order not found"})
+		return
+	}
+	from := service.OrderStatusOpen
+	if order.Status == service.OrderStatusAwaitingApproval {
+		from = service.OrderStatusAwaitingApproval
+	}
+	ok, err := h.repo.TransitionTradeOrder(ctx, order.ID, from, service.OrderStatusCancelled, nil)
+	if err != nil {
+		log.Printf("cancel order: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to cancel order"})
+		return
+	}
+	if !ok {
+		c.JSON(http.StatusConflict, gin.H{"error": "order is no longer open"})
+		return
+	}
+	if from == service.OrderStatusAwaitingApproval && order.ApprovalID != "" {
+		h.rejectOrderApproval(ctx, order)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+// rejectOrderApproval rejects the approval a cancelled order waited on, unless
+// another order still waits on the same approval.
+func (h *WalletHandler) rejectOrderApproval(ctx context.Context, order *model.TradeOrder) {
+	if n, err := h.repo.CountApprovalOrders(ctx, order.ApprovalID); err != nil || n > 0 {
+		if err != nil {
+			log.Printf("count orders of approval id=%s: %v", order.ApprovalID, err)
+		}
+		return
+	}
+	approval, err := h.repo.GetTradeApproval(ctx, order.ApprovalID)
+	if err != nil {
+		log.Printf("load approval id=%s: %v", order.ApprovalID, err)
+		return
+	}
+	actor := approvalActor(order.UserID, false)
+	won, err := h.repo.TransitionTradeApproval(ctx, approval.ID, approvalStatusPending, approvalStatusRejected, map[string]interface{}{
+		"decided_by":    actor,
+		"decided_at":    time.Now().UTC(),
+		"decision_note": "order cancelled",
+	})
+	if err != nil {
+		log.Printf("reject approval id=%s: %v", approval.ID, err)
+		return
+	}
+	if won {
+		h.recordApprovalEvent(ctx, approval, approvalStatusRejected, actor, "order "+order.ID+" cancelled")
+	}
+}
+
+func validOrderAmount(side, amount string) bool {
+	amount = strings.TrimSpace(amount)
+	if side == "SELL" {
+		if strings.EqualFold(amount, "ALL") {
+			return true
+		}
+		if _, ok := parseRatioAmount(amount); ok {
+			return true
+		}
+	}
+	value, err := decimal.NewFromString(amount)
+	return err == nil && value.GreaterThan(decimal.Zero)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"log"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// paperSwapGas approximates the gas of a PancakeSwap V2 swap.
+	paperSwapGas = 180000
+	// defaultPaperSlippage is applied when the config sets no PaperSlippage.
+	defaultPaperSlippage = 0.005
+	// defaultPaperBalance is the virtual BNB a new paper wallet starts with.
+	defaultPaperBalance = 10
+)
+
+// executePaperTrade fills a paper wallet trade at the current pair quote, less
+// the token's measured tax and simulated slippage, and books it against the
+// wallet's virtual balance and positions. Nothing is broadcast.
+func (h *WalletHandler) executePaperTrade(ctx context.Context, req ExecuteTradeRequest, wallet *model.ManagedWallet, config AutoTradeConfig) (*model.AITrade, error) {
+	chainCtx, cancel := context.WithTimeout(ctx, 15*time.Second)
+	defer cancel()
+
+	tokenAddr := common.HexToAddress(req.TokenAddress)
+	tokenReserve, bnbReserve, err := h.eth.TokenReserves(chainCtx, tokenAddr)
+	if err != nil {
+		return nil, newTradeError(http.StatusBadRequest, "no liquidity pair")
+	}
+	_, _, decimals, _ := h.eth.GetTokenInfo(chainCtx, tokenAddr)
+
+	buyTax, sellTax := 0.0, 0.0
+	if token, err := h.repo.GetTokenByAddress(ctx, req.TokenAddress); err == nil && token.EnrichedAt != nil {
+		buyTax, sellTax = token.BuyTax, token.SellTax
+	}
+	slippage := config.PaperSlippage
+	if slippage <= 0 || slippage >= 1 {
+		slippage = defaultPaperSlippage
+	}
+	gasFee := decimal.Zero
+	if gasPrice, err := h.eth.SuggestGasPrice(chainCtx); err == nil {
+		gasFee = decimal.NewFromBigInt(new(big.Int).Mul(big.NewInt(paperSwapGas), gasPrice), -18)
+	}
+
+	trade := &model.AITrade{
+		UserID:         req.UserID,
+		TokenAddress:   req.TokenAddress,
+		TokenSymbol:    req.TokenSymbol,
+		Type:           strings.ToUpper(req.Type),
+		TokenDecimals:  int(decimals),
+		TxHash:         paperTxHash(),
+		Status:         "success",
+		GasUsed:        paperSwapGas,
+		GasFeeBNB:      gasFee,
+		GoldenDogScore: req.GoldenScore,
+		DecisionReason: req.Reason,
+		StrategyUsed:   req.StrategyUsed,
+		OrderID:        req.orderID,
+		Simulated:      true,
+	}
+	if len(req.DecisionTrail) > 0 {
+		trade.DecisionTrail, _ = json.Marshal(req.DecisionTrail)
+	}
+
+	var minOutWei *big.Int
+	switch trade.Type {
+	case "BUY":
+		amountInWei, err := parseAmountToWei(req.AmountIn, 18)
+		if err != nil || amountInWei.Sign() <= 0 {
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountIn")
+		}
+		if err := h.checkBuyLimits(ctx, req, config, wallet, amountInWei); err != nil {
+			return nil, err
+		}
+		amountIn := decimal.NewFromBigInt(amountInWei, -18)
+		if wallet.Balance.LessThan(amountIn.Add(gasFee)) {
+			return nil, newTradeError(http.StatusBadRequest, "insufficient balance")
+		}
+		if minOutWei, err = parseAmountToWei(req.AmountOut, int32(decimals)); err != nil {
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountOut")
+		}
+		trade.AmountIn = amountIn
+		trade.AmountInWei = decimal.NewFromBigInt(amountInWei, 0)
+		trade.AmountOutWei = decimal.NewFromBigInt(paperFill(ethereum.GetAmountOut(amountInWei, bnbReserve, tokenReserve), buyTax, slippage), 0)
+		trade.AmountOut = trade.AmountOutWei.Shift(-int32(decimals))
+	case "SELL":
+		holdings := big.NewInt(0)
+		if pos, err := h.repo.GetAIPosition(ctx, req.UserID, req.TokenAddress); err == nil && pos != nil && pos.Quantity.IsPositive() {
+			holdings = pos.Quantity.Shift(int32(decimals)).BigInt()
+		}
+		amountInWei, err := resolveSellAmount(req, config, holdings, decimals)
+		if err != nil {
+			return nil, err
+		}
+		if amountInWei.Sign() <= 0 {
+			return nil, newTradeError(http.StatusBadRequest, "insufficient token balance")
+		}
+		if minOutWei, err = parseAmountToWei(req.AmountOut, 18); err != nil {
+			return nil, newTradeError(http.StatusBadRequest, "invalid amountOut")
+		}
+		trade.AmountIn = decimal.NewFromBigInt(amountInWei, -int32(decimals))
+		trade.AmountInWei = decimal.NewFromBigInt(amountInWei, 0)
+		trade.AmountOutWei = decimal.NewFromBigInt(paperFill(ethereum.GetAmountOut(amountInWei, tokenReserve, bnbReserve), sellTax, slippage), 0)
+		trade.AmountOut = trade.AmountOutWei.Shift(-18)
+	default:
+		return nil, newTradeError(http.StatusBadRequest, "invalid trade type")
+	}
+
+	if trade.AmountOutWei.BigInt().Cmp(minOutWei) < 0 || trade.AmountOutWei.IsZero() {
+		// A swap below its minimum output reverts on chain and still burns gas.
+		trade.Status = "failed"
+		trade.ErrorMessage = "PancakeRouter: INSUFFICIENT_OUTPUT_AMOUNT"
+		trade.AmountOut = decimal.Zero
+		trade.AmountOutWei = decimal.Zero
+		if _, err := h.repo.AdjustManagedWalletBalance(ctx, wallet.ID, gasFee.Neg()); err != nil {
+			log.Printf("paper gas debit: %v", err)
+		}
+		if err := h.ledger.RecordGasLoss(ctx, req.UserID, req.TokenAddress, req.TokenSymbol, trade.TxHash, gasFee, true); err != nil {
+			log.Printf("record gas loss: %v", err)
+		}
+	} else if trade.Type == "BUY" {
+		cost := trade.AmountIn.Add(gasFee)
+		ok, err := h.repo.AdjustManagedWalletBalance(ctx, wallet.ID, cost.Neg())
+		if err != nil {
+			log.Printf("paper buy debit: %v", err)
+			return nil, newTradeError(http.StatusInternalServerError, "trade failed")
+		}
+		if !ok {
+			return nil, newTradeError(http.StatusBadRequest, "insufficient balance")
+		}
+		h.bookBuy(ctx, req.UserID, req.TokenAddress, req.TokenSymbol, trade.TxHash, trade.AmountIn, trade.AmountOut, gasFee, true)
+		trade.CurrentValue = trade.AmountIn
+	} else {
+		ok, err := h.repo.AdjustManagedWalletBalance(ctx, wallet.ID, trade.AmountOut.Sub(gasFee))
+		if err != nil {
+			log.Printf("paper sell credit: %v", err)
+			return nil, newTradeError(http.StatusInternalServerError, "trade failed")
+		}
+		if !ok {
+			return nil, newTradeError(http.StatusBadRequest, "insufficient balance for gas")
+		}
+		realized := h.applyPositionAfterSell(ctx, req.UserID, req.TokenAddress, req.TokenSymbol, trade.TxHash, trade.AmountOut, trade.AmountIn, gasFee, config.CostBasis, true)
+		if realized != nil {
+			trade.ProfitLoss = service.ReturnRatio(realized)
+			trade.RealizedPLBNB = realized.RealizedBNB
+			trade.RealizedPLUSD = realized.RealizedUSD.Round(2)
+		}
+	}
+
+	if err := h.repo.CreateAITrade(ctx, trade); err != nil {
+		log.Printf("record paper trade: %v", err)
+	}
+	return trade, nil
+}
+
+// paperFill applies the token tax and the simulated slippage to a quote.
+func paperFill(quote *big.Int, tax, slippage float64) *big.Int {
+	if tax < 0 || tax >= 1 {
+		tax = 0
+	}
+	return decimal.NewFromBigInt(quote, 0).
+		Mul(decimal.NewFromFloat(1 - tax)).
+		Mul(decimal.NewFromFloat(1 - slippage)).
+		BigInt()
+}
+
+// paperTxHash returns a random hash-shaped ID for a paper trade; it never
+// matches a real transaction.
+func paperTxHash() string {
+	buf := make([]byte, 32)
+	_, _ = rand.Read(buf)
+	return "paper-0x" + hex.EncodeToString(buf)
+}
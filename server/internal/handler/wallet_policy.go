@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+type WalletPolicyRequest struct {
+	UserID   string                 `json:"userId"`
+	Policy   service.SpendingPolicy `json:"policy"`
+	TOTPCode string                 `json:"totpCode"`
+}
+
+// GetWalletPolicy godoc
+// @Summary Get wallet spending policy
+// @Description Get the spending policy evaluated before every managed wallet signature
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Success 200 {object} map[string]service.SpendingPolicy
+// @Failure 400 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Router /api/wallet/policy [get]
+func (h *WalletHandler) GetWalletPolicy(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	wallet, err := h.repo.GetManagedWalletByUser(c.Request.Context(), userID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+	policy, err := h.loadSpendingPolicy(c.Request.Context(), wallet.ID)
+	if err != nil {
+		log.Printf("load spending policy: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load spending policy"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": policy})
+}
+
+// UpsertWalletPolicy godoc
+// @Summary Upsert wallet spending policy
+// @Description Replace the wallet spending policy; requires a TOTP code once TOTP is enabled
+// @Tags wallet
+// @Param payload body WalletPolicyRequest true "Wallet policy payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/policy [post]
+func (h *WalletHandler) UpsertWalletPolicy(c *gin.Context) {
+	var req WalletPolicyRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if err := req.Policy.Validate(); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	ctx := c.Request.Context()
+
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, req.UserID)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "wallet not found"})
+		return
+	}
+	if sec, err := h.repo.GetWalletSecurity(ctx, req.UserID); err == nil && sec.TOTPEnabled {
+		if _, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode); err != nil {
+			respondTOTPError(c, err)
+			return
+		}
+	}
+
+	payload, _ := json.Marshal(req.Policy)
+	if err := h.repo.UpsertWalletPolicy(ctx, wallet.ID, wallet.UserID, payload); err != nil {
+		log.Printf("upsert wallet policy: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save policy"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+type PolicyViolationResponse struct {
+	ID           string                 `json:"id"`
+	Action       string                 `json:"action"`
+	Rule         string                 `json:"rule"`
+	Message      string                 `json:"message"`
+	TokenAddress string                 `json:"tokenAddress"`
+	Details      map[string]interface{} `json:"details,omitempty"`
+	Bypassed     bool                   `json:"bypassed"`
+	CreatedAt    time.Time              `json:"createdAt"`
+}
+
+// GetPolicyViolations godoc
+// @Summary Get policy violations
+// @Description List actions blocked by the wallet spending policy, and caps emergency exits bypassed (bypassed=true)
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Param limit query int false "Limit" default(50)
+// @Success 200 {object} map[string][]PolicyViolationResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/policy/violations [get]
+func (h *WalletHandler) GetPolicyViolations(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	limit := 50
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	rows, err := h.repo.ListPolicyViolations(c.Request.Context(), userID, limit)
+	if err != nil {
+		log.Printf("list policy violations: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	resp := make([]PolicyViolationResponse, 0, len(rows))
+	for _, row := range rows {
+		var details map[string]interface{}
+		if len(row.Details) > 0 {
+			_ = json.Unmarshal(row.Details, &details)
+		}
+		resp = append(resp, PolicyViolationResponse{
+			ID:           row.ID,
+			Action:       row.Action,
+			Rule:         row.Rule,
+			Message:      row.Message,
+			TokenAddress: row.TokenAddress,
+			Details:      details,
+			Bypassed:     row.Bypassed,
+			CreatedAt:    row.CreatedAt,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+// loadSpendingPolicy returns the wallet's policy, empty when none is set.
+// Any other failure is returned so callers refuse to sign rather than sign
+// without the policy.
+func (h *WalletHandler) loadSpendingPolicy(ctx context.Context, walletID string) (service.SpendingPolicy, error) {
+	var policy service.SpendingPolicy
+	record, err := h.repo.GetWalletPolicy(ctx, walletID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return policy, nil
+	}
+	if err != nil {
+		return policy, err
+	}
+	if len(record.Policy) == 0 {
+		return policy, nil
+	}
+	if err := json.Unmarshal(record.Policy, &policy); err != nil {
+		return service.SpendingPolicy{}, fmt.Errorf("decode spending policy: %w", err)
+	}
+	return policy, nil
+}
+
+// enforcePolicy evaluates action and records any violation before it is
+// returned. Caps an emergency exit bypasses are recorded too, but not returned.
+func (h *WalletHandler) enforcePolicy(ctx context.Context, wallet *model.ManagedWallet, policy service.SpendingPolicy, action service.PolicyAction) *service.PolicyViolation {
+	violation := policy.Evaluate(action)
+	if violation == nil {
+		if bypassed := policy.Bypassed(action); bypassed != nil {
+			log.Printf("[Policy] bypassed user=%s action=%s token=%s rule=%s: %s", wallet.UserID, action.Kind, action.TokenAddress, bypassed.Rule, bypassed.Message)
+			h.recordPolicyViolation(ctx, wallet, action, bypassed, true)
+		}
+		return nil
+	}
+	log.Printf("[Policy] blocked user=%s action=%s token=%s rule=%s: %s", wallet.UserID, action.Kind, action.TokenAddress, violation.Rule, violation.Message)
+	h.recordPolicyViolation(ctx, wallet, action, violation, false)
+	return violation
+}
+
+func (h *WalletHandler) recordPolicyViolation(ctx context.Context, wallet *model.ManagedWallet, action service.PolicyAction, violation *service.PolicyViolation, bypassed bool) {
+	details, _ := json.Marshal(map[string]interface{}{
+		"target":        action.Target,
+		"router":        action.Router,
+		"amountBnb":     action.AmountBNB.String(),
+		"slippage":      action.Slippage,
+		"priceImpact":   action.PriceImpact,
+		"sellTax":       action.SellTax,
+		"openPositions": action.OpenPositions,
+		"exposureBnb":   action.ExposureBNB.String(),
+	})
+	if err := h.repo.CreatePolicyViolation(ctx, &model.PolicyViolation{
+		UserID:       wallet.UserID,
+		WalletID:     wallet.ID,
+		Action:       action.Kind,
+		Rule:         violation.Rule,
+		Message:      violation.Message,
+		TokenAddress: action.TokenAddress,
+		Details:      details,
+		Bypassed:     bypassed,
+	}); err != nil {
+		log.Printf("record policy violation: %v", err)
+	}
+}
+
+func respondPolicyViolation(c *gin.Context, v *service.PolicyViolation) {
+	c.JSON(http.StatusForbidden, gin.H{"error": "policy violation", "rule": v.Rule, "message": v.Message})
+}
+
+// buildSwapAction quotes the swap against pair reserves and returns the policy
+// action along with the minimum output to use. When the caller gave no minimum
+// and the policy caps slippage, the minimum is derived from the quote.
+func (h *WalletHandler) buildSwapAction(
+	ctx context.Context,
+	userID string,
+	tokenAddr common.Address,
+	isBuy bool,
+	amountInWei *big.Int,
+	minOutWei *big.Int,
+	policy service.SpendingPolicy,
+) (service.PolicyAction, *big.Int) {
+	router := common.HexToAddress(ethereum.PancakeRouterV2).Hex()
+	action := service.PolicyAction{
+		Kind:         service.PolicyActionSell,
+		Target:       router,
+		Router:       router,
+		TokenAddress: tokenAddr.Hex(),
+		Now:          time.Now(),
+	}
+	if isBuy {
+		action.Kind = service.PolicyActionBuy
+		action.AmountBNB = decimal.NewFromBigInt(amountInWei, -18)
+	}
+
+	tax := 0.0
+	if token, err := h.repo.GetTokenByAddress(ctx, tokenAddr.Hex()); err == nil && token.EnrichedAt != nil {
+		action.SellTax = token.SellTax
+		action.SellTaxKnown = true
+		if isBuy {
+			tax = token.BuyTax
+		} else {
+			tax = token.SellTax
+		}
+	}
+
+	if positions, err := h.repo.ListAIPositionsByUser(ctx, userID); err == nil {
+		for _, pos := range positions {
+			if pos.Quantity.LessThanOrEqual(decimal.Zero) {
+				continue
+			}
+			if strings.EqualFold(pos.TokenAddress, tokenAddr.Hex()) {
+				action.HasPosition = true
+				action.ExposureBNB = pos.CostBNB
+				continue
+			}
+			action.OpenPositions++
+		}
+	}
+
+	tokenReserve, bnbReserve, err := h.eth.TokenReserves(ctx, tokenAddr)
+	if err != nil || amountInWei == nil || amountInWei.Sign() <= 0 {
+		return action, minOutWei
+	}
+	reserveIn, reserveOut := tokenReserve, bnbReserve
+	if isBuy {
+		reserveIn, reserveOut = bnbReserve, tokenReserve
+	}
+	expectedOut := ethereum.GetAmountOut(amountInWei, reserveIn, reserveOut)
+	if expectedOut.Sign() <= 0 {
+		return action, minOutWei
+	}
+	action.Quoted = true
+
+	in := decimal.NewFromBigInt(amountInWei, 0)
+	out := decimal.NewFromBigInt(expectedOut, 0)
+	spotOut := in.Mul(decimal.NewFromBigInt(reserveOut, 0)).Div(decimal.NewFromBigInt(reserveIn, 0))
+	if spotOut.GreaterThan(decimal.Zero) {
+		action.PriceImpact = decimal.NewFromInt(1).Sub(out.Div(spotOut)).InexactFloat64()
+	}
+
+	taxedOut := out.Mul(decimal.NewFromFloat(1 - tax))
+	if !isBuy {
+		action.AmountBNB = taxedOut.Shift(-18)
+	}
+	if minOutWei == nil || minOutWei.Sign() <= 0 {
+		if policy.MaxSlippage > 0 {
+			action.Slippage = policy.MaxSlippage
+			minOutWei = taxedOut.Mul(decimal.NewFromFloat(1 - policy.MaxSlippage)).BigInt()
+		} else {
+			action.Slippage = 1
+		}
+		return action, minOutWei
+	}
+	if taxedOut.GreaterThan(decimal.Zero) {
+		slippage := decimal.NewFromInt(1).Sub(decimal.NewFromBigInt(minOutWei, 0).Div(taxedOut)).InexactFloat64()
+		if slippage < 0 {
+			slippage = 0
+		}
+		action.Slippage = slippage
+	}
+	return action, minOutWei
+}
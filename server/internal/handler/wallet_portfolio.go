@@ -0,0 +1,138 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+// GetPortfolioSummary godoc
+// @Summary Portfolio summary
+// @Description Wallet BNB plus open positions marked to market in BNB and USD, with unrealized P&L
+// @Tags ai-trades
+// @Param userId query string true "User ID"
+// @Success 200 {object} map[string]service.PortfolioValuation
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/portfolio/summary [get]
+func (h *WalletHandler) GetPortfolioSummary(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	portfolio, err := h.valuator.ValuePortfolio(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("value portfolio: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to value portfolio"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": portfolio})
+}
+
+// GetPortfolioSnapshots godoc
+// @Summary Portfolio history
+// @Description Periodic portfolio valuations, newest first
+// @Tags ai-trades
+// @Param userId query string true "User ID"
+// @Param since query string false "RFC3339 start time"
+// @Param limit query int false "Limit" default(288)
+// @Success 200 {object} map[string][]model.PortfolioSnapshot
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/portfolio/snapshots [get]
+func (h *WalletHandler) GetPortfolioSnapshots(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	var since time.Time
+	if v := c.Query("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since"})
+			return
+		}
+		since = parsed
+	}
+	limit := 288
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	snapshots, err := h.repo.ListPortfolioSnapshots(c.Request.Context(), userID, since, limit)
+	if err != nil {
+		log.Printf("list portfolio snapshots: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"data": snapshots})
+}
+
+type RealizedPnLResponse struct {
+	TotalBNB string              `json:"total_bnb"`
+	TotalUSD string              `json:"total_usd"`
+	GasBNB   string              `json:"gas_bnb"`
+	Entries  []model.RealizedPnL `json:"entries"`
+}
+
+// GetRealizedPnL godoc
+// @Summary Realized P&L ledger
+// @Description Realized P&L per sell in BNB and USD with gas included, newest first; totals cover the returned entries
+// @Tags ai-trades
+// @Param userId query string true "User ID"
+// @Param since query string false "RFC3339 start time"
+// @Param limit query int false "Limit" default(100)
+// @Success 200 {object} map[string]RealizedPnLResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/portfolio/realized [get]
+func (h *WalletHandler) GetRealizedPnL(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	var since time.Time
+	if v := c.Query("since"); v != "" {
+		parsed, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "invalid since"})
+			return
+		}
+		since = parsed
+	}
+	limit := 100
+	if v := c.Query("limit"); v != "" {
+		if parsed, err := strconv.Atoi(v); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+	entries, err := h.repo.ListRealizedPnL(c.Request.Context(), userID, since, limit)
+	if err != nil {
+		log.Printf("list realized pnl: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "internal server error"})
+		return
+	}
+	totalBNB, totalUSD, gas := decimal.Zero, decimal.Zero, decimal.Zero
+	for _, entry := range entries {
+		totalBNB = totalBNB.Add(entry.RealizedBNB)
+		totalUSD = totalUSD.Add(entry.RealizedUSD)
+		gas = gas.Add(entry.GasBNB)
+	}
+	c.JSON(http.StatusOK, gin.H{"data": RealizedPnLResponse{
+		TotalBNB: totalBNB.String(),
+		TotalUSD: totalUSD.StringFixed(2),
+		GasBNB:   gas.String(),
+		Entries:  entries,
+	}})
+}
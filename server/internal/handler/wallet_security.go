@@ -0,0 +1,471 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/pkg/totp"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/gin-gonic/gin"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	totpIssuer                = "EasyMeme"
+	withdrawalAddressTimeLock = 24 * time.Hour
+	// A pending TOTP secret can only be replaced once its setup expires.
+	totpSetupTTL = 10 * time.Minute
+	// This many wrong TOTP codes in a row lock TOTP for totpLockout.
+	maxTOTPAttempts = 5
+	totpLockout     = 15 * time.Minute
+)
+
+var (
+	defaultDailyWithdrawLimit = decimal.NewFromInt(1)
+
+	errTOTPNotEnabled = errors.New("totp is not enabled")
+	errInvalidTOTP    = errors.New("invalid totp code")
+	errTOTPLocked     = errors.New("too many invalid totp codes, try again later")
+)
+
+type TOTPSetupRequest struct {
+	UserID string `json:"userId"`
+}
+
+type TOTPSetupResponse struct {
+	Secret     string `json:"secret"`
+	OtpauthURL string `json:"otpauthUrl"`
+	// SetupToken must accompany the code that enables the secret.
+	SetupToken string    `json:"setupToken"`
+	ExpiresAt  time.Time `json:"expiresAt"`
+}
+
+// SetupTOTP godoc
+// @Summary Set up TOTP
+// @Description Generate a TOTP secret for withdrawals and allowlist changes; it stays inactive until confirmed with the returned setupToken within 10 minutes, and cannot be replaced before then
+// @Tags wallet
+// @Param payload body TOTPSetupRequest true "TOTP setup payload"
+// @Success 200 {object} map[string]TOTPSetupResponse
+// @Failure 400 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/totp/setup [post]
+func (h *WalletHandler) SetupTOTP(c *gin.Context) {
+	var req TOTPSetupRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	now := time.Now().UTC()
+	existing, err := h.repo.GetWalletSecurity(ctx, req.UserID)
+	if err == nil && existing.TOTPEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "totp already enabled"})
+		return
+	}
+	if err == nil && existing.TOTPSetupExpiresAt != nil && now.Before(*existing.TOTPSetupExpiresAt) {
+		c.JSON(http.StatusConflict, gin.H{"error": "totp setup pending until " + existing.TOTPSetupExpiresAt.UTC().Format(time.RFC3339)})
+		return
+	}
+
+	secret, err := totp.GenerateSecret()
+	if err != nil {
+		log.Printf("generate totp secret: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate secret"})
+		return
+	}
+	encrypted, err := encryptSecret([]byte(secret))
+	if err != nil {
+		if err == ErrMissingMasterKey {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": "WALLET_MASTER_KEY is required"})
+			return
+		}
+		log.Printf("encrypt totp secret: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to encrypt secret"})
+		return
+	}
+	tokenBytes := make([]byte, 32)
+	if _, err := rand.Read(tokenBytes); err != nil {
+		log.Printf("generate totp setup token: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to generate secret"})
+		return
+	}
+	setupToken := hex.EncodeToString(tokenBytes)
+	tokenHash := sha256.Sum256([]byte(setupToken))
+	expiresAt := now.Add(totpSetupTTL)
+
+	if existing != nil {
+		err = h.repo.UpdateWalletSecurity(ctx, req.UserID, map[string]interface{}{
+			"totp_secret":           encrypted,
+			"totp_enabled":          false,
+			"last_totp_step":        0,
+			"totp_setup_token_hash": tokenHash[:],
+			"totp_setup_expires_at": expiresAt,
+		})
+	} else {
+		err = h.repo.CreateWalletSecurity(ctx, &model.WalletSecurity{
+			UserID:             req.UserID,
+			TOTPSecret:         encrypted,
+			TOTPSetupTokenHash: tokenHash[:],
+			TOTPSetupExpiresAt: &expiresAt,
+			DailyWithdrawLimit: defaultDailyWithdrawLimit,
+		})
+	}
+	if err != nil {
+		log.Printf("save wallet security: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save secret"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": TOTPSetupResponse{
+		Secret:     secret,
+		OtpauthURL: totp.URI(totpIssuer, req.UserID, secret),
+		SetupToken: setupToken,
+		ExpiresAt:  expiresAt,
+	}})
+}
+
+type TOTPEnableRequest struct {
+	UserID     string `json:"userId"`
+	Code       string `json:"code"`
+	SetupToken string `json:"setupToken"`
+}
+
+// EnableTOTP godoc
+// @Summary Enable TOTP
+// @Description Confirm the TOTP secret from setup with a current code and the setupToken setup returned
+// @Tags wallet
+// @Param payload body TOTPEnableRequest true "TOTP enable payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/totp/enable [post]
+func (h *WalletHandler) EnableTOTP(c *gin.Context) {
+	var req TOTPEnableRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	sec, err := h.repo.GetWalletSecurity(ctx, req.UserID)
+	if err != nil || len(sec.TOTPSecret) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "totp setup required"})
+		return
+	}
+	if sec.TOTPEnabled {
+		c.JSON(http.StatusConflict, gin.H{"error": "totp already enabled"})
+		return
+	}
+	tokenHash := sha256.Sum256([]byte(req.SetupToken))
+	if len(sec.TOTPSetupTokenHash) == 0 || subtle.ConstantTimeCompare(tokenHash[:], sec.TOTPSetupTokenHash) != 1 {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "invalid setup token"})
+		return
+	}
+	if sec.TOTPSetupExpiresAt == nil || !time.Now().Before(*sec.TOTPSetupExpiresAt) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "totp setup expired, run setup again"})
+		return
+	}
+	secret, err := decryptSecret(sec.TOTPSecret)
+	if err != nil {
+		log.Printf("decrypt totp secret: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to decrypt secret"})
+		return
+	}
+	step, ok := totp.Validate(string(secret), req.Code, time.Now())
+	if !ok {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": errInvalidTOTP.Error()})
+		return
+	}
+	if err := h.repo.UpdateWalletSecurity(ctx, req.UserID, map[string]interface{}{
+		"totp_enabled":          true,
+		"last_totp_step":        step,
+		"totp_setup_token_hash": nil,
+		"totp_setup_expires_at": nil,
+	}); err != nil {
+		log.Printf("enable totp: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to enable totp"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+type WithdrawalAddressResponse struct {
+	Address   string    `json:"address"`
+	Label     string    `json:"label"`
+	Active    bool      `json:"active"`
+	ActiveAt  time.Time `json:"activeAt"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// ListWithdrawalAddresses godoc
+// @Summary List withdrawal addresses
+// @Description List allowlisted withdrawal addresses and their time-lock state
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Success 200 {object} map[string][]WithdrawalAddressResponse
+// @Failure 400 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/withdrawal-addresses [get]
+func (h *WalletHandler) ListWithdrawalAddresses(c *gin.Context) {
+	userID := strings.TrimSpace(c.Query("userId"))
+	if userID == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+		return
+	}
+
+	entries, err := h.repo.ListWithdrawalAddresses(c.Request.Context(), userID)
+	if err != nil {
+		log.Printf("list withdrawal addresses: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to load addresses"})
+		return
+	}
+
+	now := time.Now()
+	resp := make([]WithdrawalAddressResponse, 0, len(entries))
+	for _, entry := range entries {
+		resp = append(resp, WithdrawalAddressResponse{
+			Address:   entry.Address,
+			Label:     entry.Label,
+			Active:    !now.Before(entry.ActiveAt),
+			ActiveAt:  entry.ActiveAt,
+			CreatedAt: entry.CreatedAt,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+type WithdrawalAddressRequest struct {
+	UserID   string `json:"userId"`
+	Address  string `json:"address"`
+	Label    string `json:"label"`
+	TOTPCode string `json:"totpCode"`
+}
+
+// AddWithdrawalAddress godoc
+// @Summary Add withdrawal address
+// @Description Allowlist a withdrawal address; it becomes usable after a 24h time-lock
+// @Tags wallet
+// @Param payload body WithdrawalAddressRequest true "Withdrawal address payload"
+// @Success 200 {object} map[string]WithdrawalAddressResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 409 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/withdrawal-addresses [post]
+func (h *WalletHandler) AddWithdrawalAddress(c *gin.Context) {
+	var req WithdrawalAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	if !common.IsHexAddress(req.Address) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid address"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	if _, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode); err != nil {
+		respondTOTPError(c, err)
+		return
+	}
+	if _, err := h.repo.GetWithdrawalAddress(ctx, req.UserID, req.Address); err == nil {
+		c.JSON(http.StatusConflict, gin.H{"error": "address already allowlisted"})
+		return
+	}
+
+	entry := &model.WithdrawalAddress{
+		UserID:   req.UserID,
+		Address:  common.HexToAddress(req.Address).Hex(),
+		Label:    strings.TrimSpace(req.Label),
+		ActiveAt: time.Now().UTC().Add(withdrawalAddressTimeLock),
+	}
+	if err := h.repo.CreateWithdrawalAddress(ctx, entry); err != nil {
+		log.Printf("create withdrawal address: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to save address"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": WithdrawalAddressResponse{
+		Address:   entry.Address,
+		Label:     entry.Label,
+		Active:    false,
+		ActiveAt:  entry.ActiveAt,
+		CreatedAt: entry.CreatedAt,
+	}})
+}
+
+// RemoveWithdrawalAddress godoc
+// @Summary Remove withdrawal address
+// @Description Remove an address from the withdrawal allowlist
+// @Tags wallet
+// @Param payload body WithdrawalAddressRequest true "Withdrawal address payload"
+// @Success 200 {object} map[string]string
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 404 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/withdrawal-addresses/remove [post]
+func (h *WalletHandler) RemoveWithdrawalAddress(c *gin.Context) {
+	var req WithdrawalAddressRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" || req.Address == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	if _, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode); err != nil {
+		respondTOTPError(c, err)
+		return
+	}
+	removed, err := h.repo.DeleteWithdrawalAddress(ctx, req.UserID, req.Address)
+	if err != nil {
+		log.Printf("delete withdrawal address: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to remove address"})
+		return
+	}
+	if !removed {
+		c.JSON(http.StatusNotFound, gin.H{"error": "address not found"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"status": "ok"})
+}
+
+type WithdrawalLimitRequest struct {
+	UserID     string          `json:"userId"`
+	DailyLimit decimal.Decimal `json:"dailyLimit" swaggertype:"string"`
+	TOTPCode   string          `json:"totpCode"`
+}
+
+type WithdrawalLimitResponse struct {
+	DailyLimit   string     `json:"dailyLimit"`
+	PendingLimit string     `json:"pendingLimit,omitempty"`
+	ActiveAt     *time.Time `json:"activeAt,omitempty"`
+}
+
+// UpdateWithdrawalLimit godoc
+// @Summary Update daily withdrawal limit
+// @Description Set the rolling 24h withdrawal limit in BNB; decreases apply at once, increases after 24 hours
+// @Tags wallet
+// @Param payload body WithdrawalLimitRequest true "Withdrawal limit payload"
+// @Success 200 {object} map[string]WithdrawalLimitResponse
+// @Failure 400 {object} map[string]string
+// @Failure 401 {object} map[string]string
+// @Failure 403 {object} map[string]string
+// @Failure 500 {object} map[string]string
+// @Router /api/wallet/withdrawal-limit [post]
+func (h *WalletHandler) UpdateWithdrawalLimit(c *gin.Context) {
+	var req WithdrawalLimitRequest
+	if err := c.ShouldBindJSON(&req); err != nil || strings.TrimSpace(req.UserID) == "" || req.DailyLimit.IsNegative() {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid payload"})
+		return
+	}
+	ctx := c.Request.Context()
+
+	sec, err := h.verifyTOTP(ctx, req.UserID, req.TOTPCode)
+	if err != nil {
+		respondTOTPError(c, err)
+		return
+	}
+
+	// Like a new allowlist address, a raised limit only activates after the
+	// time lock, so a stolen TOTP code cannot open the wallet up at once.
+	now := time.Now().UTC()
+	current := sec.WithdrawLimitAt(now)
+	resp := WithdrawalLimitResponse{DailyLimit: req.DailyLimit.String()}
+	updates := map[string]interface{}{
+		"daily_withdraw_limit":      req.DailyLimit,
+		"pending_withdraw_limit":    nil,
+		"pending_withdraw_limit_at": nil,
+	}
+	if req.DailyLimit.GreaterThan(current) {
+		activeAt := now.Add(withdrawalAddressTimeLock)
+		updates["daily_withdraw_limit"] = current
+		updates["pending_withdraw_limit"] = req.DailyLimit
+		updates["pending_withdraw_limit_at"] = activeAt
+		resp = WithdrawalLimitResponse{
+			DailyLimit:   current.String(),
+			PendingLimit: req.DailyLimit.String(),
+			ActiveAt:     &activeAt,
+		}
+	}
+	if err := h.repo.UpdateWalletSecurity(ctx, req.UserID, updates); err != nil {
+		log.Printf("update withdrawal limit: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to update limit"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"data": resp})
+}
+
+func (h *WalletHandler) verifyTOTP(ctx context.Context, userID, code string) (*model.WalletSecurity, error) {
+	sec, err := h.repo.GetWalletSecurity(ctx, userID)
+	if err != nil || !sec.TOTPEnabled {
+		return nil, errTOTPNotEnabled
+	}
+	now := time.Now()
+	if sec.TOTPLockedUntil != nil && now.Before(*sec.TOTPLockedUntil) {
+		return nil, errTOTPLocked
+	}
+	secret, err := decryptSecret(sec.TOTPSecret)
+	if err != nil {
+		return nil, err
+	}
+	step, ok := totp.Validate(string(secret), code, now)
+	if !ok {
+		h.recordTOTPFailure(ctx, userID, now)
+		return nil, errInvalidTOTP
+	}
+	fresh, err := h.repo.ConsumeTOTPStep(ctx, userID, step)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		h.recordTOTPFailure(ctx, userID, now)
+		return nil, errInvalidTOTP
+	}
+	if sec.FailedTOTPAttempts > 0 {
+		if err := h.repo.UpdateWalletSecurity(ctx, userID, map[string]interface{}{"failed_totp_attempts": 0}); err != nil {
+			log.Printf("reset totp failures: %v", err)
+		}
+	}
+	return sec, nil
+}
+
+func (h *WalletHandler) recordTOTPFailure(ctx context.Context, userID string, now time.Time) {
+	if err := h.repo.RecordTOTPFailure(ctx, userID, maxTOTPAttempts, now.Add(totpLockout)); err != nil {
+		log.Printf("record totp failure: %v", err)
+	}
+}
+
+func respondTOTPError(c *gin.Context, err error) {
+	switch err {
+	case errTOTPNotEnabled:
+		c.JSON(http.StatusForbidden, gin.H{"error": err.Error()})
+	case errInvalidTOTP:
+		c.JSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	case errTOTPLocked:
+		c.JSON(http.StatusTooManyRequests, gin.H{"error": err.Error()})
+	default:
+		log.Printf("verify totp: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "failed to verify totp"})
+	}
+}
@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"context"
+	"log"
+	"math/big"
+
+	"easymeme/internal/model"
+	"easymeme/internal/service"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/shopspring/decimal"
+)
+
+// SettleTrade books a trade executeTrade recorded as pending once its receipt
+// lands. A nil receipt means the transaction was dropped: the trade fails
+// without a gas charge.
+func (h *WalletHandler) SettleTrade(ctx context.Context, trade *model.AITrade, receipt *types.Receipt) error {
+	wallet, err := h.repo.GetManagedWalletByUser(ctx, trade.UserID)
+	if err != nil {
+		return err
+	}
+	tokenAddr := common.HexToAddress(trade.TokenAddress)
+	walletAddr := common.HexToAddress(wallet.Address)
+
+	status := "failed"
+	gasFee := decimal.Zero
+	amountOutWei := big.NewInt(0)
+	updates := map[string]interface{}{"error_message": "transaction dropped before it was mined"}
+	if receipt != nil {
+		if receipt.Status == 1 {
+			status = "success"
+		}
+		gasWei := big.NewInt(0)
+		if receipt.EffectiveGasPrice != nil {
+			gasWei = new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		}
+		gasFee = decimal.NewFromBigInt(gasWei, -18)
+		updates = map[string]interface{}{
+			"error_message": "",
+			"gas_used":      receipt.GasUsed,
+			"block_number":  receipt.BlockNumber.Uint64(),
+			"gas_fee_bnb":   gasFee,
+		}
+	}
+	outDecimals := int32(18)
+	if trade.Type == "BUY" {
+		outDecimals = int32(trade.TokenDecimals)
+	}
+	if status == "success" {
+		var out *big.Int
+		if trade.Type == "BUY" {
+			out = ethereum.TokensReceived(receipt, tokenAddr, walletAddr)
+		} else {
+			out = ethereum.SellProceeds(receipt)
+		}
+		if out != nil {
+			amountOutWei = out
+		}
+		updates["amount_out_wei"] = decimal.NewFromBigInt(amountOutWei, 0)
+		updates["amount_out"] = decimal.NewFromBigInt(amountOutWei, -outDecimals)
+		if trade.Type == "BUY" {
+			// BUYs start valued at their cost; the valuator re-marks them periodically.
+			updates["current_value"] = trade.AmountIn
+		}
+	}
+
+	won, err := h.repo.SettleAITrade(ctx, trade.ID, status, updates)
+	if err != nil || !won {
+		return err
+	}
+
+	amountOut := decimal.NewFromBigInt(amountOutWei, -outDecimals)
+	config, _ := h.loadWalletConfig(ctx, trade.UserID)
+	switch {
+	case status == "failed":
+		if receipt != nil {
+			if err := h.ledger.RecordGasLoss(ctx, trade.UserID, trade.TokenAddress, trade.TokenSymbol, trade.TxHash, gasFee, false); err != nil {
+				log.Printf("record gas loss: %v", err)
+			}
+		}
+	case trade.Type == "BUY":
+		h.bookBuy(ctx, trade.UserID, trade.TokenAddress, trade.TokenSymbol, trade.TxHash, trade.AmountIn, amountOut, gasFee, false)
+	default:
+		realized := h.applyPositionAfterSell(ctx, trade.UserID, trade.TokenAddress, trade.TokenSymbol, trade.TxHash, amountOut, trade.AmountIn, gasFee, config.CostBasis, false)
+		if realized != nil {
+			profitLoss := service.ReturnRatio(realized)
+			if err := h.repo.UpdateAITrade(ctx, trade.ID, map[string]interface{}{
+				"profit_loss":    profitLoss,
+				"realized_plbnb": realized.RealizedBNB,
+				"realized_plusd": realized.RealizedUSD.Round(2),
+			}); err != nil {
+				log.Printf("record trade pnl: %v", err)
+			}
+			h.breaker.RecordSell(ctx, trade.UserID, profitLoss.InexactFloat64(), config.MaxDailyLoss)
+		}
+		h.syncAllowance(ctx, wallet, tokenAddr, common.HexToAddress(ethereum.PancakeRouterV2), "")
+	}
+
+	if receipt != nil {
+		h.breaker.RecordTransaction(ctx, trade.UserID, status == "success")
+	}
+	if balanceWei, err := h.eth.GetBalance(ctx, walletAddr); err == nil {
+		if balance, err := weiToBNB(balanceWei); err == nil {
+			_ = h.repo.UpdateManagedWalletBalance(ctx, wallet.ID, balance)
+		}
+	}
+	return nil
+}
@@ -9,18 +9,33 @@ import (
     "github.com/gorilla/websocket"
 )
 
+// WebSocketHub fans messages out to connected clients. Connections opened
+// through the authenticated wallet endpoint carry their user ID and also
+// receive that user's private messages.
 type WebSocketHub struct {
-    clients    map[*websocket.Conn]bool
-    broadcast  chan []byte
-    register   chan *websocket.Conn
+    clients    map[*websocket.Conn]string
+    broadcast  chan wsMessage
+    register   chan wsClient
     unregister chan *websocket.Conn
 }
 
+type wsClient struct {
+    conn   *websocket.Conn
+    userID string
+}
+
+// wsMessage goes to every client when userID is empty, otherwise only to
+// the clients of userID.
+type wsMessage struct {
+    data   []byte
+    userID string
+}
+
 func NewWebSocketHub() *WebSocketHub {
     return &WebSocketHub{
-        clients:    make(map[*websocket.Conn]bool),
-        broadcast:  make(chan []byte, 256),
-        register:   make(chan *websocket.Conn, 32),
+        clients:    make(map[*websocket.Conn]string),
+        broadcast:  make(chan wsMessage, 256),
+        register:   make(chan wsClient, 32),
         unregister: make(chan *websocket.Conn, 32),
     }
 }
@@ -29,15 +44,18 @@ func (h *WebSocketHub) Run() {
     for {
         select {
         case client := <-h.register:
-            h.clients[client] = true
+            h.clients[client.conn] = client.userID
         case client := <-h.unregister:
             if _, ok := h.clients[client]; ok {
                 delete(h.clients, client)
                 client.Close()
             }
         case message := <-h.broadcast:
-            for client := range h.clients {
-                if err := client.WriteMessage(websocket.TextMessage, message); err != nil {
+            for client, userID := range h.clients {
+                if message.userID != "" && message.userID != userID {
+                    continue
+                }
+                if err := client.WriteMessage(websocket.TextMessage, message.data); err != nil {
                     delete(h.clients, client)
                     client.Close()
                 }
@@ -47,12 +65,24 @@ func (h *WebSocketHub) Run() {
 }
 
 func (h *WebSocketHub) Broadcast(payload interface{}) {
+    h.send("", payload)
+}
+
+// BroadcastToUser sends payload only to connections authenticated as userID.
+func (h *WebSocketHub) BroadcastToUser(userID string, payload interface{}) {
+    if userID == "" {
+        return
+    }
+    h.send(userID, payload)
+}
+
+func (h *WebSocketHub) send(userID string, payload interface{}) {
     data, err := json.Marshal(payload)
     if err != nil {
         log.Printf("[WebSocket] Marshal error: %v", err)
         return
     }
-    h.broadcast <- data
+    h.broadcast <- wsMessage{data: data, userID: userID}
 }
 
 var upgrader = websocket.Upgrader{
@@ -60,12 +90,36 @@ var upgrader = websocket.Upgrader{
 }
 
 func (h *WebSocketHub) HandleWebSocket(c *gin.Context) {
+    h.serve(c, "")
+}
+
+// HandleUserWebSocket godoc
+// @Summary Wallet event stream
+// @Description WebSocket carrying the public stream plus the user's private events such as trade approvals
+// @Tags wallet
+// @Param userId query string true "User ID"
+// @Success 101 {string} string "Switching Protocols"
+// @Failure 400 {object} map[string]string
+// @Router /api/wallet/ws [get]
+func (h *WebSocketHub) HandleUserWebSocket(c *gin.Context) {
+    userID := c.GetHeader("X-User-Id")
+    if userID == "" {
+        userID = c.Query("userId")
+    }
+    if userID == "" {
+        c.JSON(http.StatusBadRequest, gin.H{"error": "userId is required"})
+        return
+    }
+    h.serve(c, userID)
+}
+
+func (h *WebSocketHub) serve(c *gin.Context, userID string) {
     conn, err := upgrader.Upgrade(c.Writer, c.Request, nil)
     if err != nil {
         return
     }
 
-    h.register <- conn
+    h.register <- wsClient{conn: conn, userID: userID}
 
     go func() {
         defer func() { h.unregister <- conn }()
@@ -0,0 +1,23 @@
+package model
+
+import "time"
+
+// AgentSubscription is a webhook an analysis agent registers to receive
+// enriched tokens as soon as they enter the queue instead of polling it.
+type AgentSubscription struct {
+	ID              string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AgentID         string     `gorm:"index;not null" json:"agent_id"`
+	URL             string     `gorm:"not null" json:"url"`
+	Secret          string     `json:"-"`
+	MinPriority     float64    `gorm:"default:0" json:"min_priority"`
+	Active          bool       `gorm:"index;default:true" json:"active"`
+	Failures        int        `gorm:"default:0" json:"failures"` // consecutive failed deliveries
+	LastError       string     `json:"last_error"`
+	LastDeliveredAt *time.Time `json:"last_delivered_at"`
+	CreatedAt       time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt       time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AgentSubscription) TableName() string {
+	return "agent_subscriptions"
+}
@@ -13,7 +13,17 @@ type AIPosition struct {
 	TokenSymbol  string          `json:"token_symbol"`
 	Quantity     decimal.Decimal `gorm:"type:decimal(36,18)" json:"quantity"`
 	CostBNB      decimal.Decimal `gorm:"type:decimal(36,18)" json:"cost_bnb"`
-	UpdatedAt    time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+	LastPriceBNB decimal.Decimal `gorm:"type:decimal(36,18)" json:"last_price_bnb"`
+	ValueBNB     decimal.Decimal `gorm:"type:decimal(36,18)" json:"value_bnb"`
+	UnrealizedPL float64         `json:"unrealized_pl"`
+	MarkedAt     *time.Time      `json:"marked_at"`
+	OpenedAt     *time.Time      `json:"opened_at"`
+	// PeakPriceBNB is the highest marked price since entry, used by trailing stops.
+	PeakPriceBNB decimal.Decimal `gorm:"type:decimal(36,18)" json:"peak_price_bnb"`
+	PeakAt       *time.Time      `json:"peak_at"`
+	// TakeProfitHits counts take-profit levels already executed for the open position.
+	TakeProfitHits int       `gorm:"default:0" json:"take_profit_hits"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime" json:"updated_at"`
 }
 
 func (AIPosition) TableName() string {
@@ -1,28 +1,57 @@
 package model
 
-import "time"
+import (
+	"time"
 
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// AITrade amounts are stored twice: AmountIn and AmountOut in display units
+// (BNB, or whole tokens scaled by TokenDecimals) and AmountInWei and
+// AmountOutWei as the raw on-chain integers.
 type AITrade struct {
-	ID           string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID       string    `gorm:"index;not null" json:"user_id"`
-	TokenAddress string    `gorm:"index;not null" json:"token_address"`
-	TokenSymbol  string    `json:"token_symbol"`
-	Type         string    `json:"type"` // BUY | SELL
-	AmountIn     string    `json:"amount_in"`
-	AmountOut    string    `json:"amount_out"`
-	TxHash       string    `gorm:"uniqueIndex" json:"tx_hash"`
-	Timestamp    time.Time `gorm:"autoCreateTime" json:"timestamp"`
-	Status       string    `json:"status"` // pending | success | failed
-	GasUsed      string    `json:"gas_used"`
-	BlockNumber  uint64    `json:"block_number"`
-	ErrorMessage string    `json:"error_message"`
+	ID           string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string          `gorm:"index;not null" json:"user_id"`
+	TokenAddress string          `gorm:"index;not null" json:"token_address"`
+	TokenSymbol  string          `json:"token_symbol"`
+	Type         string          `json:"type"` // BUY | SELL
+	AmountIn     decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"amount_in"`
+	AmountOut    decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"amount_out"`
+	AmountInWei  decimal.Decimal `gorm:"type:decimal(78,0);default:0" json:"amount_in_wei"`
+	AmountOutWei decimal.Decimal `gorm:"type:decimal(78,0);default:0" json:"amount_out_wei"`
+	// TokenDecimals scales the token side of the trade: the output of a BUY
+	// and the input of a SELL. The BNB side always has 18 decimals.
+	TokenDecimals int       `gorm:"default:18" json:"token_decimals"`
+	TxHash        string    `gorm:"uniqueIndex" json:"tx_hash"`
+	Timestamp     time.Time `gorm:"autoCreateTime" json:"timestamp"`
+	Status        string    `json:"status"` // pending | success | failed
+	// GasUsed is in gas units; GasFeeBNB is what it cost.
+	GasUsed      uint64 `json:"gas_used"`
+	BlockNumber  uint64 `json:"block_number"`
+	ErrorMessage string `json:"error_message"`
+	// Simulated marks paper trades, which were filled against a quote and
+	// never broadcast.
+	Simulated bool `gorm:"index;default:false" json:"simulated"`
 
 	GoldenDogScore int    `json:"golden_dog_score"`
 	DecisionReason string `json:"decision_reason"`
 	StrategyUsed   string `json:"strategy_used"`
+	// OrderID is the resting order that placed the trade, if any.
+	OrderID string `gorm:"index" json:"order_id"`
+	// DecisionTrail records the inputs and checks behind an automated trade.
+	DecisionTrail datatypes.JSON `json:"decision_trail" swaggertype:"object"`
 
-	CurrentValue string  `json:"current_value"`
-	ProfitLoss   float64 `json:"profit_loss"`
+	// CurrentValue is the BNB value of the tokens a BUY received that are
+	// still held, as last marked; it drops to 0 once they are all sold.
+	CurrentValue decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"current_value"`
+	// ProfitLoss is the realized return of a sell as a ratio of its cost basis.
+	ProfitLoss decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"profit_loss"`
+	// GasFeeBNB is the gas paid by the swap transaction.
+	GasFeeBNB decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"gas_fee_bnb"`
+	// RealizedPLBNB and RealizedPLUSD are a sell's ledger P&L net of gas.
+	RealizedPLBNB decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"realized_pl_bnb"`
+	RealizedPLUSD decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"realized_pl_usd"`
 }
 
 func (AITrade) TableName() string {
@@ -0,0 +1,24 @@
+package model
+
+import "time"
+
+// AnalysisAgent is an analyst or agent that submits token analyses. Its
+// accuracy weighs its verdicts in the consensus; it is the F1 score of its
+// golden dog calls, so an agent that never calls one earns little weight.
+type AnalysisAgent struct {
+	ID         string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	AgentID    string     `gorm:"uniqueIndex;not null" json:"agent_id"`
+	Samples    int        `gorm:"default:0" json:"samples"`
+	Correct    int        `gorm:"default:0" json:"correct"`
+	Precision  float64    `gorm:"default:0.5" json:"precision"` // share of golden dog calls that were right
+	Recall     float64    `gorm:"default:0.5" json:"recall"`    // share of golden dogs it called
+	Accuracy   float64    `gorm:"default:0.5" json:"accuracy"`
+	AccuracyAt *time.Time `json:"accuracy_at"`
+	LastSeenAt *time.Time `json:"last_seen_at"`
+	CreatedAt  time.Time  `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time  `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (AnalysisAgent) TableName() string {
+	return "analysis_agents"
+}
@@ -1,16 +1,35 @@
 package model
 
-import "time"
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	WalletModeLive  = "live"
+	WalletModePaper = "paper"
+)
 
 type ManagedWallet struct {
-	ID           string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
-	UserID       string    `gorm:"index;not null" json:"user_id"`
-	Address      string    `gorm:"uniqueIndex;not null" json:"address"`
-	EncryptedKey []byte    `json:"-"`
-	Balance      float64   `gorm:"default:0" json:"balance"`
-	MaxBalance   float64   `gorm:"default:5" json:"max_balance"`
-	CreatedAt    time.Time `gorm:"autoCreateTime" json:"created_at"`
-	UpdatedAt    time.Time `gorm:"autoUpdateTime" json:"updated_at"`
+	ID string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	// UserID is unique: every trade, limit and position of a user belongs to
+	// its one wallet.
+	UserID       string `gorm:"uniqueIndex;not null" json:"user_id"`
+	Address      string `gorm:"uniqueIndex;not null" json:"address"`
+	EncryptedKey []byte `json:"-"`
+	// Mode is live or paper. Paper wallets never broadcast; their Balance is
+	// virtual BNB and their positions are virtual holdings.
+	Mode string `gorm:"default:live;not null" json:"mode"`
+	// Balance and MaxBalance are in BNB, not wei.
+	Balance    decimal.Decimal `gorm:"type:decimal(36,18);default:0" json:"balance"`
+	MaxBalance decimal.Decimal `gorm:"type:decimal(36,18);default:5" json:"max_balance"`
+	CreatedAt  time.Time       `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt  time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (w *ManagedWallet) IsPaper() bool {
+	return w.Mode == WalletModePaper
 }
 
 func (ManagedWallet) TableName() string {
@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// OrderLadder is a split entry whose tranches are TradeOrder rows with ParentID set.
+type OrderLadder struct {
+	ID              string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID          string          `gorm:"index;not null" json:"user_id"`
+	TokenAddress    string          `gorm:"index;not null" json:"token_address"`
+	TokenSymbol     string          `json:"token_symbol"`
+	Mode            string          `gorm:"not null" json:"mode"` // time | block | drawdown
+	TotalAmountBNB  decimal.Decimal `gorm:"type:decimal(36,18)" json:"total_amount_bnb"`
+	Tranches        int             `json:"tranches"`
+	IntervalSeconds int             `json:"interval_seconds"`
+	IntervalBlocks  int             `json:"interval_blocks"`
+	DrawdownStep    float64         `json:"drawdown_step"`                    // each tranche rests this fraction further below FirstFillPrice
+	Status          string          `gorm:"index;default:open" json:"status"` // open | filled | partial | failed | cancelled | expired
+	FilledTranches  int             `json:"filled_tranches"`
+	FilledBNB       decimal.Decimal `gorm:"type:decimal(36,18)" json:"filled_bnb"`
+	FilledTokens    decimal.Decimal `gorm:"type:decimal(36,18)" json:"filled_tokens"`
+	AvgPriceBNB     decimal.Decimal `gorm:"type:decimal(36,18)" json:"avg_price_bnb"`
+	FirstFillPrice  decimal.Decimal `gorm:"type:decimal(36,18)" json:"first_fill_price"` // spot price when the first tranche filled
+	ExpiresAt       *time.Time      `json:"expires_at"`
+	CreatedAt       time.Time       `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt       time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (OrderLadder) TableName() string {
+	return "order_ladders"
+}
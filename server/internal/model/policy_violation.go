@@ -0,0 +1,26 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type PolicyViolation struct {
+	ID           string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string         `gorm:"index;not null" json:"user_id"`
+	WalletID     string         `gorm:"index" json:"wallet_id"`
+	Action       string         `json:"action"`
+	Rule         string         `gorm:"index" json:"rule"`
+	Message      string         `json:"message"`
+	TokenAddress string         `json:"token_address"`
+	Details      datatypes.JSON `json:"details"`
+	// Bypassed violations were let through, such as the slippage and price
+	// impact caps an emergency exit ignores.
+	Bypassed  bool      `gorm:"default:false" json:"bypassed"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (PolicyViolation) TableName() string {
+	return "policy_violations"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// PortfolioSnapshot is a periodic mark-to-market valuation of a user's wallet.
+type PortfolioSnapshot struct {
+	ID              string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID          string          `gorm:"index;not null" json:"user_id"`
+	BNBPriceUSD     decimal.Decimal `gorm:"type:decimal(36,18)" json:"bnb_price_usd"`
+	CashBNB         decimal.Decimal `gorm:"type:decimal(36,18)" json:"cash_bnb"`
+	PositionsBNB    decimal.Decimal `gorm:"type:decimal(36,18)" json:"positions_bnb"`
+	TotalBNB        decimal.Decimal `gorm:"type:decimal(36,18)" json:"total_bnb"`
+	TotalUSD        decimal.Decimal `gorm:"type:decimal(36,18)" json:"total_usd"`
+	CostBNB         decimal.Decimal `gorm:"type:decimal(36,18)" json:"cost_bnb"`
+	UnrealizedPLBNB decimal.Decimal `gorm:"type:decimal(36,18)" json:"unrealized_pl_bnb"`
+	OpenPositions   int             `json:"open_positions"`
+	// Positions holds the per-token valuations behind the totals.
+	Positions datatypes.JSON `json:"positions" swaggertype:"object"`
+	CreatedAt time.Time      `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (PortfolioSnapshot) TableName() string {
+	return "portfolio_snapshots"
+}
@@ -0,0 +1,29 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// PositionLot is the tokens received by one buy. Sells consume lots so that
+// realized P&L is measured against the cost of the tokens actually sold.
+type PositionLot struct {
+	ID           string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string          `gorm:"index:idx_position_lots_owner;not null" json:"user_id"`
+	TokenAddress string          `gorm:"index:idx_position_lots_owner;not null" json:"token_address"`
+	BuyTxHash    string          `gorm:"index" json:"buy_tx_hash"`
+	Quantity     decimal.Decimal `gorm:"type:decimal(36,18)" json:"quantity"`
+	Remaining    decimal.Decimal `gorm:"type:decimal(36,18)" json:"remaining"`
+	// CostBNB is the BNB spent including gas; buy taxes show up as fewer tokens received.
+	CostBNB      decimal.Decimal `gorm:"type:decimal(36,18)" json:"cost_bnb"`
+	CostPerToken decimal.Decimal `gorm:"type:decimal(36,18)" json:"cost_per_token"`
+	OpenedAt     time.Time       `gorm:"index" json:"opened_at"`
+	ClosedAt     *time.Time      `json:"closed_at"`
+	// Simulated marks lots of paper buys; only paper sells consume them.
+	Simulated bool `gorm:"index;default:false" json:"simulated"`
+}
+
+func (PositionLot) TableName() string {
+	return "position_lots"
+}
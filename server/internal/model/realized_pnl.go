@@ -0,0 +1,36 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
+)
+
+// RealizedPnL is the ledger entry of one sell, or of the gas burnt by a failed
+// transaction, in absolute BNB and USD.
+type RealizedPnL struct {
+	ID           string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string          `gorm:"index;not null" json:"user_id"`
+	TokenAddress string          `gorm:"index;not null" json:"token_address"`
+	TokenSymbol  string          `json:"token_symbol"`
+	TxHash       string          `gorm:"uniqueIndex" json:"tx_hash"`
+	CostBasis    string          `json:"cost_basis"` // fifo | average
+	Quantity     decimal.Decimal `gorm:"type:decimal(36,18)" json:"quantity"`
+	// ProceedsBNB is the BNB received after sell taxes, before gas.
+	ProceedsBNB decimal.Decimal `gorm:"type:decimal(36,18)" json:"proceeds_bnb"`
+	CostBNB     decimal.Decimal `gorm:"type:decimal(36,18)" json:"cost_bnb"`
+	GasBNB      decimal.Decimal `gorm:"type:decimal(36,18)" json:"gas_bnb"`
+	RealizedBNB decimal.Decimal `gorm:"type:decimal(36,18)" json:"realized_bnb"`
+	RealizedUSD decimal.Decimal `gorm:"type:decimal(36,18)" json:"realized_usd"`
+	BNBPriceUSD decimal.Decimal `gorm:"type:decimal(36,18)" json:"bnb_price_usd"`
+	// Lots lists the lots consumed and how much of each.
+	Lots datatypes.JSON `json:"lots" swaggertype:"object"`
+	// Simulated marks paper trades, which stay out of live loss limits.
+	Simulated bool      `gorm:"index;default:false" json:"simulated"`
+	CreatedAt time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (RealizedPnL) TableName() string {
+	return "realized_pnls"
+}
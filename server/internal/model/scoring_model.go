@@ -0,0 +1,334 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// ScoringModel is one version of the scoring configuration. Versions are
+// immutable; exactly one is active at a time.
+type ScoringModel struct {
+	ID          string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Version     int            `gorm:"uniqueIndex;not null" json:"version"`
+	Config      datatypes.JSON `gorm:"not null" json:"config" swaggertype:"object"`
+	Active      bool           `gorm:"index;default:false" json:"active"`
+	Note        string         `json:"note"`
+	CreatedBy   string         `json:"created_by"`
+	ActivatedAt *time.Time     `json:"activated_at"`
+	CreatedAt   time.Time      `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (ScoringModel) TableName() string {
+	return "scoring_models"
+}
+
+// ScoringConfig holds every tunable of the baseline scorer and the golden dog
+// time decay.
+type ScoringConfig struct {
+	// Version is the ScoringModel version the config was loaded from; 0 is
+	// the built-in default.
+	Version    int               `json:"version"`
+	Levels     ScoringLevels     `json:"levels"`
+	Weights    map[string]int    `json:"weights"`
+	Thresholds ScoringThresholds `json:"thresholds"`
+	// Phases are consecutive age windows. Decay runs linearly from DecayFrom
+	// at the start of a phase to DecayTo at its end. Trading rules match
+	// phases by name, so renaming one changes which tokens they accept.
+	Phases        []ScoringPhase    `json:"phases"`
+	ExpiredPhase  string            `json:"expiredPhase"`
+	ExpiredDecay  float64           `json:"expiredDecay"`
+	GoldenDogList GoldenDogListSize `json:"goldenDogList"`
+	Priority      ScoringPriority   `json:"priority"`
+}
+
+type ScoringLevels struct {
+	SafeScore      int `json:"safeScore"`
+	WarningScore   int `json:"warningScore"`
+	GoldenDogScore int `json:"goldenDogScore"`
+	// RiskShare is the part of the risk score a golden dog score starts from.
+	RiskShare float64 `json:"riskShare"`
+	// A risk category is HIGH or MEDIUM once it loses this many points.
+	CategoryHigh   int `json:"categoryHigh"`
+	CategoryMedium int `json:"categoryMedium"`
+}
+
+type ScoringThresholds struct {
+	TaxExtreme           float64 `json:"taxExtreme"`
+	TaxHigh              float64 `json:"taxHigh"`
+	TaxMedium            float64 `json:"taxMedium"`
+	Top10High            float64 `json:"top10High"`
+	Top10Medium          float64 `json:"top10Medium"`
+	MinHolders           int     `json:"minHolders"`
+	HolderBase           int     `json:"holderBase"`
+	SellPressureRatio    float64 `json:"sellPressureRatio"`
+	MinTxnsH1            int     `json:"minTxnsH1"`
+	SerialDeployerHigh   int     `json:"serialDeployerHigh"`
+	SerialDeployerMedium int     `json:"serialDeployerMedium"`
+	LiquidityLowUSD      float64 `json:"liquidityLowUsd"`
+	LiquidityMediumUSD   float64 `json:"liquidityMediumUsd"`
+	LiquidityDeepUSD     float64 `json:"liquidityDeepUsd"`
+	// LiquidityDrop is the relative change between two market refreshes
+	// that raises a LIQUIDITY_DROP alert.
+	LiquidityDrop     float64 `json:"liquidityDrop"`
+	PriceSurgeH1      float64 `json:"priceSurgeH1"`
+	PriceMomentumH1   float64 `json:"priceMomentumH1"`
+	PriceDumpH1       float64 `json:"priceDumpH1"`
+	ActiveVolumeRatio float64 `json:"activeVolumeRatio"`
+}
+
+type ScoringPhase struct {
+	Name         string  `json:"name"`
+	UntilMinutes int     `json:"untilMinutes"`
+	DecayFrom    float64 `json:"decayFrom"`
+	DecayTo      float64 `json:"decayTo"`
+}
+
+// GoldenDogListSize is how many golden dogs the list endpoint loads before
+// dropping expired ones.
+type GoldenDogListSize struct {
+	FetchMultiplier int `json:"fetchMultiplier"`
+	MinFetch        int `json:"minFetch"`
+	MaxFetch        int `json:"maxFetch"`
+}
+
+// ScoringPriority orders the analysis queue. Each signal earns up to its
+// points, saturating at its level, and the freshness points decay with the
+// golden dog phases. Every minute a token waits after enrichment adds
+// AgingPerMinute, up to MaxAging, so low priorities still reach an agent
+// without old tokens outranking fresh ones.
+type ScoringPriority struct {
+	LiquidityPoints    float64 `json:"liquidityPoints"`
+	LiquidityUSD       float64 `json:"liquidityUsd"`
+	BuyFlowPoints      float64 `json:"buyFlowPoints"`
+	HolderGrowthPoints float64 `json:"holderGrowthPoints"`
+	HoldersPerMinute   float64 `json:"holdersPerMinute"`
+	CreatorPoints      float64 `json:"creatorPoints"`
+	FreshnessPoints    float64 `json:"freshnessPoints"`
+	AgingPerMinute     float64 `json:"agingPerMinute"`
+	MaxAging           float64 `json:"maxAging"`
+}
+
+// DefaultScoringWeights are the points of every baseline rule. Risk rules
+// are negative; momentum rules add to the golden dog score.
+func DefaultScoringWeights() map[string]int {
+	return map[string]int{
+		"honeypot":                    -100,
+		"tax_extreme":                 -60,
+		"tax_high":                    -30,
+		"tax_medium":                  -12,
+		"mintable":                    -25,
+		"take_back_ownership":         -25,
+		"proxy":                       -10,
+		"closed_source":               -15,
+		"holder_concentration_high":   -30,
+		"holder_concentration_medium": -12,
+		"few_holders":                 -10,
+		"sell_pressure":               -12,
+		"creator_honeypots":           -40,
+		"serial_deployer_high":        -15,
+		"serial_deployer_medium":      -5,
+		"no_liquidity":                -15,
+		"liquidity_low":               -30,
+		"liquidity_medium":            -12,
+		"liquidity_drop":              -25,
+		"price_surge":                 20,
+		"price_momentum":              10,
+		"price_dump":                  -20,
+		"buy_pressure":                10,
+		"deep_liquidity_high":         10,
+		"deep_liquidity_medium":       5,
+		"active_trading":              5,
+		"holder_base":                 5,
+	}
+}
+
+func DefaultScoringConfig() *ScoringConfig {
+	return &ScoringConfig{
+		Levels: ScoringLevels{
+			SafeScore:      70,
+			WarningScore:   45,
+			GoldenDogScore: 60,
+			RiskShare:      0.5,
+			CategoryHigh:   25,
+			CategoryMedium: 10,
+		},
+		Weights: DefaultScoringWeights(),
+		Thresholds: ScoringThresholds{
+			TaxExtreme:           0.5,
+			TaxHigh:              0.15,
+			TaxMedium:            0.08,
+			Top10High:            0.8,
+			Top10Medium:          0.6,
+			MinHolders:           50,
+			HolderBase:           200,
+			SellPressureRatio:    2,
+			MinTxnsH1:            20,
+			SerialDeployerHigh:   10,
+			SerialDeployerMedium: 3,
+			LiquidityLowUSD:      1000,
+			LiquidityMediumUSD:   5000,
+			LiquidityDeepUSD:     50000,
+			LiquidityDrop:        -0.4,
+			PriceSurgeH1:         50,
+			PriceMomentumH1:      10,
+			PriceDumpH1:          -30,
+			ActiveVolumeRatio:    0.5,
+		},
+		Phases: []ScoringPhase{
+			{Name: "EARLY", UntilMinutes: 30, DecayFrom: 1.0, DecayTo: 1.0},
+			{Name: "PEAK", UntilMinutes: 120, DecayFrom: 1.0, DecayTo: 0.8},
+			{Name: "DECLINING", UntilMinutes: 360, DecayFrom: 0.8, DecayTo: 0.5},
+		},
+		ExpiredPhase: "EXPIRED",
+		ExpiredDecay: 0.4,
+		GoldenDogList: GoldenDogListSize{
+			FetchMultiplier: 5,
+			MinFetch:        20,
+			MaxFetch:        200,
+		},
+		Priority: ScoringPriority{
+			LiquidityPoints:    30,
+			LiquidityUSD:       50000,
+			BuyFlowPoints:      20,
+			HolderGrowthPoints: 20,
+			HoldersPerMinute:   5,
+			CreatorPoints:      15,
+			FreshnessPoints:    15,
+			AgingPerMinute:     0.5,
+			MaxAging:           30,
+		},
+	}
+}
+
+// ParseScoringConfig reads a config over the defaults, so a config only
+// needs the fields it changes. Weights merge per rule; phases replace.
+func ParseScoringConfig(raw []byte) (*ScoringConfig, error) {
+	cfg := DefaultScoringConfig()
+	if len(raw) > 0 {
+		if err := json.Unmarshal(raw, cfg); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *ScoringConfig) Validate() error {
+	known := DefaultScoringWeights()
+	for rule := range c.Weights {
+		if _, ok := known[rule]; !ok {
+			return fmt.Errorf("unknown weight %q", rule)
+		}
+	}
+	if c.Levels.SafeScore <= c.Levels.WarningScore || c.Levels.WarningScore < 0 || c.Levels.SafeScore > 100 {
+		return fmt.Errorf("levels need 0 <= warningScore < safeScore <= 100")
+	}
+	if c.Levels.GoldenDogScore < 0 || c.Levels.GoldenDogScore > 100 {
+		return fmt.Errorf("goldenDogScore must be 0-100")
+	}
+	if c.Levels.RiskShare < 0 || c.Levels.RiskShare > 1 {
+		return fmt.Errorf("riskShare must be 0-1")
+	}
+	if c.Thresholds.LiquidityDrop <= -1 || c.Thresholds.LiquidityDrop >= 0 {
+		return fmt.Errorf("liquidityDrop must be between -1 and 0")
+	}
+	if len(c.Phases) == 0 {
+		return fmt.Errorf("at least one phase is required")
+	}
+	prev := 0
+	for _, phase := range c.Phases {
+		if phase.Name == "" || phase.UntilMinutes <= prev {
+			return fmt.Errorf("phases need names and increasing untilMinutes")
+		}
+		if !validDecay(phase.DecayFrom) || !validDecay(phase.DecayTo) {
+			return fmt.Errorf("phase %s decay must be 0-1", phase.Name)
+		}
+		prev = phase.UntilMinutes
+	}
+	if c.ExpiredPhase == "" || !validDecay(c.ExpiredDecay) {
+		return fmt.Errorf("expiredPhase is required and expiredDecay must be 0-1")
+	}
+	if c.GoldenDogList.FetchMultiplier < 1 || c.GoldenDogList.MinFetch < 1 || c.GoldenDogList.MaxFetch < c.GoldenDogList.MinFetch {
+		return fmt.Errorf("goldenDogList needs fetchMultiplier >= 1 and 1 <= minFetch <= maxFetch")
+	}
+	p := c.Priority
+	if p.LiquidityPoints < 0 || p.BuyFlowPoints < 0 || p.HolderGrowthPoints < 0 || p.CreatorPoints < 0 || p.FreshnessPoints < 0 || p.AgingPerMinute < 0 || p.MaxAging < 0 {
+		return fmt.Errorf("priority points, agingPerMinute and maxAging must not be negative")
+	}
+	if p.LiquidityUSD <= 0 || p.HoldersPerMinute <= 0 {
+		return fmt.Errorf("priority liquidityUsd and holdersPerMinute must be positive")
+	}
+	return nil
+}
+
+func validDecay(v float64) bool {
+	return v >= 0 && v <= 1
+}
+
+// Weight is the points of a rule, falling back to the default.
+func (c *ScoringConfig) Weight(rule string) int {
+	if w, ok := c.Weights[rule]; ok {
+		return w
+	}
+	return DefaultScoringWeights()[rule]
+}
+
+// PhaseAt returns the phase of a token of the given age and its decay factor.
+func (c *ScoringConfig) PhaseAt(age time.Duration) (string, float64) {
+	start := time.Duration(0)
+	for _, phase := range c.Phases {
+		end := time.Duration(phase.UntilMinutes) * time.Minute
+		if age <= end {
+			progress := 0.0
+			if age > start {
+				progress = float64(age-start) / float64(end-start)
+			}
+			return phase.Name, phase.DecayFrom + (phase.DecayTo-phase.DecayFrom)*progress
+		}
+		start = end
+	}
+	return c.ExpiredPhase, c.ExpiredDecay
+}
+
+// AnalysisWindow is how long after launch a token is worth analyzing: the
+// end of the last phase, after which it is expired.
+func (c *ScoringConfig) AnalysisWindow() time.Duration {
+	return time.Duration(c.Phases[len(c.Phases)-1].UntilMinutes) * time.Minute
+}
+
+// GoldenDogFetchLimit is how many golden dogs to load for a list of limit.
+func (c *ScoringConfig) GoldenDogFetchLimit(limit int) int {
+	size := c.GoldenDogList
+	fetch := limit * size.FetchMultiplier
+	if fetch < size.MinFetch {
+		fetch = size.MinFetch
+	}
+	if fetch > size.MaxFetch {
+		fetch = size.MaxFetch
+	}
+	return fetch
+}
+
+var activeScoring atomic.Pointer[ScoringConfig]
+
+// ActiveScoring returns the scoring config in effect, the defaults until a
+// model version is loaded.
+func ActiveScoring() *ScoringConfig {
+	if cfg := activeScoring.Load(); cfg != nil {
+		return cfg
+	}
+	return defaultScoring
+}
+
+// SetActiveScoring swaps the scoring config every scorer reads.
+func SetActiveScoring(cfg *ScoringConfig) {
+	activeScoring.Store(cfg)
+}
+
+var defaultScoring = DefaultScoringConfig()
@@ -0,0 +1,59 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseScoringConfig(t *testing.T) {
+	tests := []struct {
+		name    string
+		raw     string
+		wantErr bool
+	}{
+		{name: "empty keeps defaults", raw: ""},
+		{name: "partial override", raw: `{"levels":{"safeScore":80,"warningScore":50,"goldenDogScore":70,"riskShare":0.5}}`},
+		{name: "known weight", raw: `{"weights":{"honeypot":60}}`},
+		{name: "unknown weight", raw: `{"weights":{"moon":10}}`, wantErr: true},
+		{name: "warning above safe", raw: `{"levels":{"safeScore":40,"warningScore":60}}`, wantErr: true},
+		{name: "golden dog score above 100", raw: `{"levels":{"safeScore":70,"warningScore":40,"goldenDogScore":120}}`, wantErr: true},
+		{name: "risk share above 1", raw: `{"levels":{"safeScore":70,"warningScore":40,"goldenDogScore":60,"riskShare":2}}`, wantErr: true},
+		{name: "liquidity drop not negative", raw: `{"thresholds":{"liquidityDrop":0.2}}`, wantErr: true},
+		{name: "no phases", raw: `{"phases":[]}`, wantErr: true},
+		{name: "phases out of order", raw: `{"phases":[{"name":"A","untilMinutes":60,"decayFrom":1,"decayTo":1},{"name":"B","untilMinutes":30,"decayFrom":1,"decayTo":0.5}]}`, wantErr: true},
+		{name: "decay above 1", raw: `{"phases":[{"name":"A","untilMinutes":60,"decayFrom":1.5,"decayTo":1}]}`, wantErr: true},
+		{name: "missing expired phase", raw: `{"expiredPhase":""}`, wantErr: true},
+		{name: "min fetch above max", raw: `{"goldenDogList":{"fetchMultiplier":2,"minFetch":50,"maxFetch":10}}`, wantErr: true},
+		{name: "negative priority points", raw: `{"priority":{"liquidityPoints":-1}}`, wantErr: true},
+		{name: "zero liquidity reference", raw: `{"priority":{"liquidityUsd":0}}`, wantErr: true},
+		{name: "malformed json", raw: `{"weights":`, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := ParseScoringConfig([]byte(tt.raw))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseScoringConfig() err = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestScoringConfigPhaseAt(t *testing.T) {
+	cfg := DefaultScoringConfig()
+	tests := []struct {
+		age   time.Duration
+		phase string
+		decay float64
+	}{
+		{age: 0, phase: "EARLY", decay: 1},
+		{age: 75 * time.Minute, phase: "PEAK", decay: 0.9},
+		{age: 240 * time.Minute, phase: "DECLINING", decay: 0.65},
+		{age: 10 * time.Hour, phase: cfg.ExpiredPhase, decay: cfg.ExpiredDecay},
+	}
+	for _, tt := range tests {
+		phase, decay := cfg.PhaseAt(tt.age)
+		if phase != tt.phase || decay < tt.decay-1e-9 || decay > tt.decay+1e-9 {
+			t.Errorf("PhaseAt(%s) = %s, %v, want %s, %v", tt.age, phase, decay, tt.phase, tt.decay)
+		}
+	}
+}
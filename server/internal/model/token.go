@@ -31,6 +31,15 @@ type Token struct {
 	SmartMoneySignals   datatypes.JSON  `json:"smart_money_signals"`
 	LastMarketRefreshAt *time.Time      `json:"last_market_refresh_at"`
 	AnalysisResult      datatypes.JSON  `json:"analysis_result"`
+	CurrentAnalysisID   *string         `gorm:"type:uuid" json:"current_analysis_id"`
+	Consensus           datatypes.JSON  `json:"consensus"`
+	AgentCount          int             `gorm:"default:0" json:"agent_count"`
+	AgentDisagreement   bool            `gorm:"default:false" json:"agent_disagreement"`
+	AnalysisPriority    float64         `gorm:"index;default:0" json:"analysis_priority"`     // queue priority at enrichment, before aging
+	LeaseOwner          string          `gorm:"index;not null;default:''" json:"lease_owner"` // agent analyzing the token
+	LeaseExpiresAt      *time.Time      `gorm:"index" json:"lease_expires_at"`
+	AnalysisSource      string          `gorm:"not null;default:''" json:"analysis_source"` // baseline, agent
+	ScoringVersion      int             `gorm:"default:0" json:"scoring_version"`           // scoring model version of a baseline score
 	IsGoldenDog         bool            `gorm:"default:false" json:"is_golden_dog"`
 	GoldenDogScore      int             `gorm:"default:0" json:"golden_dog_score"`
 	IsHoneypot          bool            `gorm:"default:false" json:"is_honeypot"`
@@ -46,46 +55,62 @@ func (Token) TableName() string {
 	return "tokens"
 }
 
+// Analysis sources. A baseline analysis is the server's rule-based score; an
+// agent analysis always replaces it and is never overwritten by one.
+const (
+	AnalysisSourceBaseline = "baseline"
+	AnalysisSourceAgent    = "agent"
+)
+
+// DefaultAgentID attributes agent analyses submitted without an identity.
+const DefaultAgentID = "default"
+
 func (t *Token) GoldenDogPhase() string {
-	age := time.Since(t.CreatedAt)
-	switch {
-	case age <= 30*time.Minute:
-		return "EARLY"
-	case age <= 2*time.Hour:
-		return "PEAK"
-	case age <= 6*time.Hour:
-		return "DECLINING"
-	default:
-		return "EXPIRED"
-	}
+	return t.GoldenDogPhaseAt(time.Now())
+}
+
+// GoldenDogPhaseAt is the phase the token was in at now, for replaying
+// historical signals. Boundaries come from the active scoring model.
+func (t *Token) GoldenDogPhaseAt(now time.Time) string {
+	phase, _ := ActiveScoring().PhaseAt(now.Sub(t.CreatedAt))
+	return phase
 }
 
 func (t *Token) TimeDecayFactor() float64 {
-	age := time.Since(t.CreatedAt)
-	switch {
-	case age <= 30*time.Minute:
-		return 1.0
-	case age <= 2*time.Hour:
-		// Linear from 1.0 at 30m to 0.8 at 2h.
-		progress := float64(age-30*time.Minute) / float64(90*time.Minute)
-		return 1.0 - 0.2*progress
-	case age <= 6*time.Hour:
-		// Linear from 0.8 at 2h to 0.5 at 6h.
-		progress := float64(age-2*time.Hour) / float64(4*time.Hour)
-		return 0.8 - 0.3*progress
-	default:
-		return 0.4
-	}
+	return t.TimeDecayFactorAt(time.Now())
+}
+
+func (t *Token) TimeDecayFactorAt(now time.Time) float64 {
+	_, decay := ActiveScoring().PhaseAt(now.Sub(t.CreatedAt))
+	return decay
 }
 
 func (t *Token) EffectiveScore() int {
-	score := float64(t.GoldenDogScore) * t.TimeDecayFactor()
+	return t.EffectiveScoreAt(time.Now())
+}
+
+func (t *Token) EffectiveScoreAt(now time.Time) int {
+	score := float64(t.GoldenDogScore) * t.TimeDecayFactorAt(now)
 	if score < 0 {
 		return 0
 	}
 	return int(score + 0.5)
 }
 
+// QueuePriorityAt is the analysis queue priority at now: the priority from
+// enrichment plus the aging earned while waiting, capped at maxAging.
+func (t *Token) QueuePriorityAt(now time.Time, agingPerMinute, maxAging float64) float64 {
+	since := t.CreatedAt
+	if t.EnrichedAt != nil {
+		since = *t.EnrichedAt
+	}
+	waited := now.Sub(since).Minutes()
+	if waited < 0 {
+		waited = 0
+	}
+	return t.AnalysisPriority + min(agingPerMinute*waited, maxAging)
+}
+
 type RiskDetailsJSON struct {
 	CanMint           bool    `json:"can_mint"`
 	CanPause          bool    `json:"can_pause"`
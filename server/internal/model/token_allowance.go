@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TokenAllowance struct {
+	ID            string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	WalletID      string          `gorm:"uniqueIndex:idx_allowance_wallet_token_spender,priority:1;not null" json:"wallet_id"`
+	UserID        string          `gorm:"index;not null" json:"user_id"`
+	TokenAddress  string          `gorm:"uniqueIndex:idx_allowance_wallet_token_spender,priority:2;not null" json:"token_address"`
+	Spender       string          `gorm:"uniqueIndex:idx_allowance_wallet_token_spender,priority:3;not null" json:"spender"`
+	Amount        decimal.Decimal `gorm:"type:decimal(78,0)" json:"amount"` // raw token units
+	LastTxHash    string          `json:"last_tx_hash"`
+	LastCheckedAt time.Time       `json:"last_checked_at"`
+	UpdatedAt     time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TokenAllowance) TableName() string {
+	return "token_allowances"
+}
@@ -0,0 +1,30 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TokenAnalysis is one verdict on a token. Every agent submission and every
+// change of the baseline verdict is kept; the token row points at the
+// current one.
+type TokenAnalysis struct {
+	ID             string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TokenAddress   string         `gorm:"index;not null" json:"token_address"`
+	Source         string         `gorm:"index;not null" json:"source"` // baseline, agent
+	AgentID        string         `gorm:"index" json:"agent_id"`
+	Model          string         `json:"model"`         // "baseline" or the agent's model
+	ModelVersion   string         `json:"model_version"` // scoring model version for baseline
+	RiskScore      int            `json:"risk_score"`
+	RiskLevel      string         `json:"risk_level"`
+	IsGoldenDog    bool           `json:"is_golden_dog"`
+	GoldenDogScore int            `json:"golden_dog_score"`
+	HoneypotRisk   string         `json:"honeypot_risk"` // LOW | MEDIUM | HIGH, empty when not assessed
+	Payload        datatypes.JSON `json:"payload" swaggertype:"object"`
+	CreatedAt      time.Time      `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (TokenAnalysis) TableName() string {
+	return "token_analyses"
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TokenOutcome records what happened to a token by a fixed horizon after
+// launch. Metrics holds the measurements and Evidence the reason behind each
+// label.
+type TokenOutcome struct {
+	ID           string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	TokenAddress string         `gorm:"uniqueIndex:idx_token_outcome_horizon,priority:1;not null" json:"token_address"`
+	Horizon      string         `gorm:"uniqueIndex:idx_token_outcome_horizon,priority:2;not null" json:"horizon"` // 1h, 6h, 24h
+	HorizonAt    time.Time      `gorm:"index" json:"horizon_at"`
+	Outcome      string         `gorm:"index" json:"outcome"` // first of Labels
+	Labels       datatypes.JSON `json:"labels" swaggertype:"array,string"`
+	MaxReturn    float64        `json:"max_return"` // peak price over entry price
+	Metrics      datatypes.JSON `json:"metrics" swaggertype:"object"`
+	Evidence     datatypes.JSON `json:"evidence" swaggertype:"object"`
+	// Live is true when the chain state was read before the horizon passed;
+	// otherwise only stored snapshots up to the horizon were used.
+	Live        bool      `json:"live"`
+	EvaluatedAt time.Time `json:"evaluated_at"`
+	CreatedAt   time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (TokenOutcome) TableName() string {
+	return "token_outcomes"
+}
+
+// Outcome labels in the order they are listed. A token carries every label
+// that applies; 10x implies 2x, and survived excludes the first three.
+const (
+	OutcomeHoneypot = "honeypot_confirmed"
+	OutcomeRugged   = "rugged"
+	OutcomeDead     = "dead"
+	Outcome10x      = "10x"
+	Outcome2x       = "2x"
+	OutcomeSurvived = "survived"
+	OutcomeNoData   = "no_data"
+)
@@ -0,0 +1,52 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+// TradeApproval is a trade held back by ConfirmThreshold until a human signs off.
+type TradeApproval struct {
+	ID            string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID        string         `gorm:"index;not null" json:"user_id"`
+	TokenAddress  string         `gorm:"index;not null" json:"token_address"`
+	TokenSymbol   string         `json:"token_symbol"`
+	Type          string         `json:"type"` // BUY | SELL
+	AmountIn      string         `json:"amount_in"`
+	AmountOut     string         `json:"amount_out"`
+	GoldenScore   int            `json:"golden_score"`
+	Reason        string         `json:"reason"`
+	StrategyUsed  string         `json:"strategy_used"`
+	DecisionTrail datatypes.JSON `json:"decision_trail" swaggertype:"object"`
+	OrderID       string         `json:"order_id"`                            // resting order that placed the trade, if any
+	Status        string         `gorm:"index;default:pending" json:"status"` // pending | approved | rejected | expired | executed | failed
+	ExpiresAt     time.Time      `gorm:"index" json:"expires_at"`
+	DecidedBy     string         `json:"decided_by"`
+	DecidedAt     *time.Time     `json:"decided_at"`
+	DecisionNote  string         `json:"decision_note"`
+	TradeID       string         `json:"trade_id"`
+	TxHash        string         `json:"tx_hash"`
+	ErrorMessage  string         `json:"error_message"`
+	CreatedAt     time.Time      `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt     time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TradeApproval) TableName() string {
+	return "trade_approvals"
+}
+
+// TradeApprovalEvent is the audit trail of a TradeApproval.
+type TradeApprovalEvent struct {
+	ID         string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	ApprovalID string    `gorm:"index;not null" json:"approval_id"`
+	UserID     string    `gorm:"index;not null" json:"user_id"`
+	Action     string    `gorm:"not null" json:"action"` // created | approved | rejected | expired | executed | failed
+	Actor      string    `json:"actor"`
+	Note       string    `json:"note"`
+	CreatedAt  time.Time `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (TradeApprovalEvent) TableName() string {
+	return "trade_approval_events"
+}
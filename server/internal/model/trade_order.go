@@ -0,0 +1,37 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type TradeOrder struct {
+	ID           string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string          `gorm:"index;not null" json:"user_id"`
+	ParentID     string          `gorm:"index" json:"parent_id"` // OrderLadder this tranche belongs to
+	TrancheIndex int             `json:"tranche_index"`
+	TokenAddress string          `gorm:"index;not null" json:"token_address"`
+	TokenSymbol  string          `json:"token_symbol"`
+	Side         string          `gorm:"not null" json:"side"`    // BUY | SELL
+	Trigger      string          `gorm:"not null" json:"trigger"` // price_below | price_above | liquidity_above | golden_score_above | at_time | at_block
+	TriggerValue decimal.Decimal `gorm:"type:decimal(36,18)" json:"trigger_value"`
+	AmountIn     string          `gorm:"not null" json:"amount_in"` // BNB for BUY; token amount, ratio or ALL for SELL
+	AmountOutMin string          `json:"amount_out_min"`
+	AmountOut    string          `json:"amount_out"`
+	Status       string          `gorm:"index;default:open" json:"status"` // waiting | open | executing | awaiting_approval | filled | failed | cancelled | expired
+	ExpiresAt    *time.Time      `gorm:"index" json:"expires_at"`
+	TriggeredAt  *time.Time      `json:"triggered_at"`
+	SpotPrice    decimal.Decimal `gorm:"type:decimal(36,18)" json:"spot_price"` // token price in BNB from pair reserves when triggered, before the swap
+	FilledAt     *time.Time      `json:"filled_at"`
+	ApprovalID   string          `gorm:"index" json:"approval_id"` // TradeApproval an awaiting_approval order waits on
+	TradeID      string          `json:"trade_id"`
+	TxHash       string          `json:"tx_hash"`
+	ErrorMessage string          `json:"error_message"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime;index" json:"created_at"`
+	UpdatedAt    time.Time       `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (TradeOrder) TableName() string {
+	return "trade_orders"
+}
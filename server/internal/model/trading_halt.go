@@ -0,0 +1,22 @@
+package model
+
+import "time"
+
+// TradingHalt is a tripped kill switch or circuit breaker. It stays active
+// until it is explicitly reset.
+type TradingHalt struct {
+	ID        string     `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	Scope     string     `gorm:"index;not null" json:"scope"` // "global" or "user:<id>"
+	Kind      string     `gorm:"index;not null" json:"kind"`  // kill_switch | consecutive_failures | loss_rate | rpc_degraded | daily_loss
+	Reason    string     `json:"reason"`
+	Active    bool       `gorm:"index;default:true" json:"active"`
+	TrippedBy string     `json:"tripped_by"`
+	TrippedAt time.Time  `json:"tripped_at"`
+	ResetBy   string     `json:"reset_by"`
+	ResetAt   *time.Time `json:"reset_at"`
+	CreatedAt time.Time  `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (TradingHalt) TableName() string {
+	return "trading_halts"
+}
@@ -0,0 +1,19 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+type WalletPolicy struct {
+	ID        string         `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	WalletID  string         `gorm:"uniqueIndex;not null" json:"wallet_id"`
+	UserID    string         `gorm:"index;not null" json:"user_id"`
+	Policy    datatypes.JSON `json:"policy"`
+	UpdatedAt time.Time      `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+func (WalletPolicy) TableName() string {
+	return "wallet_policies"
+}
@@ -0,0 +1,42 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type WalletSecurity struct {
+	ID           string `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string `gorm:"uniqueIndex;not null" json:"user_id"`
+	TOTPSecret   []byte `json:"-"`
+	TOTPEnabled  bool   `gorm:"default:false" json:"totp_enabled"`
+	LastTOTPStep int64  `gorm:"default:0" json:"-"`
+	// FailedTOTPAttempts counts wrong codes since the last accepted one; too
+	// many lock TOTP until TOTPLockedUntil.
+	FailedTOTPAttempts int        `gorm:"default:0" json:"-"`
+	TOTPLockedUntil    *time.Time `json:"totp_locked_until"`
+	// TOTPSetupTokenHash is the SHA-256 of the token setup returned with a
+	// pending secret; only that caller can confirm it before TOTPSetupExpiresAt.
+	TOTPSetupTokenHash []byte          `json:"-"`
+	TOTPSetupExpiresAt *time.Time      `json:"-"`
+	DailyWithdrawLimit decimal.Decimal `gorm:"type:decimal(36,18)" json:"daily_withdraw_limit"`
+	// PendingWithdrawLimit is a raised limit that replaces DailyWithdrawLimit
+	// once PendingWithdrawLimitAt has passed.
+	PendingWithdrawLimit   decimal.NullDecimal `gorm:"type:decimal(36,18)" json:"pending_withdraw_limit" swaggertype:"string"`
+	PendingWithdrawLimitAt *time.Time          `json:"pending_withdraw_limit_at"`
+	CreatedAt              time.Time           `gorm:"autoCreateTime" json:"created_at"`
+	UpdatedAt              time.Time           `gorm:"autoUpdateTime" json:"updated_at"`
+}
+
+// WithdrawLimitAt is the daily withdrawal limit in force at now.
+func (s *WalletSecurity) WithdrawLimitAt(now time.Time) decimal.Decimal {
+	if s.PendingWithdrawLimit.Valid && s.PendingWithdrawLimitAt != nil && !now.Before(*s.PendingWithdrawLimitAt) {
+		return s.PendingWithdrawLimit.Decimal
+	}
+	return s.DailyWithdrawLimit
+}
+
+func (WalletSecurity) TableName() string {
+	return "wallet_securities"
+}
@@ -0,0 +1,25 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestWithdrawLimitAtActivatesPendingLimit(t *testing.T) {
+	now := time.Date(2026, 1, 2, 12, 0, 0, 0, time.UTC)
+	activeAt := now.Add(time.Hour)
+	sec := &WalletSecurity{
+		DailyWithdrawLimit:     decimal.NewFromInt(1),
+		PendingWithdrawLimit:   decimal.NewNullDecimal(decimal.NewFromInt(5)),
+		PendingWithdrawLimitAt: &activeAt,
+	}
+
+	if got := sec.WithdrawLimitAt(now); !got.Equal(decimal.NewFromInt(1)) {
+		t.Fatalf("limit before activation = %s, want 1", got)
+	}
+	if got := sec.WithdrawLimitAt(activeAt); !got.Equal(decimal.NewFromInt(5)) {
+		t.Fatalf("limit at activation = %s, want 5", got)
+	}
+}
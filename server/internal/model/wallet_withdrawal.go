@@ -0,0 +1,23 @@
+package model
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+type WalletWithdrawal struct {
+	ID           string          `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID       string          `gorm:"index;not null" json:"user_id"`
+	WalletID     string          `gorm:"index;not null" json:"wallet_id"`
+	ToAddress    string          `gorm:"not null" json:"to_address"`
+	Amount       decimal.Decimal `gorm:"type:decimal(36,18)" json:"amount"`
+	TxHash       string          `gorm:"index" json:"tx_hash"`
+	Status       string          `json:"status"` // reserved | pending | success | failed
+	ErrorMessage string          `json:"error_message"`
+	CreatedAt    time.Time       `gorm:"autoCreateTime;index" json:"created_at"`
+}
+
+func (WalletWithdrawal) TableName() string {
+	return "wallet_withdrawals"
+}
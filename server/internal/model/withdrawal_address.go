@@ -0,0 +1,16 @@
+package model
+
+import "time"
+
+type WithdrawalAddress struct {
+	ID        string    `gorm:"type:uuid;primaryKey;default:gen_random_uuid()" json:"id"`
+	UserID    string    `gorm:"uniqueIndex:idx_withdrawal_user_address,priority:1;not null" json:"user_id"`
+	Address   string    `gorm:"uniqueIndex:idx_withdrawal_user_address,priority:2;not null" json:"address"`
+	Label     string    `json:"label"`
+	ActiveAt  time.Time `gorm:"not null" json:"active_at"` // withdrawals allowed once time-lock passes
+	CreatedAt time.Time `gorm:"autoCreateTime" json:"created_at"`
+}
+
+func (WithdrawalAddress) TableName() string {
+	return "withdrawal_addresses"
+}
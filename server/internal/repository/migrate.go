@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"fmt"
+	"log"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// moneyColumn is a money column that used to be stored as float or text.
+type moneyColumn struct {
+	table  string
+	column string
+	target string
+}
+
+var moneyColumns = []moneyColumn{
+	{"managed_wallets", "balance", "numeric(36,18)"},
+	{"managed_wallets", "max_balance", "numeric(36,18)"},
+	{"ai_trades", "amount_in", "numeric(36,18)"},
+	{"ai_trades", "amount_out", "numeric(36,18)"},
+	{"ai_trades", "current_value", "numeric(36,18)"},
+	{"ai_trades", "profit_loss", "numeric(36,18)"},
+	{"ai_trades", "gas_fee_bnb", "numeric(36,18)"},
+	{"ai_trades", "realized_pl_bnb", "numeric(36,18)"},
+	{"ai_trades", "realized_pl_usd", "numeric(36,18)"},
+	{"ai_trades", "gas_used", "bigint"},
+}
+
+// migrateMoneyColumns converts legacy float and text money columns in place so
+// AutoMigrate finds them already numeric. Text that is blank or not a plain
+// number, such as a failed trade's empty amount, becomes 0.
+func migrateMoneyColumns(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		for _, col := range moneyColumns {
+			var dataType string
+			err := tx.Raw(
+				"SELECT data_type FROM information_schema.columns WHERE table_schema = current_schema() AND table_name = ? AND column_name = ?",
+				col.table, col.column,
+			).Scan(&dataType).Error
+			if err != nil {
+				return err
+			}
+
+			var using string
+			switch dataType {
+			case "text", "character varying":
+				pattern := `^-?[0-9]+(\.[0-9]+)?$`
+				if col.target == "bigint" {
+					pattern = `^[0-9]+$`
+				}
+				using = fmt.Sprintf("CASE WHEN btrim(%[1]s) ~ '%[2]s' THEN btrim(%[1]s)::%[3]s ELSE 0 END", col.column, pattern, col.target)
+			case "double precision", "real":
+				using = fmt.Sprintf("COALESCE(%s, 0)::%s", col.column, col.target)
+			default:
+				// Missing, or already converted.
+				continue
+			}
+
+			stmts := []string{
+				fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s DROP DEFAULT", col.table, col.column),
+				fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s USING %s", col.table, col.column, col.target, using),
+			}
+			for _, stmt := range stmts {
+				if err := tx.Exec(stmt).Error; err != nil {
+					return fmt.Errorf("migrate %s.%s: %w", col.table, col.column, err)
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// backfillTradeUnits fills the token decimals and raw wei amounts of trades
+// recorded before they were stored. Tokens that were never indexed keep the
+// default of 18 decimals.
+func backfillTradeUnits(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		const pending = "amount_in_wei = 0 AND amount_out_wei = 0 AND (amount_in <> 0 OR amount_out <> 0)"
+		err := tx.Exec(`UPDATE ai_trades t SET token_decimals = k.decimals
+			FROM tokens k
+			WHERE LOWER(k.address) = LOWER(t.token_address) AND ` + pending).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE ai_trades SET
+			amount_in_wei = trunc(amount_in * power(10::numeric, CASE WHEN type = 'SELL' THEN token_decimals ELSE 18 END)),
+			amount_out_wei = trunc(amount_out * power(10::numeric, CASE WHEN type = 'BUY' THEN token_decimals ELSE 18 END))
+			WHERE ` + pending).Error
+	})
+}
+
+// backfillAnalysisSource marks analyses stored before the baseline scorer
+// existed as agent analyses, so the scorer never overwrites them.
+func backfillAnalysisSource(db *gorm.DB) error {
+	return db.Exec(
+		"UPDATE tokens SET analysis_source = ? WHERE analysis_status = ? AND analysis_source = ''",
+		"agent", "analyzed",
+	).Error
+}
+
+// backfillTokenAnalyses starts the history of every analyzed token with its
+// current verdict.
+func backfillTokenAnalyses(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec(`INSERT INTO token_analyses
+			(token_address, source, model, model_version, risk_score, risk_level, is_golden_dog, golden_dog_score, payload, created_at)
+			SELECT address, analysis_source,
+				CASE WHEN analysis_source = 'baseline' THEN 'baseline' ELSE '' END,
+				CASE WHEN analysis_source = 'baseline' THEN scoring_version::text ELSE '' END,
+				risk_score, risk_level, is_golden_dog, golden_dog_score,
+				COALESCE(analysis_result, '{}'), COALESCE(analyzed_at, updated_at)
+			FROM tokens
+			WHERE analysis_status = 'analyzed' AND current_analysis_id IS NULL`).Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec(`UPDATE tokens t SET current_analysis_id = a.id
+			FROM token_analyses a
+			WHERE a.token_address = t.address AND t.current_analysis_id IS NULL AND t.analysis_status = 'analyzed'`).Error
+	})
+}
+
+// backfillAnalysisAgents attributes agent analyses stored before agents had
+// identities to the default agent.
+func backfillAnalysisAgents(db *gorm.DB) error {
+	return db.Exec(
+		"UPDATE token_analyses SET agent_id = ? WHERE source = ? AND (agent_id IS NULL OR agent_id = '')",
+		"default", "agent",
+	).Error
+}
+
+// backfillHaltScopes prefixes per-user halts stored under the bare user ID,
+// which could collide with the global scope.
+func backfillHaltScopes(db *gorm.DB) error {
+	return db.Exec(
+		"UPDATE trading_halts SET scope = 'user:' || scope WHERE scope <> ? AND scope NOT LIKE 'user:%'",
+		"global",
+	).Error
+}
+
+// dedupeManagedWallets keeps each user's oldest wallet before user_id becomes
+// unique. Newer wallets hold keys and maybe funds, so they are moved to a
+// "<user>:duplicate:<id>" owner instead of deleted. The old non-unique index
+// shares the unique index's name and is dropped so AutoMigrate recreates it.
+func dedupeManagedWallets(db *gorm.DB) error {
+	if !db.Migrator().HasTable("managed_wallets") {
+		return nil
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Exec(`UPDATE managed_wallets w SET user_id = w.user_id || ':duplicate:' || w.id::text
+			WHERE EXISTS (
+				SELECT 1 FROM managed_wallets o
+				WHERE o.user_id = w.user_id AND (o.created_at, o.id) < (w.created_at, w.id)
+			)`)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected > 0 {
+			log.Printf("moved %d duplicate managed wallets to ':duplicate:' owners", res.RowsAffected)
+		}
+		var indexDef string
+		err := tx.Raw(
+			"SELECT indexdef FROM pg_indexes WHERE schemaname = current_schema() AND indexname = ?",
+			"idx_managed_wallets_user_id",
+		).Scan(&indexDef).Error
+		if err != nil || indexDef == "" || strings.HasPrefix(indexDef, "CREATE UNIQUE") {
+			return err
+		}
+		return tx.Exec("DROP INDEX idx_managed_wallets_user_id").Error
+	})
+}
+
+// backfillLedgerSimulated marks the lots and ledger entries of paper trades,
+// recognizable by their paper- transaction hashes.
+func backfillLedgerSimulated(db *gorm.DB) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE position_lots SET simulated = true WHERE buy_tx_hash LIKE 'paper-%' AND NOT simulated").Error
+		if err != nil {
+			return err
+		}
+		return tx.Exec("UPDATE realized_pnls SET simulated = true WHERE tx_hash LIKE 'paper-%' AND NOT simulated").Error
+	})
+}
@@ -2,12 +2,17 @@ package repository
 
 import (
 	"context"
+	"errors"
 	"os"
+	"strings"
 	"time"
 
 	"easymeme/internal/model"
+	"github.com/shopspring/decimal"
+	"gorm.io/datatypes"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"gorm.io/gorm/logger"
 )
 
@@ -24,6 +29,12 @@ func New(databaseURL string) (*Repository, error) {
 	}
 
 	if os.Getenv("AUTO_MIGRATE") == "true" {
+		if err := migrateMoneyColumns(db); err != nil {
+			return nil, err
+		}
+		if err := dedupeManagedWallets(db); err != nil {
+			return nil, err
+		}
 		db.AutoMigrate(
 			&model.Token{},
 			&model.Trade{},
@@ -34,7 +45,44 @@ func New(databaseURL string) (*Repository, error) {
 			&model.TokenMarketSnapshot{},
 			&model.TokenAlert{},
 			&model.TokenPriceSnapshot{},
+			&model.WalletSecurity{},
+			&model.WithdrawalAddress{},
+			&model.WalletWithdrawal{},
+			&model.WalletPolicy{},
+			&model.PolicyViolation{},
+			&model.TokenAllowance{},
+			&model.TradeOrder{},
+			&model.OrderLadder{},
+			&model.TradeApproval{},
+			&model.TradeApprovalEvent{},
+			&model.TradingHalt{},
+			&model.PortfolioSnapshot{},
+			&model.PositionLot{},
+			&model.RealizedPnL{},
+			&model.ScoringModel{},
+			&model.TokenAnalysis{},
+			&model.AnalysisAgent{},
+			&model.AgentSubscription{},
+			&model.TokenOutcome{},
 		)
+		if err := backfillTradeUnits(db); err != nil {
+			return nil, err
+		}
+		if err := backfillAnalysisSource(db); err != nil {
+			return nil, err
+		}
+		if err := backfillTokenAnalyses(db); err != nil {
+			return nil, err
+		}
+		if err := backfillAnalysisAgents(db); err != nil {
+			return nil, err
+		}
+		if err := backfillHaltScopes(db); err != nil {
+			return nil, err
+		}
+		if err := backfillLedgerSimulated(db); err != nil {
+			return nil, err
+		}
 	}
 
 	return &Repository{db: db}, nil
@@ -62,19 +110,130 @@ func (r *Repository) GetLatestTokens(ctx context.Context, limit int) ([]model.To
 	return tokens, err
 }
 
-func (r *Repository) GetPendingTokens(ctx context.Context, limit int, minLiquidity float64) ([]model.Token, error) {
-	var tokens []model.Token
-	query := r.db.WithContext(ctx).
-		Where("analysis_status = ?", "enriched").
-		Order("created_at DESC").
-		Limit(limit)
+// PendingQueue configures the analysis queue.
+type PendingQueue struct {
+	// AgingPerMinute is added to a token's priority for every minute it has
+	// waited since enrichment, up to MaxAging.
+	AgingPerMinute float64
+	MaxAging       float64
+	// Window is the token age after which it leaves the queue.
+	Window time.Duration
+}
+
+// pendingAnalysis filters tokens waiting for an agent analysis: enriched
+// tokens and tokens with only a baseline analysis, launched within the
+// queue window.
+func pendingAnalysis(db *gorm.DB, now time.Time, minLiquidity float64, queue PendingQueue) *gorm.DB {
+	db = db.Where("analysis_status = ? OR (analysis_status = ? AND analysis_source = ?)", "enriched", "analyzed", model.AnalysisSourceBaseline)
+	if queue.Window > 0 {
+		db = db.Where("created_at >= ?", now.Add(-queue.Window))
+	}
 	if minLiquidity > 0 {
-		query = query.Where("initial_liquidity >= ?", minLiquidity)
+		db = db.Where("initial_liquidity >= ?", minLiquidity)
 	}
-	err := query.Find(&tokens).Error
+	return db
+}
+
+// queuePriority orders pending tokens by their enrichment priority plus the
+// capped aging earned while waiting, highest first.
+func queuePriority(now time.Time, queue PendingQueue) clause.OrderBy {
+	return clause.OrderBy{Expression: clause.Expr{
+		SQL:                "analysis_priority + LEAST(? * GREATEST(EXTRACT(EPOCH FROM (? - COALESCE(enriched_at, created_at))) / 60, 0), ?) DESC, created_at DESC",
+		Vars:               []interface{}{queue.AgingPerMinute, now, queue.MaxAging},
+		WithoutParentheses: true,
+	}}
+}
+
+// GetPendingTokens returns tokens waiting for an agent analysis that no agent
+// holds a live lease on, in queue priority order.
+func (r *Repository) GetPendingTokens(ctx context.Context, limit int, minLiquidity float64, queue PendingQueue) ([]model.Token, error) {
+	var tokens []model.Token
+	now := time.Now().UTC()
+	err := pendingAnalysis(r.db.WithContext(ctx), now, minLiquidity, queue).
+		Where("lease_expires_at IS NULL OR lease_expires_at < ?", now).
+		Order(queuePriority(now, queue)).
+		Limit(limit).
+		Find(&tokens).Error
 	return tokens, err
 }
 
+// ClaimPendingTokens leases up to limit pending tokens, in queue priority
+// order, to agentID until ttl elapses. Tokens locked by a concurrent claim are
+// skipped, and tokens whose lease expired are claimable again.
+func (r *Repository) ClaimPendingTokens(ctx context.Context, agentID string, limit int, minLiquidity float64, queue PendingQueue, ttl time.Duration) ([]model.Token, error) {
+	var tokens []model.Token
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		now := time.Now().UTC()
+		err := pendingAnalysis(tx, now, minLiquidity, queue).
+			Where("lease_expires_at IS NULL OR lease_expires_at < ?", now).
+			Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Order(queuePriority(now, queue)).
+			Limit(limit).
+			Find(&tokens).Error
+		if err != nil || len(tokens) == 0 {
+			return err
+		}
+		ids := make([]string, 0, len(tokens))
+		for _, token := range tokens {
+			ids = append(ids, token.ID)
+		}
+		expiresAt := now.Add(ttl)
+		err = tx.Model(&model.Token{}).
+			Where("id IN ?", ids).
+			Updates(map[string]interface{}{"lease_owner": agentID, "lease_expires_at": expiresAt}).Error
+		if err != nil {
+			return err
+		}
+		for i := range tokens {
+			tokens[i].LeaseOwner = agentID
+			tokens[i].LeaseExpiresAt = &expiresAt
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return tokens, nil
+}
+
+// RenewTokenLeases extends the leases agentID still holds and returns the
+// addresses renewed. A lease that expired stays renewable until another agent
+// claims the token.
+func (r *Repository) RenewTokenLeases(ctx context.Context, agentID string, addresses []string, ttl time.Duration) ([]string, time.Time, error) {
+	expiresAt := time.Now().UTC().Add(ttl)
+	var tokens []model.Token
+	err := r.db.WithContext(ctx).
+		Model(&tokens).
+		Clauses(clause.Returning{Columns: []clause.Column{{Name: "address"}}}).
+		Where("lease_owner = ? AND LOWER(address) IN ?", agentID, lowerAll(addresses)).
+		Update("lease_expires_at", expiresAt).Error
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	renewed := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		renewed = append(renewed, token.Address)
+	}
+	return renewed, expiresAt, nil
+}
+
+// ReleaseTokenLeases returns the tokens agentID holds to the queue.
+func (r *Repository) ReleaseTokenLeases(ctx context.Context, agentID string, addresses []string) (int64, error) {
+	result := r.db.WithContext(ctx).
+		Model(&model.Token{}).
+		Where("lease_owner = ? AND LOWER(address) IN ?", agentID, lowerAll(addresses)).
+		Updates(map[string]interface{}{"lease_owner": "", "lease_expires_at": nil})
+	return result.RowsAffected, result.Error
+}
+
+func lowerAll(values []string) []string {
+	lowered := make([]string, 0, len(values))
+	for _, v := range values {
+		lowered = append(lowered, strings.ToLower(strings.TrimSpace(v)))
+	}
+	return lowered
+}
+
 func (r *Repository) GetAnalyzedTokens(ctx context.Context, limit int) ([]model.Token, error) {
 	var tokens []model.Token
 	err := r.db.WithContext(ctx).
@@ -96,6 +255,58 @@ func (r *Repository) GetGoldenDogTokens(ctx context.Context, limit int) ([]model
 	return tokens, err
 }
 
+// GoldenDogSignal is the first verdict that called a token a golden dog,
+// with the token it was about.
+type GoldenDogSignal struct {
+	Token    model.Token
+	Analysis model.TokenAnalysis
+}
+
+// ListGoldenDogSignals returns, oldest first, the first golden dog verdict
+// on each token stored between from and to. A baseline verdict counts only
+// while no agent had analyzed the token, since an agent analysis replaces it.
+func (r *Repository) ListGoldenDogSignals(ctx context.Context, from, to time.Time, limit int) ([]GoldenDogSignal, error) {
+	var analyses []model.TokenAnalysis
+	q := r.db.WithContext(ctx).
+		Table("(?) AS firsts", r.db.Model(&model.TokenAnalysis{}).
+			Select("DISTINCT ON (token_address) *").
+			Where("is_golden_dog = ? AND created_at >= ? AND created_at <= ?", true, from, to).
+			Where("source = ? OR NOT EXISTS (?)", model.AnalysisSourceAgent,
+				r.db.Table("token_analyses AS earlier").Select("1").
+					Where("earlier.token_address = token_analyses.token_address AND earlier.source = ? AND earlier.created_at < token_analyses.created_at", model.AnalysisSourceAgent)).
+			Order("token_address, created_at ASC")).
+		Order("created_at ASC")
+	if limit > 0 {
+		q = q.Limit(limit)
+	}
+	if err := q.Find(&analyses).Error; err != nil {
+		return nil, err
+	}
+	if len(analyses) == 0 {
+		return nil, nil
+	}
+
+	addresses := make([]string, 0, len(analyses))
+	for _, a := range analyses {
+		addresses = append(addresses, a.TokenAddress)
+	}
+	var tokens []model.Token
+	if err := r.db.WithContext(ctx).Where("address IN ?", addresses).Find(&tokens).Error; err != nil {
+		return nil, err
+	}
+	byAddress := make(map[string]model.Token, len(tokens))
+	for _, t := range tokens {
+		byAddress[t.Address] = t
+	}
+	signals := make([]GoldenDogSignal, 0, len(analyses))
+	for _, a := range analyses {
+		if token, ok := byAddress[a.TokenAddress]; ok {
+			signals = append(signals, GoldenDogSignal{Token: token, Analysis: a})
+		}
+	}
+	return signals, nil
+}
+
 func (r *Repository) UpdateToken(ctx context.Context, token *model.Token) error {
 	return r.db.WithContext(ctx).Save(token).Error
 }
@@ -107,6 +318,119 @@ func (r *Repository) UpdateTokenAnalysis(ctx context.Context, address string, up
 		Updates(updates).Error
 }
 
+var errAnalysisNotApplied = errors.New("token not updated")
+
+// RecordTokenAnalysis stores the analysis and makes it the token's current
+// one with the given token updates. Nothing is stored when the token does not
+// exist, or for a baseline analysis when an agent analysis is already stored;
+// the result reports whether the analysis was kept.
+func (r *Repository) RecordTokenAnalysis(ctx context.Context, analysis *model.TokenAnalysis, updates map[string]interface{}) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(analysis).Error; err != nil {
+			return err
+		}
+		updates["current_analysis_id"] = analysis.ID
+		if analysis.Source == model.AnalysisSourceAgent {
+			releaseLeaseOf(updates, analysis.AgentID)
+		}
+		query := tx.Model(&model.Token{}).Where("address = ?", analysis.TokenAddress)
+		if analysis.Source == model.AnalysisSourceBaseline {
+			query = query.Where("analysis_source <> ?", model.AnalysisSourceAgent)
+		}
+		result := query.Updates(updates)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return errAnalysisNotApplied
+		}
+		return nil
+	})
+	if errors.Is(err, errAnalysisNotApplied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// releaseLeaseOf adds updates ending the token's lease when agentID holds it.
+// A lease held by another agent is left alone.
+func releaseLeaseOf(updates map[string]interface{}, agentID string) {
+	updates["lease_expires_at"] = gorm.Expr("CASE WHEN lease_owner = ? THEN NULL ELSE lease_expires_at END", agentID)
+	updates["lease_owner"] = gorm.Expr("CASE WHEN lease_owner = ? THEN '' ELSE lease_owner END", agentID)
+}
+
+// RecordAgentAnalysis stores an agent analysis and updates the token from
+// every agent's latest analysis, built by merge. The token row stays locked
+// in between so concurrent submissions cannot drop each other's verdicts.
+// It reports false when the token does not exist.
+func (r *Repository) RecordAgentAnalysis(ctx context.Context, analysis *model.TokenAnalysis, merge func(latest []model.TokenAnalysis) (map[string]interface{}, error)) (bool, error) {
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var token model.Token
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Select("id", "address").
+			Where("address = ?", analysis.TokenAddress).
+			First(&token).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return errAnalysisNotApplied
+		}
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(analysis).Error; err != nil {
+			return err
+		}
+
+		var latest []model.TokenAnalysis
+		err = tx.Raw(`SELECT DISTINCT ON (agent_id) * FROM token_analyses
+			WHERE token_address = ? AND source = ?
+			ORDER BY agent_id, created_at DESC`, analysis.TokenAddress, model.AnalysisSourceAgent).
+			Scan(&latest).Error
+		if err != nil {
+			return err
+		}
+		updates, err := merge(latest)
+		if err != nil {
+			return err
+		}
+		updates["current_analysis_id"] = analysis.ID
+		releaseLeaseOf(updates, analysis.AgentID)
+		return tx.Model(&model.Token{}).Where("id = ?", token.ID).Updates(updates).Error
+	})
+	if errors.Is(err, errAnalysisNotApplied) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// ListTokenAnalyses returns the analyses of a token, newest first.
+func (r *Repository) ListTokenAnalyses(ctx context.Context, address, source string, limit int) ([]model.TokenAnalysis, error) {
+	var analyses []model.TokenAnalysis
+	query := r.db.WithContext(ctx).Where("LOWER(token_address) = LOWER(?)", address)
+	if source != "" {
+		query = query.Where("source = ?", source)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&analyses).Error; err != nil {
+		return nil, err
+	}
+	return analyses, nil
+}
+
+// CountCreatorHoneypots counts the other tokens by creator flagged as
+// honeypots.
+func (r *Repository) CountCreatorHoneypots(ctx context.Context, creator, excludeAddress string) (int64, error) {
+	var count int64
+	err := r.db.WithContext(ctx).
+		Model(&model.Token{}).
+		Where("LOWER(creator_address) = LOWER(?)", creator).
+		Where("address <> ?", excludeAddress).
+		Where("is_honeypot = ?", true).
+		Count(&count).Error
+	return count, err
+}
+
 func (r *Repository) GetTokensByStatus(ctx context.Context, status string, limit int) ([]model.Token, error) {
 	var tokens []model.Token
 	err := r.db.WithContext(ctx).
@@ -185,8 +509,20 @@ func (r *Repository) UpdateTradeStatus(ctx context.Context, txHash, status strin
 		Update("status", status).Error
 }
 
+// ErrWalletExists is returned when a user already has a managed wallet.
+var ErrWalletExists = errors.New("wallet already exists")
+
 func (r *Repository) CreateManagedWallet(ctx context.Context, wallet *model.ManagedWallet) error {
-	return r.db.WithContext(ctx).Create(wallet).Error
+	res := r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "user_id"}}, DoNothing: true}).
+		Create(wallet)
+	if res.Error != nil {
+		return res.Error
+	}
+	if res.RowsAffected == 0 {
+		return ErrWalletExists
+	}
+	return nil
 }
 
 func (r *Repository) GetManagedWalletByUser(ctx context.Context, userID string) (*model.ManagedWallet, error) {
@@ -198,13 +534,166 @@ func (r *Repository) GetManagedWalletByUser(ctx context.Context, userID string)
 	return &wallet, nil
 }
 
-func (r *Repository) UpdateManagedWalletBalance(ctx context.Context, walletID string, balance float64) error {
+func (r *Repository) ListManagedWallets(ctx context.Context) ([]model.ManagedWallet, error) {
+	var wallets []model.ManagedWallet
+	if err := r.db.WithContext(ctx).Order("created_at ASC").Find(&wallets).Error; err != nil {
+		return nil, err
+	}
+	return wallets, nil
+}
+
+// AdjustManagedWalletBalance adds delta to a paper wallet's virtual balance.
+// It reports false when the balance would go negative.
+func (r *Repository) AdjustManagedWalletBalance(ctx context.Context, walletID string, delta decimal.Decimal) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.ManagedWallet{}).
+		Where("id = ?", walletID).
+		Where("balance + ? >= 0", delta).
+		Update("balance", gorm.Expr("balance + ?", delta))
+	return res.RowsAffected > 0, res.Error
+}
+
+func (r *Repository) UpdateManagedWalletBalance(ctx context.Context, walletID string, balance decimal.Decimal) error {
 	return r.db.WithContext(ctx).
 		Model(&model.ManagedWallet{}).
 		Where("id = ?", walletID).
 		Update("balance", balance).Error
 }
 
+func (r *Repository) GetWalletSecurity(ctx context.Context, userID string) (*model.WalletSecurity, error) {
+	var sec model.WalletSecurity
+	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&sec).Error
+	if err != nil {
+		return nil, err
+	}
+	return &sec, nil
+}
+
+func (r *Repository) CreateWalletSecurity(ctx context.Context, sec *model.WalletSecurity) error {
+	return r.db.WithContext(ctx).Create(sec).Error
+}
+
+func (r *Repository) UpdateWalletSecurity(ctx context.Context, userID string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WalletSecurity{}).
+		Where("user_id = ?", userID).
+		Updates(updates).Error
+}
+
+// ConsumeTOTPStep records step as used and reports false when it was not newer
+// than the last accepted step, so a code cannot be replayed.
+func (r *Repository) ConsumeTOTPStep(ctx context.Context, userID string, step int64) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.WalletSecurity{}).
+		Where("user_id = ?", userID).
+		Where("last_totp_step < ?", step).
+		Update("last_totp_step", step)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// RecordTOTPFailure counts a wrong TOTP code and, on the maxAttempts-th one
+// in a row, locks TOTP until lockedUntil.
+func (r *Repository) RecordTOTPFailure(ctx context.Context, userID string, maxAttempts int, lockedUntil time.Time) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WalletSecurity{}).
+		Where("user_id = ?", userID).
+		Updates(map[string]interface{}{
+			"failed_totp_attempts": gorm.Expr("CASE WHEN failed_totp_attempts + 1 >= ? THEN 0 ELSE failed_totp_attempts + 1 END", maxAttempts),
+			"totp_locked_until":    gorm.Expr("CASE WHEN failed_totp_attempts + 1 >= ? THEN ? ELSE totp_locked_until END", maxAttempts, lockedUntil),
+		}).Error
+}
+
+func (r *Repository) ListWithdrawalAddresses(ctx context.Context, userID string) ([]model.WithdrawalAddress, error) {
+	var addresses []model.WithdrawalAddress
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Find(&addresses).Error
+	return addresses, err
+}
+
+func (r *Repository) GetWithdrawalAddress(ctx context.Context, userID, address string) (*model.WithdrawalAddress, error) {
+	var entry model.WithdrawalAddress
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("LOWER(address) = LOWER(?)", address).
+		First(&entry).Error
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (r *Repository) CreateWithdrawalAddress(ctx context.Context, entry *model.WithdrawalAddress) error {
+	return r.db.WithContext(ctx).Create(entry).Error
+}
+
+func (r *Repository) DeleteWithdrawalAddress(ctx context.Context, userID, address string) (bool, error) {
+	res := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("LOWER(address) = LOWER(?)", address).
+		Delete(&model.WithdrawalAddress{})
+	return res.RowsAffected > 0, res.Error
+}
+
+// ReserveWalletWithdrawal stores w unless it would take the user's
+// withdrawals over the last 24 hours past their daily limit, and reports
+// whether it did. The user's security row stays locked in between, so
+// concurrent withdrawals and limit changes are checked against each other.
+func (r *Repository) ReserveWalletWithdrawal(ctx context.Context, w *model.WalletWithdrawal) (bool, error) {
+	reserved := false
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var sec model.WalletSecurity
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", w.UserID).
+			First(&sec).Error
+		if err != nil {
+			return err
+		}
+		used, err := sumWalletWithdrawalsSince(tx, w.UserID, time.Now().Add(-24*time.Hour))
+		if err != nil {
+			return err
+		}
+		if used.Add(w.Amount).GreaterThan(sec.WithdrawLimitAt(time.Now())) {
+			return nil
+		}
+		if err := tx.Create(w).Error; err != nil {
+			return err
+		}
+		reserved = true
+		return nil
+	})
+	return reserved, err
+}
+
+func (r *Repository) UpdateWalletWithdrawal(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&model.WalletWithdrawal{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func sumWalletWithdrawalsSince(db *gorm.DB, userID string, since time.Time) (decimal.Decimal, error) {
+	var total decimal.NullDecimal
+	err := db.
+		Model(&model.WalletWithdrawal{}).
+		Select("SUM(amount)").
+		Where("user_id = ?", userID).
+		Where("status <> ?", "failed").
+		Where("created_at >= ?", since).
+		Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !total.Valid {
+		return decimal.Zero, nil
+	}
+	return total.Decimal, nil
+}
+
 func (r *Repository) UpsertWalletConfig(ctx context.Context, userID string, configJSON []byte) error {
 	var existing model.WalletConfig
 	err := r.db.WithContext(ctx).Where("user_id = ?", userID).First(&existing).Error
@@ -229,26 +718,125 @@ func (r *Repository) GetWalletConfig(ctx context.Context, userID string) (*model
 	return &cfg, nil
 }
 
-func (r *Repository) GetAITrades(ctx context.Context, limit int) ([]model.AITrade, error) {
+func (r *Repository) ListWalletConfigs(ctx context.Context) ([]model.WalletConfig, error) {
+	var configs []model.WalletConfig
+	if err := r.db.WithContext(ctx).Find(&configs).Error; err != nil {
+		return nil, err
+	}
+	return configs, nil
+}
+
+func (r *Repository) GetWalletPolicy(ctx context.Context, walletID string) (*model.WalletPolicy, error) {
+	var policy model.WalletPolicy
+	err := r.db.WithContext(ctx).Where("wallet_id = ?", walletID).First(&policy).Error
+	if err != nil {
+		return nil, err
+	}
+	return &policy, nil
+}
+
+func (r *Repository) UpsertWalletPolicy(ctx context.Context, walletID, userID string, policyJSON []byte) error {
+	var existing model.WalletPolicy
+	err := r.db.WithContext(ctx).Where("wallet_id = ?", walletID).First(&existing).Error
+	if err == nil {
+		return r.db.WithContext(ctx).
+			Model(&model.WalletPolicy{}).
+			Where("wallet_id = ?", walletID).
+			Update("policy", policyJSON).Error
+	}
+	return r.db.WithContext(ctx).Create(&model.WalletPolicy{
+		WalletID: walletID,
+		UserID:   userID,
+		Policy:   policyJSON,
+	}).Error
+}
+
+func (r *Repository) CreatePolicyViolation(ctx context.Context, v *model.PolicyViolation) error {
+	return r.db.WithContext(ctx).Create(v).Error
+}
+
+func (r *Repository) ListPolicyViolations(ctx context.Context, userID string, limit int) ([]model.PolicyViolation, error) {
+	var rows []model.PolicyViolation
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Limit(limit).
+		Find(&rows).Error
+	return rows, err
+}
+
+func (r *Repository) UpsertTokenAllowance(ctx context.Context, allowance *model.TokenAllowance) error {
+	if allowance == nil {
+		return nil
+	}
+	var existing model.TokenAllowance
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ?", allowance.WalletID).
+		Where("token_address = ?", allowance.TokenAddress).
+		Where("spender = ?", allowance.Spender).
+		First(&existing).Error
+	if err == nil {
+		updates := map[string]interface{}{
+			"amount":          allowance.Amount,
+			"last_checked_at": allowance.LastCheckedAt,
+		}
+		if allowance.LastTxHash != "" {
+			updates["last_tx_hash"] = allowance.LastTxHash
+		}
+		return r.db.WithContext(ctx).
+			Model(&model.TokenAllowance{}).
+			Where("id = ?", existing.ID).
+			Updates(updates).Error
+	}
+	return r.db.WithContext(ctx).Create(allowance).Error
+}
+
+func (r *Repository) ListTokenAllowances(ctx context.Context, walletID string) ([]model.TokenAllowance, error) {
+	var rows []model.TokenAllowance
+	err := r.db.WithContext(ctx).
+		Where("wallet_id = ?", walletID).
+		Order("updated_at DESC").
+		Find(&rows).Error
+	return rows, err
+}
+
+// walletMode limits queries on tables with a simulated column to live or
+// paper records; any other mode includes both.
+func walletMode(mode string) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		switch mode {
+		case model.WalletModeLive:
+			return db.Where("simulated = ?", false)
+		case model.WalletModePaper:
+			return db.Where("simulated = ?", true)
+		}
+		return db
+	}
+}
+
+func (r *Repository) GetAITrades(ctx context.Context, limit int, mode string) ([]model.AITrade, error) {
 	var trades []model.AITrade
 	err := r.db.WithContext(ctx).
+		Scopes(walletMode(mode)).
 		Order("timestamp DESC").
 		Limit(limit).
 		Find(&trades).Error
 	return trades, err
 }
 
-func (r *Repository) GetAllAITrades(ctx context.Context) ([]model.AITrade, error) {
+func (r *Repository) GetAllAITrades(ctx context.Context, mode string) ([]model.AITrade, error) {
 	var trades []model.AITrade
 	err := r.db.WithContext(ctx).
+		Scopes(walletMode(mode)).
 		Order("timestamp DESC").
 		Find(&trades).Error
 	return trades, err
 }
 
-func (r *Repository) GetAITradesByUserSince(ctx context.Context, userID string, since time.Time) ([]model.AITrade, error) {
+func (r *Repository) GetAITradesByUserSince(ctx context.Context, userID string, since time.Time, mode string) ([]model.AITrade, error) {
 	var trades []model.AITrade
 	err := r.db.WithContext(ctx).
+		Scopes(walletMode(mode)).
 		Where("user_id = ?", userID).
 		Where("timestamp >= ?", since).
 		Order("timestamp DESC").
@@ -270,10 +858,80 @@ func (r *Repository) GetLatestAITradeByUserTokenType(ctx context.Context, userID
 	return &trade, nil
 }
 
+// HasAITradeWithStrategy reports whether a trade with the strategy succeeded
+// or may still succeed, or failed maxFailures times. Fewer failures leave the
+// token open to another attempt.
+func (r *Repository) HasAITradeWithStrategy(ctx context.Context, userID, tokenAddress, strategy string, maxFailures int) (bool, error) {
+	var counts struct {
+		Live   int64
+		Failed int64
+	}
+	err := r.db.WithContext(ctx).
+		Model(&model.AITrade{}).
+		Select("COUNT(*) FILTER (WHERE status IN ?) AS live, COUNT(*) FILTER (WHERE status = ?) AS failed", []string{"success", "pending"}, "failed").
+		Where("user_id = ?", userID).
+		Where("LOWER(token_address) = LOWER(?)", tokenAddress).
+		Where("strategy_used = ?", strategy).
+		Scan(&counts).Error
+	return counts.Live > 0 || counts.Failed >= int64(maxFailures), err
+}
+
+// GetAITradeByOrder returns the most recent trade placed by a resting order.
+func (r *Repository) GetAITradeByOrder(ctx context.Context, orderID string) (*model.AITrade, error) {
+	var trade model.AITrade
+	err := r.db.WithContext(ctx).
+		Where("order_id = ?", orderID).
+		Order("timestamp DESC").
+		First(&trade).Error
+	if err != nil {
+		return nil, err
+	}
+	return &trade, nil
+}
+
 func (r *Repository) CreateAITrade(ctx context.Context, trade *model.AITrade) error {
 	return r.db.WithContext(ctx).Create(trade).Error
 }
 
+// ListPendingAITrades returns on-chain trades still waiting for a receipt
+// that were recorded before cutoff, oldest first.
+func (r *Repository) ListPendingAITrades(ctx context.Context, cutoff time.Time, limit int) ([]model.AITrade, error) {
+	var trades []model.AITrade
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "pending").
+		Where("simulated = ?", false).
+		Where("timestamp < ?", cutoff).
+		Order("timestamp ASC").
+		Limit(limit).
+		Find(&trades).Error
+	if err != nil {
+		return nil, err
+	}
+	return trades, nil
+}
+
+// SettleAITrade moves a pending trade to its final status and reports whether
+// this caller won the transition.
+func (r *Repository) SettleAITrade(ctx context.Context, id, status string, updates map[string]interface{}) (bool, error) {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = status
+	res := r.db.WithContext(ctx).
+		Model(&model.AITrade{}).
+		Where("id = ?", id).
+		Where("status = ?", "pending").
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+func (r *Repository) UpdateAITrade(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).Model(&model.AITrade{}).Where("id = ?", id).Updates(updates).Error
+}
+
 func (r *Repository) GetAIPosition(ctx context.Context, userID, tokenAddress string) (*model.AIPosition, error) {
 	var pos model.AIPosition
 	err := r.db.WithContext(ctx).
@@ -300,9 +958,13 @@ func (r *Repository) UpsertAIPosition(ctx context.Context, pos *model.AIPosition
 			Model(&model.AIPosition{}).
 			Where("id = ?", existing.ID).
 			Updates(map[string]interface{}{
-				"quantity":     pos.Quantity,
-				"cost_bnb":     pos.CostBNB,
-				"token_symbol": pos.TokenSymbol,
+				"quantity":         pos.Quantity,
+				"cost_bnb":         pos.CostBNB,
+				"token_symbol":     pos.TokenSymbol,
+				"take_profit_hits": pos.TakeProfitHits,
+				"opened_at":        pos.OpenedAt,
+				"peak_price_bnb":   pos.PeakPriceBNB,
+				"peak_at":          pos.PeakAt,
 			}).Error
 	}
 	return r.db.WithContext(ctx).Create(pos).Error
@@ -320,25 +982,44 @@ func (r *Repository) ListAIPositionsByUser(ctx context.Context, userID string) (
 	return positions, nil
 }
 
-func (r *Repository) GetAITradeStats(ctx context.Context) (count int64, winRate float64, avgPL float64, err error) {
+func (r *Repository) ListOpenAIPositions(ctx context.Context) ([]model.AIPosition, error) {
+	var positions []model.AIPosition
+	err := r.db.WithContext(ctx).
+		Where("quantity > 0").
+		Order("updated_at ASC").
+		Find(&positions).Error
+	if err != nil {
+		return nil, err
+	}
+	return positions, nil
+}
+
+func (r *Repository) UpdateAIPositionMark(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&model.AIPosition{}).
+		Where("id = ?", id).
+		UpdateColumns(updates).Error
+}
+
+func (r *Repository) GetAITradeStats(ctx context.Context, mode string) (count int64, winRate float64, avgPL float64, err error) {
 	var trades []model.AITrade
-	if err = r.db.WithContext(ctx).Find(&trades).Error; err != nil {
+	if err = r.db.WithContext(ctx).Scopes(walletMode(mode)).Find(&trades).Error; err != nil {
 		return 0, 0, 0, err
 	}
 	if len(trades) == 0 {
 		return 0, 0, 0, nil
 	}
 	var wins int
-	var totalPL float64
+	totalPL := decimal.Zero
 	for _, t := range trades {
-		if t.ProfitLoss > 0 {
+		if t.ProfitLoss.IsPositive() {
 			wins++
 		}
-		totalPL += t.ProfitLoss
+		totalPL = totalPL.Add(t.ProfitLoss)
 	}
 	count = int64(len(trades))
 	winRate = float64(wins) / float64(len(trades))
-	avgPL = totalPL / float64(len(trades))
+	avgPL = totalPL.Div(decimal.NewFromInt(count)).InexactFloat64()
 	return count, winRate, avgPL, nil
 }
 
@@ -425,3 +1106,790 @@ func (r *Repository) GetTokenPriceSeries(ctx context.Context, tokenAddress strin
 	}
 	return rows, nil
 }
+
+func (r *Repository) CreateTradeOrder(ctx context.Context, order *model.TradeOrder) error {
+	return r.db.WithContext(ctx).Create(order).Error
+}
+
+func (r *Repository) GetTradeOrder(ctx context.Context, id string) (*model.TradeOrder, error) {
+	var order model.TradeOrder
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&order).Error; err != nil {
+		return nil, err
+	}
+	return &order, nil
+}
+
+func (r *Repository) ListTradeOrders(ctx context.Context, userID, status string, limit int) ([]model.TradeOrder, error) {
+	var orders []model.TradeOrder
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&orders).Error; err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *Repository) ListOpenTradeOrders(ctx context.Context, limit int) ([]model.TradeOrder, error) {
+	var orders []model.TradeOrder
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "open").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// ListStaleTradeOrders returns orders still executing that were triggered
+// before cutoff.
+func (r *Repository) ListStaleTradeOrders(ctx context.Context, cutoff time.Time, limit int) ([]model.TradeOrder, error) {
+	var orders []model.TradeOrder
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "executing").
+		Where("triggered_at IS NULL OR triggered_at < ?", cutoff).
+		Order("triggered_at ASC").
+		Limit(limit).
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+// TransitionTradeOrder moves an order from one status to another and reports
+// whether this caller won the transition.
+func (r *Repository) TransitionTradeOrder(ctx context.Context, id, from, to string, updates map[string]interface{}) (bool, error) {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = to
+	res := r.db.WithContext(ctx).
+		Model(&model.TradeOrder{}).
+		Where("id = ?", id).
+		Where("status = ?", from).
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ExpireTradeOrders expires resting orders past their expiry and orders
+// awaiting an approval that expired undecided.
+func (r *Repository) ExpireTradeOrders(ctx context.Context, now time.Time) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.TradeOrder{}).
+		Where("status IN ?", []string{"open", "waiting"}).
+		Where("expires_at IS NOT NULL AND expires_at <= ?", now).
+		Update("status", "expired")
+	if res.Error != nil {
+		return 0, res.Error
+	}
+	awaiting := r.db.WithContext(ctx).
+		Model(&model.TradeOrder{}).
+		Where("status = ?", "awaiting_approval").
+		Where("approval_id IN (?)", r.db.Model(&model.TradeApproval{}).
+			Select("id").
+			Where("status IN ?", []string{"pending", "expired"}).
+			Where("expires_at <= ?", now)).
+		Updates(map[string]interface{}{"status": "expired", "error_message": "approval expired"})
+	return res.RowsAffected + awaiting.RowsAffected, awaiting.Error
+}
+
+// SettleApprovalOrders moves the orders awaiting approvalID to status.
+func (r *Repository) SettleApprovalOrders(ctx context.Context, approvalID, status string, updates map[string]interface{}) (int64, error) {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = status
+	res := r.db.WithContext(ctx).
+		Model(&model.TradeOrder{}).
+		Where("approval_id = ?", approvalID).
+		Where("status = ?", "awaiting_approval").
+		Updates(updates)
+	return res.RowsAffected, res.Error
+}
+
+// CountApprovalOrders counts the orders still awaiting an approval.
+func (r *Repository) CountApprovalOrders(ctx context.Context, approvalID string) (int64, error) {
+	var n int64
+	err := r.db.WithContext(ctx).
+		Model(&model.TradeOrder{}).
+		Where("approval_id = ?", approvalID).
+		Where("status = ?", "awaiting_approval").
+		Count(&n).Error
+	return n, err
+}
+
+// CreateOrderLadder stores the ladder and its tranches atomically.
+func (r *Repository) CreateOrderLadder(ctx context.Context, ladder *model.OrderLadder, tranches []model.TradeOrder) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ladder).Error; err != nil {
+			return err
+		}
+		for i := range tranches {
+			tranches[i].ParentID = ladder.ID
+		}
+		if len(tranches) == 0 {
+			return nil
+		}
+		return tx.Create(&tranches).Error
+	})
+}
+
+func (r *Repository) GetOrderLadder(ctx context.Context, id string) (*model.OrderLadder, error) {
+	var ladder model.OrderLadder
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&ladder).Error; err != nil {
+		return nil, err
+	}
+	return &ladder, nil
+}
+
+func (r *Repository) ListOrderLadders(ctx context.Context, userID string, limit int) ([]model.OrderLadder, error) {
+	var ladders []model.OrderLadder
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&ladders).Error; err != nil {
+		return nil, err
+	}
+	return ladders, nil
+}
+
+func (r *Repository) ListOpenOrderLadders(ctx context.Context, limit int) ([]model.OrderLadder, error) {
+	var ladders []model.OrderLadder
+	err := r.db.WithContext(ctx).
+		Where("status = ?", "open").
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&ladders).Error
+	if err != nil {
+		return nil, err
+	}
+	return ladders, nil
+}
+
+func (r *Repository) ListLadderTranches(ctx context.Context, ladderID string) ([]model.TradeOrder, error) {
+	var orders []model.TradeOrder
+	err := r.db.WithContext(ctx).
+		Where("parent_id = ?", ladderID).
+		Order("tranche_index ASC").
+		Find(&orders).Error
+	if err != nil {
+		return nil, err
+	}
+	return orders, nil
+}
+
+func (r *Repository) UpdateOrderLadder(ctx context.Context, id string, updates map[string]interface{}) error {
+	return r.db.WithContext(ctx).
+		Model(&model.OrderLadder{}).
+		Where("id = ?", id).
+		Updates(updates).Error
+}
+
+func (r *Repository) CancelLadderTranches(ctx context.Context, ladderID string) (int64, error) {
+	res := r.db.WithContext(ctx).
+		Model(&model.TradeOrder{}).
+		Where("parent_id = ?", ladderID).
+		Where("status IN ?", []string{"open", "waiting"}).
+		Update("status", "cancelled")
+	return res.RowsAffected, res.Error
+}
+
+func (r *Repository) CreateTradeApproval(ctx context.Context, approval *model.TradeApproval) error {
+	return r.db.WithContext(ctx).Create(approval).Error
+}
+
+func (r *Repository) GetTradeApproval(ctx context.Context, id string) (*model.TradeApproval, error) {
+	var approval model.TradeApproval
+	if err := r.db.WithContext(ctx).Where("id = ?", id).First(&approval).Error; err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *Repository) GetPendingTradeApproval(ctx context.Context, userID, tokenAddress, tradeType string) (*model.TradeApproval, error) {
+	var approval model.TradeApproval
+	err := r.db.WithContext(ctx).
+		Where("user_id = ?", userID).
+		Where("LOWER(token_address) = LOWER(?)", tokenAddress).
+		Where("type = ?", tradeType).
+		Where("status = ?", "pending").
+		Where("expires_at > ?", time.Now().UTC()).
+		Order("created_at DESC").
+		First(&approval).Error
+	if err != nil {
+		return nil, err
+	}
+	return &approval, nil
+}
+
+func (r *Repository) ListTradeApprovals(ctx context.Context, userID, status string, limit int) ([]model.TradeApproval, error) {
+	var approvals []model.TradeApproval
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&approvals).Error; err != nil {
+		return nil, err
+	}
+	return approvals, nil
+}
+
+// TransitionTradeApproval moves an approval between statuses and reports
+// whether this caller won the transition.
+func (r *Repository) TransitionTradeApproval(ctx context.Context, id, from, to string, updates map[string]interface{}) (bool, error) {
+	if updates == nil {
+		updates = map[string]interface{}{}
+	}
+	updates["status"] = to
+	res := r.db.WithContext(ctx).
+		Model(&model.TradeApproval{}).
+		Where("id = ?", id).
+		Where("status = ?", from).
+		Updates(updates)
+	if res.Error != nil {
+		return false, res.Error
+	}
+	return res.RowsAffected > 0, nil
+}
+
+// ExpireTradeApprovals marks overdue pending approvals of userID, or of every
+// user when userID is empty, expired and returns the ones this call expired.
+func (r *Repository) ExpireTradeApprovals(ctx context.Context, userID string, now time.Time) ([]model.TradeApproval, error) {
+	var expired []model.TradeApproval
+	q := r.db.WithContext(ctx).
+		Model(&expired).
+		Clauses(clause.Returning{}).
+		Where("status = ?", "pending").
+		Where("expires_at <= ?", now)
+	if userID != "" {
+		q = q.Where("user_id = ?", userID)
+	}
+	if err := q.Update("status", "expired").Error; err != nil {
+		return nil, err
+	}
+	return expired, nil
+}
+
+func (r *Repository) CreateTradeApprovalEvent(ctx context.Context, event *model.TradeApprovalEvent) error {
+	return r.db.WithContext(ctx).Create(event).Error
+}
+
+func (r *Repository) ListTradeApprovalEvents(ctx context.Context, approvalID string) ([]model.TradeApprovalEvent, error) {
+	var events []model.TradeApprovalEvent
+	err := r.db.WithContext(ctx).
+		Where("approval_id = ?", approvalID).
+		Order("created_at ASC").
+		Find(&events).Error
+	if err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func (r *Repository) CreateTradingHalt(ctx context.Context, halt *model.TradingHalt) error {
+	return r.db.WithContext(ctx).Create(halt).Error
+}
+
+func (r *Repository) ListActiveTradingHalts(ctx context.Context) ([]model.TradingHalt, error) {
+	var halts []model.TradingHalt
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Order("tripped_at ASC").Find(&halts).Error; err != nil {
+		return nil, err
+	}
+	return halts, nil
+}
+
+func (r *Repository) ListTradingHalts(ctx context.Context, limit int) ([]model.TradingHalt, error) {
+	var halts []model.TradingHalt
+	query := r.db.WithContext(ctx).Order("tripped_at DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&halts).Error; err != nil {
+		return nil, err
+	}
+	return halts, nil
+}
+
+// ResetTradingHalts deactivates the active halts of a scope. An empty kind
+// resets every kind in the scope.
+func (r *Repository) ResetTradingHalts(ctx context.Context, scope, kind, resetBy string, now time.Time) (int64, error) {
+	query := r.db.WithContext(ctx).
+		Model(&model.TradingHalt{}).
+		Where("scope = ?", scope).
+		Where("active = ?", true)
+	if kind != "" {
+		query = query.Where("kind = ?", kind)
+	}
+	res := query.Updates(map[string]interface{}{
+		"active":   false,
+		"reset_by": resetBy,
+		"reset_at": now,
+	})
+	return res.RowsAffected, res.Error
+}
+
+func (r *Repository) CreatePortfolioSnapshot(ctx context.Context, snapshot *model.PortfolioSnapshot) error {
+	return r.db.WithContext(ctx).Create(snapshot).Error
+}
+
+func (r *Repository) ListPortfolioSnapshots(ctx context.Context, userID string, since time.Time, limit int) ([]model.PortfolioSnapshot, error) {
+	var snapshots []model.PortfolioSnapshot
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&snapshots).Error; err != nil {
+		return nil, err
+	}
+	return snapshots, nil
+}
+
+// UpdateAITradesCurrentValue revalues the tokens still held from successful
+// BUY trades of a token at priceBNB per whole token: the remaining quantity
+// of each trade's open lot, or everything it bought when it has no lot.
+// Trades whose lot was sold off keep the value they were last marked at.
+func (r *Repository) UpdateAITradesCurrentValue(ctx context.Context, userID, tokenAddress string, priceBNB decimal.Decimal) error {
+	const lotOf = "position_lots l WHERE l.user_id = ai_trades.user_id AND l.buy_tx_hash = ai_trades.tx_hash"
+	return r.db.WithContext(ctx).
+		Model(&model.AITrade{}).
+		Where("user_id = ?", userID).
+		Where("LOWER(token_address) = LOWER(?)", tokenAddress).
+		Where("type = ?", "BUY").
+		Where("status = ?", "success").
+		Update("current_value", gorm.Expr("COALESCE((SELECT SUM(l.remaining) FROM "+lotOf+"), amount_out) * ?", priceBNB)).Error
+}
+
+func (r *Repository) CreatePositionLot(ctx context.Context, lot *model.PositionLot) error {
+	return r.db.WithContext(ctx).Create(lot).Error
+}
+
+// RecordRealizedPnL stores a sell's ledger entry and the remaining quantity of
+// every lot it consumed in one transaction. Lots left empty are closed.
+func (r *Repository) RecordRealizedPnL(ctx context.Context, entry *model.RealizedPnL, remaining map[string]decimal.Decimal) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := updateLotRemaining(tx, remaining); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+}
+
+// RecordSellAgainstLots locks the open lots of a position, oldest first, and
+// stores the ledger entry and remaining lot quantities match derives from
+// them in the same transaction.
+func (r *Repository) RecordSellAgainstLots(ctx context.Context, userID, tokenAddress string, simulated bool, match func(lots []model.PositionLot) (*model.RealizedPnL, map[string]decimal.Decimal)) (*model.RealizedPnL, error) {
+	var entry *model.RealizedPnL
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var lots []model.PositionLot
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("user_id = ?", userID).
+			Where("simulated = ?", simulated).
+			Where("LOWER(token_address) = LOWER(?)", tokenAddress).
+			Where("remaining > 0").
+			Order("opened_at ASC").
+			Find(&lots).Error
+		if err != nil {
+			return err
+		}
+		var remaining map[string]decimal.Decimal
+		entry, remaining = match(lots)
+		if err := updateLotRemaining(tx, remaining); err != nil {
+			return err
+		}
+		return tx.Create(entry).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func updateLotRemaining(tx *gorm.DB, remaining map[string]decimal.Decimal) error {
+	now := time.Now().UTC()
+	for id, left := range remaining {
+		updates := map[string]interface{}{"remaining": left}
+		closed := left.LessThanOrEqual(decimal.Zero)
+		if closed {
+			updates["remaining"] = decimal.Zero
+			updates["closed_at"] = now
+		}
+		if err := tx.Model(&model.PositionLot{}).Where("id = ?", id).Updates(updates).Error; err != nil {
+			return err
+		}
+		if !closed {
+			continue
+		}
+		// Nothing the buy received is held any more.
+		err := tx.Exec(`UPDATE ai_trades SET current_value = 0
+			FROM position_lots l
+			WHERE l.id = ? AND ai_trades.user_id = l.user_id AND ai_trades.tx_hash = l.buy_tx_hash AND ai_trades.type = 'BUY'`, id).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *Repository) ListRealizedPnL(ctx context.Context, userID string, since time.Time, limit int) ([]model.RealizedPnL, error) {
+	var entries []model.RealizedPnL
+	query := r.db.WithContext(ctx).Where("user_id = ?", userID)
+	if !since.IsZero() {
+		query = query.Where("created_at >= ?", since)
+	}
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Order("created_at DESC").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SumRealizedPnLSince returns the net realized BNB of a user's live or paper
+// trades since the given time.
+func (r *Repository) SumRealizedPnLSince(ctx context.Context, userID string, since time.Time, mode string) (decimal.Decimal, error) {
+	var total decimal.NullDecimal
+	err := r.db.WithContext(ctx).
+		Model(&model.RealizedPnL{}).
+		Scopes(walletMode(mode)).
+		Select("SUM(realized_bnb)").
+		Where("user_id = ?", userID).
+		Where("created_at >= ?", since).
+		Scan(&total).Error
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if !total.Valid {
+		return decimal.Zero, nil
+	}
+	return total.Decimal, nil
+}
+
+// CreateScoringModel stores the config as the next version, activating it
+// when asked.
+func (r *Repository) CreateScoringModel(ctx context.Context, m *model.ScoringModel, activate bool) error {
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&model.ScoringModel{}).Select("COALESCE(MAX(version), 0)").Scan(&latest).Error; err != nil {
+			return err
+		}
+		m.Version = latest + 1
+		m.Active = false
+		if err := tx.Create(m).Error; err != nil {
+			return err
+		}
+		if !activate {
+			return nil
+		}
+		return activateScoringModel(tx, m)
+	})
+}
+
+// SeedScoringModel stores m as the active version 1 unless a version 1
+// exists. Instances starting together race here, so losing is not an error.
+func (r *Repository) SeedScoringModel(ctx context.Context, m *model.ScoringModel) error {
+	now := time.Now().UTC()
+	m.Version = 1
+	m.Active = true
+	m.ActivatedAt = &now
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{Columns: []clause.Column{{Name: "version"}}, DoNothing: true}).
+		Create(m).Error
+}
+
+// ActivateScoringModel makes the version the only active one.
+func (r *Repository) ActivateScoringModel(ctx context.Context, version int) (*model.ScoringModel, error) {
+	var m model.ScoringModel
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("version = ?", version).First(&m).Error; err != nil {
+			return err
+		}
+		return activateScoringModel(tx, &m)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func activateScoringModel(tx *gorm.DB, m *model.ScoringModel) error {
+	if err := tx.Model(&model.ScoringModel{}).Where("active = ?", true).Update("active", false).Error; err != nil {
+		return err
+	}
+	now := time.Now().UTC()
+	m.Active = true
+	m.ActivatedAt = &now
+	return tx.Model(m).Updates(map[string]interface{}{"active": true, "activated_at": now}).Error
+}
+
+// GetActiveScoringModel returns gorm.ErrRecordNotFound when no version is
+// active.
+func (r *Repository) GetActiveScoringModel(ctx context.Context) (*model.ScoringModel, error) {
+	var m model.ScoringModel
+	if err := r.db.WithContext(ctx).Where("active = ?", true).First(&m).Error; err != nil {
+		return nil, err
+	}
+	return &m, nil
+}
+
+func (r *Repository) ListScoringModels(ctx context.Context, limit int) ([]model.ScoringModel, error) {
+	var models []model.ScoringModel
+	query := r.db.WithContext(ctx).Order("version DESC")
+	if limit > 0 {
+		query = query.Limit(limit)
+	}
+	if err := query.Find(&models).Error; err != nil {
+		return nil, err
+	}
+	return models, nil
+}
+
+// TouchAnalysisAgent registers the agent on first use and records when it
+// was last seen.
+func (r *Repository) TouchAnalysisAgent(ctx context.Context, agentID string) error {
+	now := time.Now().UTC()
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "agent_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"last_seen_at": now, "updated_at": now}),
+	}).Create(&model.AnalysisAgent{AgentID: agentID, Accuracy: 0.5, LastSeenAt: &now}).Error
+}
+
+func (r *Repository) ListAnalysisAgents(ctx context.Context) ([]model.AnalysisAgent, error) {
+	var agents []model.AnalysisAgent
+	if err := r.db.WithContext(ctx).Order("accuracy DESC, agent_id ASC").Find(&agents).Error; err != nil {
+		return nil, err
+	}
+	return agents, nil
+}
+
+// AgentCallCounts tallies an agent's scored golden dog calls.
+type AgentCallCounts struct {
+	AgentID        string
+	TruePositives  int
+	FalsePositives int
+	TrueNegatives  int
+	FalseNegatives int
+}
+
+// CountAgentCalls scores each agent's latest verdict on every token analyzed
+// at least horizon ago. A token was a golden dog when its price reached
+// winMultiple times the price at analysis within horizon.
+func (r *Repository) CountAgentCalls(ctx context.Context, horizon time.Duration, winMultiple float64) ([]AgentCallCounts, error) {
+	cutoff := time.Now().UTC().Add(-horizon)
+	var counts []AgentCallCounts
+	err := r.db.WithContext(ctx).Raw(`WITH verdicts AS (
+			SELECT DISTINCT ON (agent_id, token_address) agent_id, token_address, is_golden_dog, created_at
+			FROM token_analyses
+			WHERE source = ? AND created_at <= ?
+			ORDER BY agent_id, token_address, created_at DESC
+		), priced AS (
+			SELECT v.agent_id, v.is_golden_dog,
+				(SELECT p.price_usd FROM token_price_snapshots p
+					WHERE p.token_address = v.token_address AND p.ts >= v.created_at
+					ORDER BY p.ts ASC LIMIT 1) AS entry,
+				(SELECT MAX(p.price_usd) FROM token_price_snapshots p
+					WHERE p.token_address = v.token_address AND p.ts >= v.created_at
+					AND p.ts <= v.created_at + make_interval(secs => ?)) AS peak
+			FROM verdicts v
+		)
+		SELECT agent_id,
+			COUNT(*) FILTER (WHERE is_golden_dog AND peak >= entry * ?) AS true_positives,
+			COUNT(*) FILTER (WHERE is_golden_dog AND NOT (peak >= entry * ?)) AS false_positives,
+			COUNT(*) FILTER (WHERE NOT is_golden_dog AND NOT (peak >= entry * ?)) AS true_negatives,
+			COUNT(*) FILTER (WHERE NOT is_golden_dog AND peak >= entry * ?) AS false_negatives
+		FROM priced
+		WHERE entry > 0 AND peak IS NOT NULL
+		GROUP BY agent_id`,
+		model.AnalysisSourceAgent, cutoff, horizon.Seconds(), winMultiple, winMultiple, winMultiple, winMultiple).
+		Scan(&counts).Error
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// UpdateAnalysisAgentAccuracy stores a rescored agent, creating it if needed.
+func (r *Repository) UpdateAnalysisAgentAccuracy(ctx context.Context, agent *model.AnalysisAgent) error {
+	now := time.Now().UTC()
+	agent.AccuracyAt = &now
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "agent_id"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{
+			"samples":     agent.Samples,
+			"correct":     agent.Correct,
+			"precision":   agent.Precision,
+			"recall":      agent.Recall,
+			"accuracy":    agent.Accuracy,
+			"accuracy_at": now,
+			"updated_at":  now,
+		}),
+	}).Create(agent).Error
+}
+
+func (r *Repository) CreateAgentSubscription(ctx context.Context, sub *model.AgentSubscription) error {
+	return r.db.WithContext(ctx).Create(sub).Error
+}
+
+// ListAgentSubscriptions returns the subscriptions of agentID, or of every
+// agent when agentID is empty.
+func (r *Repository) ListAgentSubscriptions(ctx context.Context, agentID string) ([]model.AgentSubscription, error) {
+	var subs []model.AgentSubscription
+	query := r.db.WithContext(ctx).Order("created_at ASC")
+	if agentID != "" {
+		query = query.Where("agent_id = ?", agentID)
+	}
+	if err := query.Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *Repository) ListActiveAgentSubscriptions(ctx context.Context) ([]model.AgentSubscription, error) {
+	var subs []model.AgentSubscription
+	if err := r.db.WithContext(ctx).Where("active = ?", true).Find(&subs).Error; err != nil {
+		return nil, err
+	}
+	return subs, nil
+}
+
+func (r *Repository) DeleteAgentSubscription(ctx context.Context, id, agentID string) (bool, error) {
+	result := r.db.WithContext(ctx).
+		Where("id = ? AND agent_id = ?", id, agentID).
+		Delete(&model.AgentSubscription{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// RecordAgentSubscriptionDelivery stores the outcome of a delivery. A
+// subscription is deactivated after maxFailures consecutive failures.
+func (r *Repository) RecordAgentSubscriptionDelivery(ctx context.Context, id string, deliveryErr error, maxFailures int) error {
+	query := r.db.WithContext(ctx).Model(&model.AgentSubscription{}).Where("id = ?", id)
+	if deliveryErr == nil {
+		return query.Updates(map[string]interface{}{
+			"failures":          0,
+			"last_error":        "",
+			"last_delivered_at": time.Now().UTC(),
+		}).Error
+	}
+	return query.Updates(map[string]interface{}{
+		"failures":   gorm.Expr("failures + 1"),
+		"last_error": deliveryErr.Error(),
+		"active":     gorm.Expr("failures + 1 < ?", maxFailures),
+	}).Error
+}
+
+// ListTokensDueForOutcome returns enriched tokens launched at least after ago
+// that have no outcome for horizon yet, oldest first so a backlog drains in
+// horizon order.
+func (r *Repository) ListTokensDueForOutcome(ctx context.Context, horizon string, after time.Duration, limit int) ([]model.Token, error) {
+	var tokens []model.Token
+	err := r.db.WithContext(ctx).
+		Where("enriched_at IS NOT NULL AND created_at <= ?", time.Now().UTC().Add(-after)).
+		Where("NOT EXISTS (SELECT 1 FROM token_outcomes o WHERE o.token_address = tokens.address AND o.horizon = ?)", horizon).
+		Order("created_at ASC").
+		Limit(limit).
+		Find(&tokens).Error
+	return tokens, err
+}
+
+func (r *Repository) ListMarketSnapshotsBetween(ctx context.Context, tokenAddress string, from, to time.Time) ([]model.TokenMarketSnapshot, error) {
+	var snapshots []model.TokenMarketSnapshot
+	err := r.db.WithContext(ctx).
+		Where("token_address = ? AND created_at >= ? AND created_at <= ?", tokenAddress, from, to).
+		Order("created_at ASC").
+		Find(&snapshots).Error
+	return snapshots, err
+}
+
+// CreateTokenOutcome stores an outcome unless the horizon is already labeled.
+func (r *Repository) CreateTokenOutcome(ctx context.Context, outcome *model.TokenOutcome) error {
+	return r.db.WithContext(ctx).
+		Clauses(clause.OnConflict{DoNothing: true}).
+		Create(outcome).Error
+}
+
+func (r *Repository) ListTokenOutcomes(ctx context.Context, address string) ([]model.TokenOutcome, error) {
+	var outcomes []model.TokenOutcome
+	err := r.db.WithContext(ctx).
+		Where("LOWER(token_address) = LOWER(?)", address).
+		Order("horizon_at ASC").
+		Find(&outcomes).Error
+	return outcomes, err
+}
+
+// OutcomeFilter selects the analyses scored against outcomes. Empty fields
+// do not filter.
+type OutcomeFilter struct {
+	From         time.Time
+	To           time.Time
+	Horizon      string
+	Source       string
+	AgentID      string
+	ModelVersion string
+}
+
+// ScoredOutcome pairs the first matching analysis of a token with its
+// outcome at the horizon.
+type ScoredOutcome struct {
+	TokenAddress   string
+	Source         string
+	AgentID        string
+	ModelVersion   string
+	RiskScore      int
+	RiskLevel      string
+	IsGoldenDog    bool
+	GoldenDogScore int
+	AnalyzedAt     time.Time
+	Outcome        string
+	Labels         datatypes.JSON
+	MaxReturn      float64
+}
+
+// ListScoredOutcomes returns, per token, the first analysis made before the
+// horizon that matches the filter, joined with the token's labeled outcome.
+// Tokens without price data are left out.
+func (r *Repository) ListScoredOutcomes(ctx context.Context, f OutcomeFilter) ([]ScoredOutcome, error) {
+	query := r.db.WithContext(ctx).
+		Table("token_analyses a").
+		Select(`DISTINCT ON (a.token_address) a.token_address, a.source, a.agent_id, a.model_version,
+			a.risk_score, a.risk_level, a.is_golden_dog, a.golden_dog_score, a.created_at AS analyzed_at,
+			o.outcome, o.labels, o.max_return`).
+		Joins("JOIN token_outcomes o ON o.token_address = a.token_address AND o.horizon = ?", f.Horizon).
+		Where("a.created_at < o.horizon_at AND o.outcome <> ?", model.OutcomeNoData)
+	if !f.From.IsZero() {
+		query = query.Where("a.created_at >= ?", f.From)
+	}
+	if !f.To.IsZero() {
+		query = query.Where("a.created_at <= ?", f.To)
+	}
+	if f.Source != "" {
+		query = query.Where("a.source = ?", f.Source)
+	}
+	if f.AgentID != "" {
+		query = query.Where("a.agent_id = ?", f.AgentID)
+	}
+	if f.ModelVersion != "" {
+		query = query.Where("a.model_version = ?", f.ModelVersion)
+	}
+	var rows []ScoredOutcome
+	if err := query.Order("a.token_address, a.created_at ASC").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	return rows, nil
+}
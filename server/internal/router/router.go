@@ -14,6 +14,7 @@ import (
 
 	"easymeme/internal/config"
 	"easymeme/internal/handler"
+	"easymeme/internal/model"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
@@ -25,15 +26,18 @@ func Setup(
 	tradeHandler *handler.TradeHandler,
 	walletHandler *handler.WalletHandler,
 	aiTradeHandler *handler.AITradeHandler,
+	adminHandler *handler.AdminHandler,
+	backtestHandler *handler.BacktestHandler,
 	wsHub *handler.WebSocketHub,
 	healthReporter interface{ HealthStatus() map[string]interface{} },
+	tradingReporter interface{ HealthStatus() map[string]interface{} },
 ) *gin.Engine {
 	r := gin.Default()
 
 	r.Use(cors.New(cors.Config{
 		AllowOrigins:     cfg.CorsAllowedOrigins,
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-User-Id", "X-Timestamp", "X-Nonce", "X-Signature"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", "X-API-Key", "X-User-Id", "X-Timestamp", "X-Nonce", "X-Signature", "X-Agent-Id"},
 		AllowCredentials: true,
 	}))
 
@@ -42,21 +46,36 @@ func Setup(
 		if healthReporter != nil {
 			data["scanner"] = healthReporter.HealthStatus()
 		}
+		if tradingReporter != nil {
+			data["trading"] = tradingReporter.HealthStatus()
+		}
 		c.JSON(200, data)
 	})
 
+	agentAuth := agentKeyMiddleware(cfg.ApiKey, cfg.AgentApiKeys)
+
 	api := r.Group("/api")
 	{
 		api.GET("/tokens", tokenHandler.GetTokens)
 		api.GET("/tokens/:address", tokenHandler.GetToken)
 		api.GET("/tokens/:address/detail", tokenHandler.GetTokenDetail)
 		api.GET("/tokens/pending", tokenHandler.GetPendingTokens)
+		api.POST("/tokens/pending/claim", agentAuth, tokenHandler.ClaimPendingTokens)
+		api.POST("/tokens/pending/heartbeat", agentAuth, tokenHandler.RenewTokenLeases)
+		api.POST("/tokens/pending/release", agentAuth, tokenHandler.ReleaseTokenLeases)
 		api.GET("/tokens/analyzed", tokenHandler.GetAnalyzedTokens)
 		api.GET("/tokens/golden-dogs", tokenHandler.GetGoldenDogs)
 		api.GET("/tokens/stats/golden-dog-score-distribution", tokenHandler.GetGoldenDogScoreDistribution)
+		api.GET("/tokens/stats/calibration", tokenHandler.GetCalibrationReport)
 		api.GET("/tokens/:address/price-series", tokenHandler.GetTokenPriceSeries)
-		api.POST("/tokens/price-snapshots", apiKeyMiddleware(cfg.ApiKey), tokenHandler.UpsertTokenPriceSnapshot)
-		api.POST("/tokens/:address/analysis", apiKeyMiddleware(cfg.ApiKey), tokenHandler.PostTokenAnalysis)
+		api.GET("/tokens/:address/analyses", tokenHandler.GetTokenAnalyses)
+		api.GET("/tokens/:address/outcomes", tokenHandler.GetTokenOutcomes)
+		api.GET("/analysis-agents", tokenHandler.GetAnalysisAgents)
+		api.GET("/agent-subscriptions", agentAuth, tokenHandler.GetAgentSubscriptions)
+		api.POST("/agent-subscriptions", agentAuth, tokenHandler.CreateAgentSubscription)
+		api.POST("/agent-subscriptions/remove", agentAuth, tokenHandler.RemoveAgentSubscription)
+		api.POST("/tokens/price-snapshots", agentAuth, tokenHandler.UpsertTokenPriceSnapshot)
+		api.POST("/tokens/:address/analysis", agentAuth, tokenHandler.PostTokenAnalysis)
 
 		api.POST("/trades", tradeHandler.CreateTrade)
 		api.GET("/trades", tradeHandler.GetTrades)
@@ -64,6 +83,9 @@ func Setup(
 
 		api.GET("/wallet/info", walletHandler.GetWalletInfo)
 		api.GET("/ai-positions", walletHandler.GetAIPositions)
+		api.GET("/portfolio/summary", walletHandler.GetPortfolioSummary)
+		api.GET("/portfolio/snapshots", walletHandler.GetPortfolioSnapshots)
+		api.GET("/portfolio/realized", walletHandler.GetRealizedPnL)
 		walletAuth := chainMiddleware(
 			apiKeyUserMiddleware(cfg.ApiKey, cfg.ApiUserID),
 			hmacMiddleware(cfg.ApiHmacSecret),
@@ -71,12 +93,48 @@ func Setup(
 		api.POST("/wallet/create", walletAuth, walletHandler.CreateWallet)
 		api.GET("/wallet/balance", walletAuth, walletHandler.GetWalletBalance)
 		api.POST("/wallet/withdraw", walletAuth, walletHandler.Withdraw)
+		api.POST("/wallet/totp/setup", walletAuth, walletHandler.SetupTOTP)
+		api.POST("/wallet/totp/enable", walletAuth, walletHandler.EnableTOTP)
+		api.GET("/wallet/withdrawal-addresses", walletAuth, walletHandler.ListWithdrawalAddresses)
+		api.POST("/wallet/withdrawal-addresses", walletAuth, walletHandler.AddWithdrawalAddress)
+		api.POST("/wallet/withdrawal-addresses/remove", walletAuth, walletHandler.RemoveWithdrawalAddress)
+		api.POST("/wallet/withdrawal-limit", walletAuth, walletHandler.UpdateWithdrawalLimit)
 		api.POST("/wallet/execute-trade", walletAuth, walletHandler.ExecuteTrade)
+		api.POST("/wallet/liquidate", walletAuth, walletHandler.LiquidateAll)
 		api.POST("/wallet/config", walletAuth, walletHandler.UpsertWalletConfig)
+		api.GET("/wallet/policy", walletAuth, walletHandler.GetWalletPolicy)
+		api.POST("/wallet/policy", walletAuth, walletHandler.UpsertWalletPolicy)
+		api.GET("/wallet/policy/violations", walletAuth, walletHandler.GetPolicyViolations)
+		api.GET("/wallet/allowances", walletAuth, walletHandler.ListAllowances)
+		api.POST("/wallet/allowances/revoke", walletAuth, walletHandler.RevokeAllowance)
+		api.GET("/wallet/orders", walletAuth, walletHandler.ListOrders)
+		api.POST("/wallet/orders", walletAuth, walletHandler.CreateOrder)
+		api.POST("/wallet/orders/cancel", walletAuth, walletHandler.CancelOrder)
+		api.GET("/wallet/ladders", walletAuth, walletHandler.ListLadders)
+		api.POST("/wallet/ladders", walletAuth, walletHandler.CreateLadder)
+		api.POST("/wallet/ladders/cancel", walletAuth, walletHandler.CancelLadder)
+		api.GET("/wallet/approvals", walletAuth, walletHandler.ListApprovals)
+		api.GET("/wallet/approvals/events", walletAuth, walletHandler.GetApprovalEvents)
+		api.POST("/wallet/approvals/approve", walletAuth, walletHandler.ApproveTrade)
+		api.POST("/wallet/approvals/reject", walletAuth, walletHandler.RejectTrade)
+		api.GET("/wallet/ws", walletAuth, wsHub.HandleUserWebSocket)
 
 		api.GET("/ai-trades", aiTradeHandler.GetAITrades)
 		api.POST("/ai-trades", walletAuth, aiTradeHandler.CreateAITrade)
 		api.GET("/ai-trades/stats", aiTradeHandler.GetAITradeStats)
+
+		api.POST("/backtest", apiKeyMiddleware(cfg.ApiKey), backtestHandler.RunBacktest)
+
+		adminAuth := chainMiddleware(
+			adminKeyMiddleware(cfg.AdminApiKey),
+			hmacMiddleware(cfg.ApiHmacSecret),
+		)
+		api.GET("/admin/trading-halts", adminAuth, adminHandler.GetTradingHalts)
+		api.POST("/admin/trading-halts/reset", adminAuth, adminHandler.ResetTradingHalt)
+		api.POST("/admin/kill-switch", adminAuth, adminHandler.EngageKillSwitch)
+		api.GET("/admin/scoring-models", adminAuth, adminHandler.GetScoringModels)
+		api.POST("/admin/scoring-models", adminAuth, adminHandler.PublishScoringModel)
+		api.POST("/admin/scoring-models/:version/activate", adminAuth, adminHandler.ActivateScoringModel)
 	}
 
 	r.GET("/ws", wsHub.HandleWebSocket)
@@ -100,6 +158,46 @@ func apiKeyMiddleware(expected string) gin.HandlerFunc {
 	}
 }
 
+// adminKeyMiddleware requires the admin key. Unlike the agent key it has no
+// open default: without one configured every admin request is refused.
+func adminKeyMiddleware(expected string) gin.HandlerFunc {
+	if expected == "" {
+		return func(c *gin.Context) {
+			c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": "admin api key not configured"})
+		}
+	}
+	return apiKeyMiddleware(expected)
+}
+
+// agentKeyMiddleware authenticates analysis agents and records who they are
+// for the handlers: an agent's own key identifies that agent and the shared
+// key the default agent. Only without any key configured may an agent name
+// itself.
+func agentKeyMiddleware(sharedKey string, agentKeys map[string]string) gin.HandlerFunc {
+	if sharedKey == "" && len(agentKeys) == 0 {
+		return func(c *gin.Context) {
+			c.Next()
+		}
+	}
+	byKey := make(map[string]string, len(agentKeys))
+	for agentID, key := range agentKeys {
+		byKey[key] = agentID
+	}
+	return func(c *gin.Context) {
+		key := c.GetHeader("X-API-Key")
+		agentID, ok := byKey[key]
+		if !ok && sharedKey != "" && key == sharedKey {
+			agentID, ok = model.DefaultAgentID, true
+		}
+		if !ok {
+			c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+			return
+		}
+		c.Set(handler.AgentIDContextKey, agentID)
+		c.Next()
+	}
+}
+
 func apiKeyUserMiddleware(expectedKey, expectedUser string) gin.HandlerFunc {
 	if expectedKey == "" {
 		return func(c *gin.Context) {
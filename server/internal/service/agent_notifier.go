@@ -0,0 +1,213 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"log"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+)
+
+const (
+	agentNotifyQueueSize      = 256
+	agentNotifySubscriberSize = 32
+	agentNotifyAttempts       = 5
+	agentNotifyBackoff        = 2 * time.Second
+	// A subscription is deactivated after this many consecutive deliveries
+	// fail every attempt.
+	maxSubscriptionFailures = 20
+)
+
+var errAgentQueueFull = errors.New("delivery queue full")
+
+// PendingTokenRenderer formats a token the way the pending queue serves it.
+type PendingTokenRenderer func(token model.Token) interface{}
+
+type agentDelivery struct {
+	sub     model.AgentSubscription
+	address string
+	data    interface{}
+}
+
+// AgentNotifier pushes tokens that finished enrichment to subscribed agents
+// by signed webhook, retrying failed deliveries with exponential backoff.
+// Every subscription has its own delivery queue, so a slow endpoint only
+// delays itself; a delivery that finds its queue full is dropped and counted
+// as a failure. Tokens leased to another agent are not pushed. The webhook
+// body is {"type": "token_enriched", "agentId", "subscriptionId", "data"}.
+type AgentNotifier struct {
+	repo    *repository.Repository
+	webhook *WebhookClient
+	render  PendingTokenRenderer
+	queue   chan string
+	// subscribers is only touched by the dispatch goroutine.
+	subscribers map[string]chan agentDelivery
+}
+
+func NewAgentNotifier(repo *repository.Repository, render PendingTokenRenderer) *AgentNotifier {
+	return &AgentNotifier{
+		repo:        repo,
+		webhook:     NewWebhookClient(),
+		render:      render,
+		queue:       make(chan string, agentNotifyQueueSize),
+		subscribers: make(map[string]chan agentDelivery),
+	}
+}
+
+func (n *AgentNotifier) Start(ctx context.Context) {
+	log.Println("[AgentNotifier] Started")
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case address := <-n.queue:
+				n.dispatch(ctx, address)
+			}
+		}
+	}()
+}
+
+// OnTokenEnriched queues the token for delivery. When the queue is full the
+// token is left to agents polling the queue.
+func (n *AgentNotifier) OnTokenEnriched(tokenAddress string) {
+	select {
+	case n.queue <- tokenAddress:
+	default:
+		log.Printf("[AgentNotifier] queue full, dropped token=%s", tokenAddress)
+	}
+}
+
+func (n *AgentNotifier) dispatch(ctx context.Context, tokenAddress string) {
+	subs, err := n.repo.ListActiveAgentSubscriptions(ctx)
+	if err != nil {
+		log.Printf("[AgentNotifier] list subscriptions err=%v", err)
+		return
+	}
+	n.pruneSubscribers(subs)
+	if len(subs) == 0 {
+		return
+	}
+	token, err := n.repo.GetTokenByAddress(ctx, tokenAddress)
+	if err != nil {
+		log.Printf("[AgentNotifier] load token=%s err=%v", tokenAddress, err)
+		return
+	}
+	if token.AnalysisSource == model.AnalysisSourceAgent {
+		return
+	}
+
+	now := time.Now()
+	scoring := model.ActiveScoring()
+	priority := token.QueuePriorityAt(now, scoring.Priority.AgingPerMinute, scoring.Priority.MaxAging)
+	data := n.render(*token)
+	for _, sub := range subs {
+		if priority < sub.MinPriority || leasedToOther(token, sub.AgentID, now) {
+			continue
+		}
+		select {
+		case n.subscriber(ctx, sub.ID) <- agentDelivery{sub: sub, address: token.Address, data: data}:
+		default:
+			log.Printf("[AgentNotifier] subscription=%s queue full, dropped token=%s", sub.ID, token.Address)
+			n.recordDelivery(ctx, sub.ID, errAgentQueueFull)
+		}
+	}
+}
+
+// subscriber returns the delivery queue of a subscription, starting its
+// worker on first use.
+func (n *AgentNotifier) subscriber(ctx context.Context, id string) chan agentDelivery {
+	if q, ok := n.subscribers[id]; ok {
+		return q
+	}
+	q := make(chan agentDelivery, agentNotifySubscriberSize)
+	n.subscribers[id] = q
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case d, ok := <-q:
+				if !ok {
+					return
+				}
+				n.deliver(ctx, d)
+			}
+		}
+	}()
+	return q
+}
+
+// pruneSubscribers stops the workers of subscriptions that were removed or
+// deactivated.
+func (n *AgentNotifier) pruneSubscribers(active []model.AgentSubscription) {
+	keep := make(map[string]bool, len(active))
+	for _, sub := range active {
+		keep[sub.ID] = true
+	}
+	for id, q := range n.subscribers {
+		if !keep[id] {
+			close(q)
+			delete(n.subscribers, id)
+		}
+	}
+}
+
+func (n *AgentNotifier) deliver(ctx context.Context, d agentDelivery) {
+	// Retries resend the same delivery ID so agents can drop duplicates.
+	payload := map[string]interface{}{
+		"deliveryId":     newDeliveryID(),
+		"type":           "token_enriched",
+		"agentId":        d.sub.AgentID,
+		"subscriptionId": d.sub.ID,
+		"data":           d.data,
+	}
+	var err error
+	backoff := agentNotifyBackoff
+	for attempt := 1; attempt <= agentNotifyAttempts; attempt++ {
+		if err = n.webhook.Post(ctx, d.sub.URL, d.sub.Secret, payload); err == nil {
+			break
+		}
+		if attempt == agentNotifyAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		// Stop retrying once another agent took the token.
+		if token, lerr := n.repo.GetTokenByAddress(ctx, d.address); lerr == nil &&
+			(token.AnalysisSource == model.AnalysisSourceAgent || leasedToOther(token, d.sub.AgentID, time.Now())) {
+			return
+		}
+	}
+	if err != nil {
+		log.Printf("[AgentNotifier] deliver token=%s subscription=%s err=%v", d.address, d.sub.ID, err)
+	}
+	n.recordDelivery(ctx, d.sub.ID, err)
+}
+
+func (n *AgentNotifier) recordDelivery(ctx context.Context, id string, deliveryErr error) {
+	if err := n.repo.RecordAgentSubscriptionDelivery(ctx, id, deliveryErr, maxSubscriptionFailures); err != nil {
+		log.Printf("[AgentNotifier] record delivery subscription=%s err=%v", id, err)
+	}
+}
+
+// leasedToOther reports whether an agent other than agentID holds a live
+// lease on the token.
+func leasedToOther(token *model.Token, agentID string, now time.Time) bool {
+	return token.LeaseOwner != "" && token.LeaseOwner != agentID &&
+		token.LeaseExpiresAt != nil && token.LeaseExpiresAt.After(now)
+}
+
+func newDeliveryID() string {
+	buf := make([]byte, 16)
+	_, _ = rand.Read(buf)
+	return hex.EncodeToString(buf)
+}
@@ -1,90 +1,415 @@
 package service
 
 import (
-    "context"
-    "math/big"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
 
-    "easymeme/pkg/ethereum"
-
-    "github.com/ethereum/go-ethereum/common"
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
 )
 
 type RiskLevel string
 
 const (
-    RiskSafe    RiskLevel = "safe"
-    RiskWarning RiskLevel = "warning"
-    RiskDanger  RiskLevel = "danger"
+	RiskSafe    RiskLevel = "safe"
+	RiskWarning RiskLevel = "warning"
+	RiskDanger  RiskLevel = "danger"
 )
 
-type RiskDetails struct {
-    CanMint           bool    `json:"can_mint"`
-    CanPause          bool    `json:"can_pause"`
-    CanBlacklist      bool    `json:"can_blacklist"`
-    OwnerCanChangeTax bool    `json:"owner_can_change_tax"`
-    LPLocked          bool    `json:"lp_locked"`
-    ContractVerified  bool    `json:"contract_verified"`
-    Top10Holding      float64 `json:"top10_holding"`
+// ScoreFactor is one rule's contribution to a baseline score. Risk rules take
+// points off the risk score; momentum rules add to or take from the golden dog
+// score.
+type ScoreFactor struct {
+	Rule     string `json:"rule"`
+	Category string `json:"category"`
+	Points   int    `json:"points"`
+	Detail   string `json:"detail"`
+}
+
+// BaselineAnalysis is the rule-based analysis written after enrichment. It
+// uses the field names of an agent analysis so readers treat both alike.
+type BaselineAnalysis struct {
+	Source         string            `json:"source"`
+	ModelVersion   int               `json:"modelVersion"`
+	RiskScore      int               `json:"riskScore"`
+	RiskLevel      RiskLevel         `json:"riskLevel"`
+	IsHoneypot     bool              `json:"isHoneypot"`
+	IsGoldenDog    bool              `json:"isGoldenDog"`
+	GoldenDogScore int               `json:"goldenDogScore"`
+	RiskFactors    map[string]string `json:"riskFactors"`
+	Factors        []ScoreFactor     `json:"factors"`
+	Reasoning      string            `json:"reasoning"`
+	Recommendation string            `json:"recommendation"`
+	AnalyzedAt     time.Time         `json:"analyzedAt"`
 }
 
-type RiskResult struct {
-    Score      int         `json:"score"`
-    Level      RiskLevel   `json:"level"`
-    IsHoneypot bool        `json:"is_honeypot"`
-    BuyTax     float64     `json:"buy_tax"`
-    SellTax    float64     `json:"sell_tax"`
-    Details    RiskDetails `json:"details"`
+// baselineInputs are the enrichment fields the rules read.
+type baselineInputs struct {
+	isHoneypot       bool
+	buyTax           float64
+	sellTax          float64
+	isMintable       bool
+	canTakeBack      bool
+	isProxy          bool
+	closedSource     bool // only when GoPlus reports it; missing data is unknown
+	holderCount      int
+	top10Share       float64
+	createdContracts int
+	creatorHoneypots int
+	liquidityUSD     float64
+	volumeH1         float64
+	priceChangeH1    float64
+	buysH1           int
+	sellsH1          int
+	liquidityDrops   int
 }
 
+// Analyzer scores enriched tokens with deterministic rules so every token
+// gets a risk and golden dog score without the external agent.
 type Analyzer struct {
-    client *ethereum.Client
-}
-
-func NewAnalyzer(client *ethereum.Client) *Analyzer {
-    return &Analyzer{client: client}
-}
-
-func (a *Analyzer) Analyze(ctx context.Context, tokenAddr common.Address) RiskResult {
-    details := RiskDetails{
-        CanMint:           false,
-        CanPause:          false,
-        CanBlacklist:      false,
-        OwnerCanChangeTax: false,
-        LPLocked:          false,
-        ContractVerified:  true,
-        Top10Holding:      0,
-    }
-
-    isHoneypot := false
-    if err := a.client.SimulateSell(ctx, tokenAddr, big.NewInt(1e18)); err != nil {
-        isHoneypot = true
-    }
-
-    if isHoneypot {
-        return RiskResult{
-            Score:      0,
-            Level:      RiskDanger,
-            IsHoneypot: true,
-            BuyTax:     0,
-            SellTax:    0,
-            Details:    details,
-        }
-    }
-
-    score := 100
-    level := RiskSafe
-    if score < 40 {
-        level = RiskDanger
-    } else if score < 70 {
-        level = RiskWarning
-    }
-
-    return RiskResult{
-        Score:      score,
-        Level:      level,
-        IsHoneypot: false,
-        BuyTax:     0,
-        SellTax:    0,
-        Details:    details,
-    }
+	repo *repository.Repository
+}
+
+func NewAnalyzer(repo *repository.Repository) *Analyzer {
+	return &Analyzer{repo: repo}
+}
+
+func (a *Analyzer) Analyze(ctx context.Context, token *model.Token) BaselineAnalysis {
+	return scoreBaseline(a.inputs(ctx, token), model.ActiveScoring(), time.Now().UTC())
+}
+
+// Evaluate scores the token and ranks it for the analysis queue from the
+// same inputs.
+func (a *Analyzer) Evaluate(ctx context.Context, token *model.Token) (BaselineAnalysis, float64) {
+	in := a.inputs(ctx, token)
+	cfg := model.ActiveScoring()
+	now := time.Now().UTC()
+	enrichedAt := now
+	if token.EnrichedAt != nil {
+		enrichedAt = *token.EnrichedAt
+	}
+	return scoreBaseline(in, cfg, now), analysisPriority(in, enrichedAt.Sub(token.CreatedAt), cfg)
+}
+
+func (a *Analyzer) inputs(ctx context.Context, token *model.Token) baselineInputs {
+	in := baselineInputsFromToken(token)
+	if token.CreatorAddress != "" {
+		count, err := a.repo.CountCreatorHoneypots(ctx, token.CreatorAddress, token.Address)
+		if err != nil {
+			log.Printf("[Analyzer] creator honeypots for %s: %v", token.Address, err)
+		}
+		in.creatorHoneypots = int(count)
+	}
+	return in
+}
+
+func baselineInputsFromToken(token *model.Token) baselineInputs {
+	in := baselineInputs{
+		isHoneypot: token.IsHoneypot,
+		buyTax:     token.BuyTax,
+		sellTax:    token.SellTax,
+	}
+
+	var details map[string]interface{}
+	_ = json.Unmarshal(token.RiskDetails, &details)
+	if goplus, ok := details["normalized"].(map[string]interface{}); ok {
+		in.isMintable, _ = goplus["is_mintable"].(bool)
+		in.canTakeBack, _ = goplus["can_take_back_ownership"].(bool)
+		in.isProxy, _ = goplus["is_proxy"].(bool)
+		if open, ok := goplus["is_open_source"].(bool); ok {
+			in.closedSource = !open
+		}
+		in.holderCount = int(toFloat64(goplus["holder_count"]))
+		in.top10Share = toFloat64(goplus["top10_holder_share"])
+	}
+
+	var holders map[string]interface{}
+	_ = json.Unmarshal(token.HolderData, &holders)
+	if share := toFloat64(holders["top10Share"]); share > 0 {
+		in.top10Share = share
+	}
+
+	var creator struct {
+		CreatedContracts []string `json:"createdContracts"`
+	}
+	_ = json.Unmarshal(token.CreatorHistory, &creator)
+	in.createdContracts = len(creator.CreatedContracts)
+
+	var market map[string]interface{}
+	_ = json.Unmarshal(token.MarketData, &market)
+	in.liquidityUSD = toFloat64(getNested(market, "liquidity", "usd"))
+	in.volumeH1 = toFloat64(getNested(market, "volume", "h1"))
+	in.priceChangeH1 = toFloat64(getNested(market, "priceChange", "h1"))
+	in.buysH1 = int(toFloat64(getNested(market, "txns", "h1", "buys")))
+	in.sellsH1 = int(toFloat64(getNested(market, "txns", "h1", "sells")))
+
+	var alerts []map[string]interface{}
+	_ = json.Unmarshal(token.MarketAlerts, &alerts)
+	for _, alert := range alerts {
+		if alert["type"] == "LIQUIDITY_DROP" {
+			in.liquidityDrops++
+		}
+	}
+	return in
+}
+
+func scoreBaseline(in baselineInputs, cfg *model.ScoringConfig, now time.Time) BaselineAnalysis {
+	th := cfg.Thresholds
+	factors := []ScoreFactor{}
+	add := func(rule, category, detail string) {
+		factors = append(factors, ScoreFactor{Rule: rule, Category: category, Points: cfg.Weight(rule), Detail: detail})
+	}
+
+	if in.isHoneypot {
+		add("honeypot", "honeypotRisk", "GoPlus flags the token as a honeypot")
+	}
+
+	maxTax := math.Max(in.buyTax, in.sellTax)
+	taxDetail := fmt.Sprintf("buy tax %.1f%%, sell tax %.1f%%", in.buyTax*100, in.sellTax*100)
+	switch {
+	case maxTax >= th.TaxExtreme:
+		add("tax_extreme", "taxRisk", taxDetail)
+	case maxTax >= th.TaxHigh:
+		add("tax_high", "taxRisk", taxDetail)
+	case maxTax >= th.TaxMedium:
+		add("tax_medium", "taxRisk", taxDetail)
+	}
+
+	if in.isMintable {
+		add("mintable", "ownerRisk", "owner can mint new supply")
+	}
+	if in.canTakeBack {
+		add("take_back_ownership", "ownerRisk", "ownership can be reclaimed after renouncing")
+	}
+	if in.isProxy {
+		add("proxy", "ownerRisk", "upgradeable proxy contract")
+	}
+	if in.closedSource {
+		add("closed_source", "ownerRisk", "contract source is not verified")
+	}
+
+	top10Detail := fmt.Sprintf("top 10 holders own %.0f%%", in.top10Share*100)
+	switch {
+	case in.top10Share >= th.Top10High:
+		add("holder_concentration_high", "concentrationRisk", top10Detail)
+	case in.top10Share >= th.Top10Medium:
+		add("holder_concentration_medium", "concentrationRisk", top10Detail)
+	}
+	if in.holderCount > 0 && in.holderCount < th.MinHolders {
+		add("few_holders", "concentrationRisk", fmt.Sprintf("only %d holders", in.holderCount))
+	}
+	if float64(in.sellsH1) > float64(in.buysH1)*th.SellPressureRatio && in.sellsH1 >= th.MinTxnsH1 {
+		add("sell_pressure", "concentrationRisk", fmt.Sprintf("%d sells vs %d buys in the last hour", in.sellsH1, in.buysH1))
+	}
+
+	if in.creatorHoneypots > 0 {
+		add("creator_honeypots", "creatorRisk", fmt.Sprintf("creator deployed %d other honeypot(s)", in.creatorHoneypots))
+	}
+	deployDetail := fmt.Sprintf("creator deployed %d contracts", in.createdContracts)
+	switch {
+	case in.createdContracts >= th.SerialDeployerHigh:
+		add("serial_deployer_high", "creatorRisk", deployDetail)
+	case in.createdContracts >= th.SerialDeployerMedium:
+		add("serial_deployer_medium", "creatorRisk", deployDetail)
+	}
+
+	liquidityDetail := fmt.Sprintf("liquidity $%.0f", in.liquidityUSD)
+	switch {
+	case in.liquidityUSD <= 0:
+		add("no_liquidity", "liquidityRisk", "no liquidity data")
+	case in.liquidityUSD < th.LiquidityLowUSD:
+		add("liquidity_low", "liquidityRisk", liquidityDetail)
+	case in.liquidityUSD < th.LiquidityMediumUSD:
+		add("liquidity_medium", "liquidityRisk", liquidityDetail)
+	}
+	if in.liquidityDrops > 0 {
+		add("liquidity_drop", "liquidityRisk", fmt.Sprintf("%d liquidity drop alert(s)", in.liquidityDrops))
+	}
+
+	riskScore := 100
+	for _, f := range factors {
+		riskScore += f.Points
+	}
+	riskScore = clampScore(riskScore)
+	level := RiskDanger
+	switch {
+	case in.isHoneypot:
+		riskScore = 0
+	case riskScore >= cfg.Levels.SafeScore:
+		level = RiskSafe
+	case riskScore >= cfg.Levels.WarningScore:
+		level = RiskWarning
+	}
+	riskFactors := baselineRiskFactors(factors, cfg.Levels)
+
+	momentumStart := len(factors)
+	priceDetail := fmt.Sprintf("price %+.0f%% in the last hour", in.priceChangeH1)
+	switch {
+	case in.priceChangeH1 >= th.PriceSurgeH1:
+		add("price_surge", "momentum", priceDetail)
+	case in.priceChangeH1 >= th.PriceMomentumH1:
+		add("price_momentum", "momentum", priceDetail)
+	case in.priceChangeH1 <= th.PriceDumpH1:
+		add("price_dump", "momentum", priceDetail)
+	}
+	if in.buysH1 >= in.sellsH1 && in.buysH1 >= th.MinTxnsH1 {
+		add("buy_pressure", "momentum", fmt.Sprintf("%d buys vs %d sells in the last hour", in.buysH1, in.sellsH1))
+	}
+	switch {
+	case in.liquidityUSD >= th.LiquidityDeepUSD:
+		add("deep_liquidity_high", "momentum", liquidityDetail)
+	case in.liquidityUSD >= th.LiquidityMediumUSD:
+		add("deep_liquidity_medium", "momentum", liquidityDetail)
+	}
+	if in.liquidityUSD > 0 && in.volumeH1 >= in.liquidityUSD*th.ActiveVolumeRatio {
+		add("active_trading", "momentum", fmt.Sprintf("1h volume $%.0f", in.volumeH1))
+	}
+	if in.holderCount >= th.HolderBase {
+		add("holder_base", "momentum", fmt.Sprintf("%d holders", in.holderCount))
+	}
+
+	goldenDogScore := int(float64(riskScore)*cfg.Levels.RiskShare + 0.5)
+	for _, f := range factors[momentumStart:] {
+		goldenDogScore += f.Points
+	}
+	goldenDogScore = clampScore(goldenDogScore)
+	momentumGood := in.priceChangeH1 > th.PriceMomentumH1 && in.buysH1 >= in.sellsH1 && in.liquidityUSD >= th.LiquidityMediumUSD
+	isGoldenDog := level != RiskDanger && momentumGood && goldenDogScore >= cfg.Levels.GoldenDogScore
+
+	recommendation := "Do not auto-buy yet; wait for stronger momentum or better risk signals."
+	switch {
+	case level == RiskDanger:
+		recommendation = "Avoid: the token fails the baseline risk rules."
+	case isGoldenDog:
+		recommendation = "Momentum and risk profile are acceptable for a small, controlled position."
+	}
+
+	return BaselineAnalysis{
+		Source:         model.AnalysisSourceBaseline,
+		ModelVersion:   cfg.Version,
+		RiskScore:      riskScore,
+		RiskLevel:      level,
+		IsHoneypot:     in.isHoneypot,
+		IsGoldenDog:    isGoldenDog,
+		GoldenDogScore: goldenDogScore,
+		RiskFactors:    riskFactors,
+		Factors:        factors,
+		Reasoning:      baselineReasoning(factors, riskScore, goldenDogScore),
+		Recommendation: recommendation,
+		AnalyzedAt:     now,
+	}
+}
+
+// baselineRiskFactors grades each risk category LOW, MEDIUM or HIGH from the
+// points it lost, in the shape of an agent analysis's riskFactors.
+func baselineRiskFactors(factors []ScoreFactor, levels model.ScoringLevels) map[string]string {
+	lost := map[string]int{}
+	for _, f := range factors {
+		lost[f.Category] -= f.Points
+	}
+	out := map[string]string{}
+	for _, category := range []string{"honeypotRisk", "taxRisk", "ownerRisk", "concentrationRisk", "creatorRisk", "liquidityRisk"} {
+		switch {
+		case lost[category] >= levels.CategoryHigh:
+			out[category] = "HIGH"
+		case lost[category] >= levels.CategoryMedium:
+			out[category] = "MEDIUM"
+		default:
+			out[category] = "LOW"
+		}
+	}
+	return out
+}
+
+func baselineReasoning(factors []ScoreFactor, riskScore, goldenDogScore int) string {
+	if len(factors) == 0 {
+		return fmt.Sprintf("Baseline rules: no rule triggered. Risk score %d, golden dog score %d.", riskScore, goldenDogScore)
+	}
+	ordered := make([]ScoreFactor, len(factors))
+	copy(ordered, factors)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		return absInt(ordered[i].Points) > absInt(ordered[j].Points)
+	})
+	parts := make([]string, 0, len(ordered))
+	for _, f := range ordered {
+		parts = append(parts, fmt.Sprintf("%s (%+d)", f.Detail, f.Points))
+	}
+	return fmt.Sprintf("Baseline rules: %s. Risk score %d, golden dog score %d.", strings.Join(parts, "; "), riskScore, goldenDogScore)
+}
+
+// sameVerdict reports whether the token already holds this verdict.
+func (b BaselineAnalysis) sameVerdict(token *model.Token) bool {
+	return token.AnalysisSource == model.AnalysisSourceBaseline &&
+		token.ScoringVersion == b.ModelVersion &&
+		token.RiskScore == b.RiskScore &&
+		token.RiskLevel == string(b.RiskLevel) &&
+		token.IsGoldenDog == b.IsGoldenDog &&
+		token.GoldenDogScore == b.GoldenDogScore
+}
+
+// tokenAnalysis is the history record of the analysis.
+func (b BaselineAnalysis) tokenAnalysis(tokenAddress string) (*model.TokenAnalysis, error) {
+	payload, err := json.Marshal(b)
+	if err != nil {
+		return nil, err
+	}
+	return &model.TokenAnalysis{
+		TokenAddress:   tokenAddress,
+		Source:         model.AnalysisSourceBaseline,
+		Model:          model.AnalysisSourceBaseline,
+		ModelVersion:   strconv.Itoa(b.ModelVersion),
+		RiskScore:      b.RiskScore,
+		RiskLevel:      string(b.RiskLevel),
+		IsGoldenDog:    b.IsGoldenDog,
+		GoldenDogScore: b.GoldenDogScore,
+		HoneypotRisk:   baselineHoneypotRisk(b.IsHoneypot),
+		Payload:        payload,
+		CreatedAt:      b.AnalyzedAt,
+	}, nil
+}
+
+func baselineHoneypotRisk(honeypot bool) string {
+	if honeypot {
+		return "HIGH"
+	}
+	return "LOW"
+}
+
+// tokenUpdates is the token row update that makes record current.
+func (b BaselineAnalysis) tokenUpdates(record *model.TokenAnalysis) map[string]interface{} {
+	return map[string]interface{}{
+		"risk_score":       b.RiskScore,
+		"risk_level":       string(b.RiskLevel),
+		"analysis_result":  record.Payload,
+		"analysis_status":  "analyzed",
+		"analysis_source":  model.AnalysisSourceBaseline,
+		"scoring_version":  b.ModelVersion,
+		"is_golden_dog":    b.IsGoldenDog,
+		"golden_dog_score": b.GoldenDogScore,
+		"analyzed_at":      b.AnalyzedAt,
+	}
+}
+
+func clampScore(score int) int {
+	if score < 0 {
+		return 0
+	}
+	if score > 100 {
+		return 100
+	}
+	return score
+}
+
+func absInt(v int) int {
+	if v < 0 {
+		return -v
+	}
+	return v
 }
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"easymeme/internal/model"
+
+	"gorm.io/datatypes"
+)
+
+func TestScoreBaseline(t *testing.T) {
+	cfg := model.DefaultScoringConfig()
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name      string
+		in        baselineInputs
+		riskScore int
+		level     RiskLevel
+		golden    bool
+		goldScore int
+		rules     []string
+	}{
+		{
+			name:      "honeypot",
+			in:        baselineInputs{isHoneypot: true, liquidityUSD: 60000},
+			riskScore: 0, level: RiskDanger, goldScore: 10,
+			rules: []string{"honeypot", "deep_liquidity_high"},
+		},
+		{
+			name:      "high sell tax",
+			in:        baselineInputs{sellTax: 0.2, liquidityUSD: 60000, holderCount: 300},
+			riskScore: 70, level: RiskSafe, goldScore: 50,
+			rules: []string{"tax_high", "deep_liquidity_high", "holder_base"},
+		},
+		{
+			name:      "closed source",
+			in:        baselineInputs{closedSource: true, liquidityUSD: 60000},
+			riskScore: 85, level: RiskSafe, goldScore: 53,
+			rules: []string{"closed_source", "deep_liquidity_high"},
+		},
+		{
+			name:      "deep liquidity with momentum",
+			in:        baselineInputs{liquidityUSD: 80000, volumeH1: 50000, priceChangeH1: 60, buysH1: 100, sellsH1: 30, holderCount: 400},
+			riskScore: 100, level: RiskSafe, golden: true, goldScore: 100,
+			rules: []string{"price_surge", "buy_pressure", "deep_liquidity_high", "active_trading", "holder_base"},
+		},
+		{
+			name:      "no market data",
+			in:        baselineInputs{},
+			riskScore: 85, level: RiskSafe, goldScore: 43,
+			rules: []string{"no_liquidity"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := scoreBaseline(tt.in, cfg, now)
+			if got.RiskScore != tt.riskScore || got.RiskLevel != tt.level {
+				t.Errorf("risk = %d %s, want %d %s", got.RiskScore, got.RiskLevel, tt.riskScore, tt.level)
+			}
+			if got.IsGoldenDog != tt.golden || got.GoldenDogScore != tt.goldScore {
+				t.Errorf("golden dog = %v %d, want %v %d", got.IsGoldenDog, got.GoldenDogScore, tt.golden, tt.goldScore)
+			}
+			if len(got.Factors) != len(tt.rules) {
+				t.Fatalf("factors = %+v, want rules %v", got.Factors, tt.rules)
+			}
+			for i, rule := range tt.rules {
+				if got.Factors[i].Rule != rule {
+					t.Errorf("factor %d = %s, want %s", i, got.Factors[i].Rule, rule)
+				}
+			}
+		})
+	}
+}
+
+func TestBaselineInputsSourceVisibility(t *testing.T) {
+	tests := []struct {
+		name        string
+		riskDetails string
+		closed      bool
+	}{
+		{name: "no GoPlus data", riskDetails: ``},
+		{name: "GoPlus without the flag", riskDetails: `{"normalized":{"is_mintable":false}}`},
+		{name: "verified source", riskDetails: `{"normalized":{"is_open_source":true}}`},
+		{name: "unverified source", riskDetails: `{"normalized":{"is_open_source":false}}`, closed: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			in := baselineInputsFromToken(&model.Token{RiskDetails: datatypes.JSON(tt.riskDetails)})
+			if in.closedSource != tt.closed {
+				t.Errorf("closedSource = %v, want %v", in.closedSource, tt.closed)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+)
+
+const approvalExpiryInterval = time.Minute
+
+// ApprovalExpirer expires trade approvals past their deadline, expires the
+// orders waiting on them and tells the owner.
+type ApprovalExpirer struct {
+	repo    *repository.Repository
+	hub     Broadcaster
+	webhook *WebhookClient
+}
+
+func NewApprovalExpirer(repo *repository.Repository, hub Broadcaster) *ApprovalExpirer {
+	return &ApprovalExpirer{
+		repo:    repo,
+		hub:     hub,
+		webhook: NewWebhookClient(),
+	}
+}
+
+func (e *ApprovalExpirer) Start(ctx context.Context) {
+	log.Println("[ApprovalExpirer] Started")
+	go e.loop(ctx)
+}
+
+func (e *ApprovalExpirer) loop(ctx context.Context) {
+	ticker := time.NewTicker(approvalExpiryInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.Expire(ctx, "")
+		}
+	}
+}
+
+// Expire expires the overdue approvals of userID, or of every user when
+// userID is empty.
+func (e *ApprovalExpirer) Expire(ctx context.Context, userID string) {
+	expired, err := e.repo.ExpireTradeApprovals(ctx, userID, time.Now().UTC())
+	if err != nil {
+		log.Printf("[ApprovalExpirer] expire approvals failed: %v", err)
+		return
+	}
+	for i := range expired {
+		approval := &expired[i]
+		if err := e.repo.CreateTradeApprovalEvent(ctx, &model.TradeApprovalEvent{
+			ApprovalID: approval.ID,
+			UserID:     approval.UserID,
+			Action:     "expired",
+			Actor:      "system",
+		}); err != nil {
+			log.Printf("[ApprovalExpirer] record event approval=%s failed: %v", approval.ID, err)
+		}
+		if _, err := e.repo.SettleApprovalOrders(ctx, approval.ID, OrderStatusExpired, map[string]interface{}{"error_message": "approval expired"}); err != nil {
+			log.Printf("[ApprovalExpirer] expire orders of approval=%s failed: %v", approval.ID, err)
+		}
+		e.notify(ctx, approval)
+	}
+}
+
+func (e *ApprovalExpirer) notify(ctx context.Context, approval *model.TradeApproval) {
+	payload := map[string]interface{}{
+		"type":         "trade_approval_expired",
+		"userId":       approval.UserID,
+		"approvalId":   approval.ID,
+		"tokenAddress": approval.TokenAddress,
+		"tokenSymbol":  approval.TokenSymbol,
+		"side":         approval.Type,
+		"amountIn":     approval.AmountIn,
+		"expiresAt":    approval.ExpiresAt,
+	}
+	if e.hub != nil {
+		e.hub.BroadcastToUser(approval.UserID, payload)
+	}
+	config, err := LoadAutoTradeConfig(ctx, e.repo, approval.UserID)
+	if err != nil || config.ApprovalWebhookURL == "" {
+		return
+	}
+	go func() {
+		if err := e.webhook.Post(context.Background(), config.ApprovalWebhookURL, config.WebhookSecret, payload); err != nil {
+			log.Printf("[ApprovalExpirer] webhook approval=%s failed: %v", approval.ID, err)
+		}
+	}()
+}
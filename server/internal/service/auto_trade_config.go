@@ -0,0 +1,103 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+// AutoTradeConfig is the per-user auto-trade config stored in wallet_configs.
+type AutoTradeConfig struct {
+	Enabled           bool      `json:"enabled"`
+	MaxAmountPerTrade float64   `json:"maxAmountPerTrade"`
+	MinGoldenDogScore int       `json:"minGoldenDogScore"`
+	DailyBudget       float64   `json:"dailyBudget"`
+	ConfirmThreshold  float64   `json:"confirmThreshold"`
+	MaxDailyLoss      float64   `json:"maxDailyLoss"`
+	TakeProfitLevels  []float64 `json:"takeProfitLevels"`
+	TakeProfitAmounts []float64 `json:"takeProfitAmounts"`
+	StopLoss          float64   `json:"stopLoss"`
+	// TrailingStop exits once price falls this fraction below the peak since entry.
+	TrailingStop float64 `json:"trailingStop"`
+	// TrailingActivation is the profit the position must reach before the trailing stop arms.
+	TrailingActivation float64 `json:"trailingActivation"`
+	MaxHoldMinutes     int     `json:"maxHoldMinutes"`
+	// ExitPhases lists GoldenDogPhase values (e.g. DECLINING) that force an exit.
+	ExitPhases []string `json:"exitPhases"`
+	// AutoTradeBaseline lets golden dogs scored only by the server's baseline
+	// rules, before any agent reviewed the token, trigger auto buys.
+	AutoTradeBaseline bool `json:"autoTradeBaseline"`
+	// AutoBuyAmount is the BNB the auto-trader spends per golden dog; defaults to MaxAmountPerTrade.
+	AutoBuyAmount float64 `json:"autoBuyAmount"`
+	// ApprovalTTLMinutes bounds how long a trade waits for ConfirmThreshold sign-off.
+	ApprovalTTLMinutes int `json:"approvalTtlMinutes"`
+	// ApprovalWebhookURL receives pending approvals, signed with WebhookSecret when set.
+	ApprovalWebhookURL string `json:"approvalWebhookUrl"`
+	WebhookSecret      string `json:"webhookSecret"`
+	// CostBasis matches sells to buy lots: fifo (default) or average.
+	CostBasis string `json:"costBasis"`
+	// PaperSlippage is the adverse move applied to paper fills after the
+	// quote (0.005 = 0.5%).
+	PaperSlippage float64 `json:"paperSlippage"`
+}
+
+func LoadAutoTradeConfig(ctx context.Context, repo *repository.Repository, userID string) (AutoTradeConfig, error) {
+	record, err := repo.GetWalletConfig(ctx, userID)
+	if err != nil || record == nil {
+		return AutoTradeConfig{}, err
+	}
+	return ParseAutoTradeConfig(record.Config), nil
+}
+
+func ParseAutoTradeConfig(raw []byte) AutoTradeConfig {
+	var cfg AutoTradeConfig
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &cfg)
+	}
+	return cfg
+}
+
+// DailyBuyAmount sums BNB spent on buys over the last 24 hours by the user's
+// wallet mode, so paper fills never use up a live budget.
+func DailyBuyAmount(ctx context.Context, repo *repository.Repository, userID string) (decimal.Decimal, error) {
+	since := time.Now().Add(-24 * time.Hour)
+	trades, err := repo.GetAITradesByUserSince(ctx, userID, since, userWalletMode(ctx, repo, userID))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	total := decimal.Zero
+	for _, t := range trades {
+		if strings.ToUpper(t.Type) != "BUY" {
+			continue
+		}
+		total = total.Add(t.AmountIn)
+	}
+	return total, nil
+}
+
+// DailyLoss returns the net realized loss in BNB, gas included, of the user's
+// wallet mode over the last 24 hours. It is zero when the day is net profitable.
+func DailyLoss(ctx context.Context, repo *repository.Repository, userID string) (decimal.Decimal, error) {
+	realized, err := repo.SumRealizedPnLSince(ctx, userID, time.Now().Add(-24*time.Hour), userWalletMode(ctx, repo, userID))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if realized.GreaterThanOrEqual(decimal.Zero) {
+		return decimal.Zero, nil
+	}
+	return realized.Neg(), nil
+}
+
+// userWalletMode is the mode of the user's wallet, live when it has none.
+func userWalletMode(ctx context.Context, repo *repository.Repository, userID string) string {
+	if wallet, err := repo.GetManagedWalletByUser(ctx, userID); err == nil {
+		return wallet.Mode
+	}
+	return model.WalletModeLive
+}
@@ -0,0 +1,222 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+const StrategyAutoGoldenDog = "auto_golden_dog"
+
+const autoTraderQueueSize = 256
+
+// A token is not bought again after this many failed auto buys.
+const maxAutoTradeFailures = 3
+
+// AutoTrader buys golden dogs for every user with auto-trade enabled once an
+// analysis flags the token.
+type AutoTrader struct {
+	repo     *repository.Repository
+	executor TradeExecutor
+	hub      Broadcaster
+	queue    chan string
+}
+
+func NewAutoTrader(repo *repository.Repository, executor TradeExecutor, hub Broadcaster) *AutoTrader {
+	return &AutoTrader{
+		repo:     repo,
+		executor: executor,
+		hub:      hub,
+		queue:    make(chan string, autoTraderQueueSize),
+	}
+}
+
+func (a *AutoTrader) Start(ctx context.Context) {
+	log.Println("[AutoTrader] Started")
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case tokenAddress := <-a.queue:
+				a.handleGoldenDog(ctx, tokenAddress)
+			}
+		}
+	}()
+}
+
+// OnGoldenDog queues the token for evaluation without blocking the caller.
+func (a *AutoTrader) OnGoldenDog(tokenAddress string) {
+	select {
+	case a.queue <- tokenAddress:
+	default:
+		log.Printf("[AutoTrader] queue full, dropping token=%s", tokenAddress)
+	}
+}
+
+func (a *AutoTrader) handleGoldenDog(ctx context.Context, tokenAddress string) {
+	token, err := a.repo.GetTokenByAddress(ctx, tokenAddress)
+	if err != nil {
+		log.Printf("[AutoTrader] load token=%s err=%v", tokenAddress, err)
+		return
+	}
+	if !token.IsGoldenDog {
+		return
+	}
+	configs, err := a.repo.ListWalletConfigs(ctx)
+	if err != nil {
+		log.Printf("[AutoTrader] list configs err=%v", err)
+		return
+	}
+	for _, record := range configs {
+		cfg := ParseAutoTradeConfig(record.Config)
+		if !cfg.Enabled {
+			continue
+		}
+		a.evaluate(ctx, token, record.UserID, cfg)
+	}
+}
+
+// evaluate runs the entry checks for one user and buys when all of them pass.
+// Every check is written to the decision trail stored on the AITrade.
+func (a *AutoTrader) evaluate(ctx context.Context, token *model.Token, userID string, cfg AutoTradeConfig) {
+	phase := token.GoldenDogPhase()
+	effective := token.EffectiveScore()
+	trail := map[string]interface{}{
+		"trigger":           "golden_dog_analysis",
+		"evaluatedAt":       time.Now().UTC(),
+		"goldenDogScore":    token.GoldenDogScore,
+		"effectiveScore":    effective,
+		"phase":             phase,
+		"riskLevel":         token.RiskLevel,
+		"riskScore":         token.RiskScore,
+		"analysisSource":    token.AnalysisSource,
+		"isHoneypot":        token.IsHoneypot,
+		"buyTax":            token.BuyTax,
+		"sellTax":           token.SellTax,
+		"minGoldenDogScore": cfg.MinGoldenDogScore,
+		"maxAmountPerTrade": cfg.MaxAmountPerTrade,
+		"dailyBudget":       cfg.DailyBudget,
+		"maxDailyLoss":      cfg.MaxDailyLoss,
+	}
+	checks := []string{}
+	skip := func(reason string) {
+		log.Printf("[AutoTrader] skip user=%s token=%s: %s", userID, token.Address, reason)
+	}
+
+	if token.AnalysisSource == model.AnalysisSourceBaseline && !cfg.AutoTradeBaseline {
+		skip("baseline analysis not reviewed by an agent")
+		return
+	}
+	checks = append(checks, "analysis source "+token.AnalysisSource)
+	if token.IsHoneypot {
+		skip("honeypot")
+		return
+	}
+	if strings.EqualFold(token.RiskLevel, "danger") {
+		skip("risk level danger")
+		return
+	}
+	checks = append(checks, "risk level "+token.RiskLevel)
+	if phase != "EARLY" && phase != "PEAK" {
+		skip("phase " + phase)
+		return
+	}
+	checks = append(checks, "phase "+phase)
+	if cfg.MinGoldenDogScore > 0 && effective < cfg.MinGoldenDogScore {
+		skip(fmt.Sprintf("effective score %d below %d", effective, cfg.MinGoldenDogScore))
+		return
+	}
+	checks = append(checks, fmt.Sprintf("effective score %d >= %d", effective, cfg.MinGoldenDogScore))
+
+	if pos, err := a.repo.GetAIPosition(ctx, userID, token.Address); err == nil && pos.Quantity.GreaterThan(decimal.Zero) {
+		skip("position already open")
+		return
+	}
+	if done, err := a.repo.HasAITradeWithStrategy(ctx, userID, token.Address, StrategyAutoGoldenDog, maxAutoTradeFailures); err != nil || done {
+		skip("already auto-traded")
+		return
+	}
+
+	amount := decimal.NewFromFloat(cfg.AutoBuyAmount)
+	if amount.LessThanOrEqual(decimal.Zero) {
+		amount = decimal.NewFromFloat(cfg.MaxAmountPerTrade)
+	}
+	if amount.LessThanOrEqual(decimal.Zero) {
+		skip("no buy size configured")
+		return
+	}
+	if cfg.MaxAmountPerTrade > 0 {
+		amount = decimal.Min(amount, decimal.NewFromFloat(cfg.MaxAmountPerTrade))
+	}
+	if cfg.DailyBudget > 0 {
+		used, err := DailyBuyAmount(ctx, a.repo, userID)
+		if err != nil {
+			skip("daily budget unavailable")
+			return
+		}
+		remaining := decimal.NewFromFloat(cfg.DailyBudget).Sub(used)
+		trail["dailyBudgetUsed"] = used.String()
+		if remaining.LessThanOrEqual(decimal.Zero) {
+			skip("daily budget exhausted")
+			return
+		}
+		amount = decimal.Min(amount, remaining)
+		checks = append(checks, "daily budget remaining "+remaining.String())
+	}
+	if cfg.MaxDailyLoss > 0 {
+		loss, err := DailyLoss(ctx, a.repo, userID)
+		if err != nil || loss.GreaterThan(decimal.NewFromFloat(cfg.MaxDailyLoss)) {
+			skip("max daily loss reached")
+			return
+		}
+		trail["dailyLoss"] = loss.String()
+		checks = append(checks, "daily loss "+loss.String())
+	}
+	trail["amountBnb"] = amount.String()
+	trail["checks"] = checks
+
+	reason := fmt.Sprintf("auto golden dog buy: score %d (effective %d), phase %s, risk %s", token.GoldenDogScore, effective, phase, token.RiskLevel)
+	log.Printf("[AutoTrader] buy user=%s token=%s amount=%s", userID, token.Address, amount.String())
+	result, err := a.executor.ExecuteManagedTrade(ctx, ManagedTradeRequest{
+		UserID:        userID,
+		TokenAddress:  token.Address,
+		TokenSymbol:   token.Symbol,
+		Type:          "BUY",
+		AmountIn:      amount.String(),
+		Reason:        reason,
+		StrategyUsed:  StrategyAutoGoldenDog,
+		GoldenScore:   token.GoldenDogScore,
+		DecisionTrail: trail,
+	})
+	var pending *ApprovalRequired
+	if errors.As(err, &pending) {
+		log.Printf("[AutoTrader] buy queued for approval user=%s token=%s approval=%s", userID, token.Address, pending.ApprovalID)
+		return
+	}
+	if err != nil {
+		log.Printf("[AutoTrader] buy failed user=%s token=%s err=%v", userID, token.Address, err)
+		return
+	}
+
+	if a.hub != nil {
+		a.hub.BroadcastToUser(userID, map[string]interface{}{
+			"type":         "auto_trade",
+			"userId":       userID,
+			"tokenAddress": token.Address,
+			"tokenSymbol":  token.Symbol,
+			"amountIn":     result.AmountIn,
+			"amountOut":    result.AmountOut,
+			"txHash":       result.TxHash,
+			"status":       result.Status,
+		})
+	}
+}
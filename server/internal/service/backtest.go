@@ -0,0 +1,614 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultBacktestSlippage   = 0.01
+	defaultBacktestHorizon    = 24 * time.Hour
+	defaultBacktestBalanceBNB = 10
+	defaultBacktestMaxSignals = 500
+	// backtestEntryWindow is how soon after a signal a price snapshot must
+	// exist for the trade to be filled.
+	backtestEntryWindow = 15 * time.Minute
+)
+
+// BacktestParams selects the signals to replay and how fills are modeled.
+type BacktestParams struct {
+	From   time.Time       `json:"from"`
+	To     time.Time       `json:"to"`
+	Config AutoTradeConfig `json:"config"`
+	// Slippage is charged on every fill on top of the price impact modeled
+	// from the snapshot's liquidity (0.01 = 1%).
+	Slippage float64 `json:"slippage"`
+	// GasBNB is charged per swap.
+	GasBNB float64 `json:"gasBnb"`
+	// BNBPriceUSD converts the USD price and liquidity series into BNB.
+	BNBPriceUSD float64 `json:"bnbPriceUsd"`
+	// InitialBalanceBNB is the starting equity the drawdown is measured on.
+	InitialBalanceBNB float64 `json:"initialBalanceBnb"`
+	// HorizonMinutes bounds how long a position is followed when the config
+	// has no MaxHoldMinutes; open positions are closed at the last snapshot.
+	HorizonMinutes int `json:"horizonMinutes"`
+	MaxSignals     int `json:"maxSignals"`
+}
+
+// Validate fills defaults and rejects parameters the replay cannot use.
+func (p *BacktestParams) Validate() error {
+	if p.From.IsZero() || p.To.IsZero() || !p.To.After(p.From) {
+		return errors.New("from must be before to")
+	}
+	if p.BNBPriceUSD <= 0 {
+		return errors.New("bnbPriceUsd is required")
+	}
+	if p.Slippage == 0 {
+		p.Slippage = defaultBacktestSlippage
+	}
+	if p.Slippage < 0 || p.Slippage >= 1 {
+		return errors.New("slippage must be between 0 and 1")
+	}
+	if p.GasBNB < 0 {
+		return errors.New("gasBnb must not be negative")
+	}
+	if p.InitialBalanceBNB <= 0 {
+		p.InitialBalanceBNB = defaultBacktestBalanceBNB
+	}
+	if p.MaxSignals <= 0 {
+		p.MaxSignals = defaultBacktestMaxSignals
+	}
+	if p.Config.AutoBuyAmount <= 0 && p.Config.MaxAmountPerTrade <= 0 {
+		return errors.New("config needs autoBuyAmount or maxAmountPerTrade")
+	}
+	return nil
+}
+
+func (p *BacktestParams) horizon() time.Duration {
+	if p.Config.MaxHoldMinutes > 0 {
+		return time.Duration(p.Config.MaxHoldMinutes) * time.Minute
+	}
+	if p.HorizonMinutes > 0 {
+		return time.Duration(p.HorizonMinutes) * time.Minute
+	}
+	return defaultBacktestHorizon
+}
+
+// BacktestEvent is one simulated fill.
+type BacktestEvent struct {
+	At       time.Time `json:"at"`
+	Action   string    `json:"action"` // BUY | SELL
+	Strategy string    `json:"strategy"`
+	Reason   string    `json:"reason"`
+	PriceUSD float64   `json:"priceUsd"`
+	Quantity float64   `json:"quantity"`
+	// AmountBNB is what a BUY spent or a SELL received, net of gas.
+	AmountBNB float64 `json:"amountBnb"`
+}
+
+// BacktestTrade is one replayed signal from entry to final exit.
+type BacktestTrade struct {
+	TokenAddress   string          `json:"tokenAddress"`
+	TokenSymbol    string          `json:"tokenSymbol"`
+	GoldenDogScore int             `json:"goldenDogScore"`
+	EffectiveScore int             `json:"effectiveScore"`
+	Phase          string          `json:"phase"`
+	SignalAt       time.Time       `json:"signalAt"`
+	EntryAt        time.Time       `json:"entryAt"`
+	ExitAt         time.Time       `json:"exitAt"`
+	EntryPriceUSD  float64         `json:"entryPriceUsd"`
+	ExitPriceUSD   float64         `json:"exitPriceUsd"`
+	CostBNB        float64         `json:"costBnb"`
+	ProceedsBNB    float64         `json:"proceedsBnb"`
+	PnLBNB         float64         `json:"pnlBnb"`
+	Return         float64         `json:"return"`
+	ExitReason     string          `json:"exitReason"`
+	Events         []BacktestEvent `json:"events"`
+}
+
+// BacktestSkip is a signal the entry rules or missing data kept out.
+type BacktestSkip struct {
+	TokenAddress string    `json:"tokenAddress"`
+	TokenSymbol  string    `json:"tokenSymbol"`
+	SignalAt     time.Time `json:"signalAt"`
+	Reason       string    `json:"reason"`
+}
+
+// BacktestBucket counts trades whose return fell in [Min, Max).
+type BacktestBucket struct {
+	Label string  `json:"label"`
+	Min   float64 `json:"min"`
+	Max   float64 `json:"max"`
+	Count int     `json:"count"`
+}
+
+type BacktestEquityPoint struct {
+	At        time.Time `json:"at"`
+	EquityBNB float64   `json:"equityBnb"`
+}
+
+type BacktestSummary struct {
+	Signals      int     `json:"signals"`
+	Trades       int     `json:"trades"`
+	Skipped      int     `json:"skipped"`
+	Wins         int     `json:"wins"`
+	Losses       int     `json:"losses"`
+	WinRate      float64 `json:"winRate"`
+	TotalCostBNB float64 `json:"totalCostBnb"`
+	TotalPnLBNB  float64 `json:"totalPnlBnb"`
+	AvgReturn    float64 `json:"avgReturn"`
+	MedianReturn float64 `json:"medianReturn"`
+	BestReturn   float64 `json:"bestReturn"`
+	WorstReturn  float64 `json:"worstReturn"`
+	// MaxDrawdownBNB is the largest peak-to-trough fall of realized equity;
+	// MaxDrawdown is the same as a ratio of the peak.
+	MaxDrawdownBNB float64 `json:"maxDrawdownBnb"`
+	MaxDrawdown    float64 `json:"maxDrawdown"`
+}
+
+type BacktestReport struct {
+	Params       BacktestParams        `json:"params"`
+	Summary      BacktestSummary       `json:"summary"`
+	Distribution []BacktestBucket      `json:"distribution"`
+	Equity       []BacktestEquityPoint `json:"equity"`
+	Trades       []BacktestTrade       `json:"trades"`
+	Skipped      []BacktestSkip        `json:"skipped"`
+	// ScoringVersion is the scoring model whose phases and decay the replay
+	// used.
+	ScoringVersion int       `json:"scoringVersion"`
+	GeneratedAt    time.Time `json:"generatedAt"`
+}
+
+// Backtester replays stored golden dog signals against stored price and
+// liquidity snapshots with the auto-trader's entry rules and the position
+// monitor's exit rules.
+type Backtester struct {
+	repo *repository.Repository
+}
+
+func NewBacktester(repo *repository.Repository) *Backtester {
+	return &Backtester{repo: repo}
+}
+
+func (b *Backtester) Run(ctx context.Context, params BacktestParams) (*BacktestReport, error) {
+	if err := params.Validate(); err != nil {
+		return nil, err
+	}
+	signals, err := b.repo.ListGoldenDogSignals(ctx, params.From, params.To, params.MaxSignals)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &BacktestReport{
+		Params:         params,
+		Trades:         []BacktestTrade{},
+		Skipped:        []BacktestSkip{},
+		ScoringVersion: model.ActiveScoring().Version,
+		GeneratedAt:    time.Now().UTC(),
+	}
+	for i := range signals {
+		token := tokenAtSignal(signals[i])
+		signalAt := signals[i].Analysis.CreatedAt
+		trade, reason, err := b.replay(ctx, token, signals[i].Analysis.Source, signalAt, &params, report.Trades)
+		if err != nil {
+			return nil, err
+		}
+		if trade == nil {
+			report.Skipped = append(report.Skipped, BacktestSkip{
+				TokenAddress: token.Address,
+				TokenSymbol:  token.Symbol,
+				SignalAt:     signalAt,
+				Reason:       reason,
+			})
+			continue
+		}
+		report.Trades = append(report.Trades, *trade)
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		default:
+		}
+	}
+
+	report.Summary = summarizeBacktest(report, len(signals))
+	report.Distribution = backtestDistribution(report.Trades)
+	report.Equity = backtestEquity(report.Trades, params.InitialBalanceBNB, &report.Summary)
+	return report, nil
+}
+
+// tokenAtSignal is the token as the signal's verdict described it, so later
+// analyses do not leak into the replay.
+func tokenAtSignal(signal repository.GoldenDogSignal) *model.Token {
+	token := signal.Token
+	a := signal.Analysis
+	token.IsGoldenDog = a.IsGoldenDog
+	token.GoldenDogScore = a.GoldenDogScore
+	token.RiskScore = a.RiskScore
+	token.RiskLevel = a.RiskLevel
+	token.AnalysisSource = a.Source
+	token.AnalyzedAt = &a.CreatedAt
+	if honeypot, ok := analysisHoneypot(a); ok {
+		token.IsHoneypot = honeypot
+	}
+	return &token
+}
+
+// analysisHoneypot is the verdict's honeypot call. Analyses stored before
+// HoneypotRisk was recorded carry it only in the payload.
+func analysisHoneypot(a model.TokenAnalysis) (bool, bool) {
+	if a.HoneypotRisk != "" {
+		return strings.EqualFold(a.HoneypotRisk, "HIGH"), true
+	}
+	var payload struct {
+		IsHoneypot  *bool `json:"isHoneypot"`
+		RiskFactors struct {
+			HoneypotRisk string `json:"honeypotRisk"`
+		} `json:"riskFactors"`
+	}
+	if len(a.Payload) == 0 || json.Unmarshal(a.Payload, &payload) != nil {
+		return false, false
+	}
+	if payload.IsHoneypot != nil {
+		return *payload.IsHoneypot, true
+	}
+	if payload.RiskFactors.HoneypotRisk != "" {
+		return strings.EqualFold(payload.RiskFactors.HoneypotRisk, "HIGH"), true
+	}
+	return false, false
+}
+
+// replay applies the entry rules at signalAt and, if they pass, follows the
+// position through the price series. It returns the skip reason otherwise.
+func (b *Backtester) replay(ctx context.Context, token *model.Token, source string, signalAt time.Time, p *BacktestParams, done []BacktestTrade) (*BacktestTrade, string, error) {
+	cfg := p.Config
+	phase := token.GoldenDogPhaseAt(signalAt)
+	effective := token.EffectiveScoreAt(signalAt)
+	if source == model.AnalysisSourceBaseline && !cfg.AutoTradeBaseline {
+		return nil, "analysis source baseline", nil
+	}
+	if token.IsHoneypot {
+		return nil, "honeypot", nil
+	}
+	if strings.EqualFold(token.RiskLevel, "danger") {
+		return nil, "risk level danger", nil
+	}
+	if phase != "EARLY" && phase != "PEAK" {
+		return nil, "phase " + phase, nil
+	}
+	if cfg.MinGoldenDogScore > 0 && effective < cfg.MinGoldenDogScore {
+		return nil, fmt.Sprintf("effective score %d below %d", effective, cfg.MinGoldenDogScore), nil
+	}
+
+	size := cfg.AutoBuyAmount
+	if size <= 0 {
+		size = cfg.MaxAmountPerTrade
+	}
+	if cfg.MaxAmountPerTrade > 0 && size > cfg.MaxAmountPerTrade {
+		size = cfg.MaxAmountPerTrade
+	}
+	dayStart := signalAt.Add(-24 * time.Hour)
+	if cfg.DailyBudget > 0 {
+		used := 0.0
+		for _, t := range done {
+			if t.EntryAt.After(dayStart) {
+				used += t.CostBNB - p.GasBNB
+			}
+		}
+		remaining := cfg.DailyBudget - used
+		if remaining <= 0 {
+			return nil, "daily budget exhausted", nil
+		}
+		if size > remaining {
+			size = remaining
+		}
+	}
+	if cfg.MaxDailyLoss > 0 {
+		realized := 0.0
+		for _, t := range done {
+			if t.ExitAt.After(dayStart) && !t.ExitAt.After(signalAt) {
+				realized += t.PnLBNB
+			}
+		}
+		if -realized > cfg.MaxDailyLoss {
+			return nil, "max daily loss reached", nil
+		}
+	}
+
+	series, err := b.repo.GetTokenPriceSeries(ctx, token.Address, signalAt, signalAt.Add(p.horizon()+backtestEntryWindow), 0)
+	if err != nil {
+		return nil, "", err
+	}
+	if len(series) == 0 || series[0].TS.Sub(signalAt) > backtestEntryWindow || series[0].PriceUSD <= 0 {
+		return nil, "no price snapshot within 15 minutes of the signal", nil
+	}
+
+	buyTax, sellTax := 0.0, 0.0
+	if token.EnrichedAt != nil {
+		buyTax, sellTax = token.BuyTax, token.SellTax
+	}
+	sim := &backtestPosition{params: p, sellTax: sellTax}
+	entry := series[0]
+	sim.buy(entry, size, buyTax)
+
+	trade := &BacktestTrade{
+		TokenAddress:   token.Address,
+		TokenSymbol:    token.Symbol,
+		GoldenDogScore: token.GoldenDogScore,
+		EffectiveScore: effective,
+		Phase:          phase,
+		SignalAt:       signalAt,
+		EntryAt:        entry.TS,
+		EntryPriceUSD:  entry.PriceUSD,
+		CostBNB:        sim.cost,
+	}
+	sim.events = append(sim.events, BacktestEvent{
+		At:        entry.TS,
+		Action:    "BUY",
+		Strategy:  StrategyAutoGoldenDog,
+		Reason:    fmt.Sprintf("score %d (effective %d), phase %s", token.GoldenDogScore, effective, phase),
+		PriceUSD:  entry.PriceUSD,
+		Quantity:  sim.qty,
+		AmountBNB: sim.cost,
+	})
+
+	deadline := entry.TS.Add(p.horizon())
+	last := entry
+	for _, snap := range series[1:] {
+		if snap.TS.After(deadline) {
+			break
+		}
+		if snap.PriceUSD <= 0 {
+			continue
+		}
+		last = snap
+		sim.step(token, snap)
+		if sim.qty <= 0 {
+			break
+		}
+	}
+	if sim.qty > 0 {
+		reason := "end of price data"
+		if !last.TS.Before(deadline.Add(-backtestEntryWindow)) {
+			reason = "backtest horizon reached"
+		}
+		sim.sell(last, 1, "end_of_data", reason)
+	}
+
+	exit := sim.events[len(sim.events)-1]
+	trade.ExitAt = exit.At
+	trade.ExitPriceUSD = exit.PriceUSD
+	trade.ExitReason = exit.Reason
+	trade.ProceedsBNB = sim.proceeds
+	trade.PnLBNB = sim.proceeds - sim.cost
+	if sim.cost > 0 {
+		trade.Return = trade.PnLBNB / sim.cost
+	}
+	trade.Events = sim.events
+	return trade, "", nil
+}
+
+// backtestPosition is a simulated position. Prices are USD per token from the
+// snapshots; amounts are BNB at the fixed BNBPriceUSD.
+type backtestPosition struct {
+	params  *BacktestParams
+	sellTax float64
+
+	qty      float64
+	cost     float64 // BNB spent including gas
+	basis    float64 // cost of the tokens still held
+	proceeds float64 // BNB received net of gas
+	peak     float64
+	openedAt time.Time
+	tpHits   int
+	events   []BacktestEvent
+}
+
+// priceImpact is the constant-product impact of swapping amountUSD into a
+// pool whose two sides together hold liquidityUSD.
+func priceImpact(amountUSD, liquidityUSD float64) float64 {
+	if liquidityUSD <= 0 {
+		return 0
+	}
+	side := liquidityUSD / 2
+	return amountUSD / (side + amountUSD)
+}
+
+func (s *backtestPosition) buy(snap model.TokenPriceSnapshot, sizeBNB, buyTax float64) {
+	sizeUSD := sizeBNB * s.params.BNBPriceUSD
+	received := sizeUSD * (1 - priceImpact(sizeUSD, snap.LiquidityUSD)) * (1 - s.params.Slippage)
+	s.qty = received * (1 - buyTax) / snap.PriceUSD
+	s.cost = sizeBNB + s.params.GasBNB
+	s.basis = s.cost
+	s.peak = snap.PriceUSD
+	s.openedAt = snap.TS
+}
+
+// sellValue is what selling qty at the snapshot would return, before gas.
+func (s *backtestPosition) sellValue(snap model.TokenPriceSnapshot, qty float64) float64 {
+	grossUSD := qty * snap.PriceUSD * (1 - s.sellTax)
+	outUSD := grossUSD * (1 - priceImpact(grossUSD, snap.LiquidityUSD)) * (1 - s.params.Slippage)
+	return outUSD / s.params.BNBPriceUSD
+}
+
+func (s *backtestPosition) sell(snap model.TokenPriceSnapshot, ratio float64, strategy, reason string) {
+	qty := s.qty * ratio
+	out := s.sellValue(snap, qty) - s.params.GasBNB
+	s.proceeds += out
+	s.basis *= 1 - ratio
+	s.qty -= qty
+	if ratio >= 1 {
+		s.qty = 0
+		s.basis = 0
+	}
+	s.events = append(s.events, BacktestEvent{
+		At:        snap.TS,
+		Action:    "SELL",
+		Strategy:  strategy,
+		Reason:    reason,
+		PriceUSD:  snap.PriceUSD,
+		Quantity:  qty,
+		AmountBNB: out,
+	})
+}
+
+// step marks the position at snap and applies the exit rules in the
+// position monitor's order: stop-loss, trailing stop, max hold, exit phase,
+// then take-profit levels.
+func (s *backtestPosition) step(token *model.Token, snap model.TokenPriceSnapshot) {
+	cfg := s.params.Config
+	if snap.PriceUSD > s.peak {
+		s.peak = snap.PriceUSD
+	}
+	pl := 0.0
+	if s.basis > 0 {
+		pl = (s.sellValue(snap, s.qty) - s.basis) / s.basis
+	}
+
+	if cfg.StopLoss < 0 && pl <= cfg.StopLoss {
+		s.sell(snap, 1, StrategyStopLoss, fmt.Sprintf("stop-loss %.2f%% hit at %.2f%%", cfg.StopLoss*100, pl*100))
+		return
+	}
+	// Reuse the monitor's trailing rule on BNB prices.
+	bnbUSD := decimal.NewFromFloat(s.params.BNBPriceUSD)
+	pos := &model.AIPosition{
+		Quantity:     decimal.NewFromFloat(s.qty),
+		CostBNB:      decimal.NewFromFloat(s.basis),
+		PeakPriceBNB: decimal.NewFromFloat(s.peak).Div(bnbUSD),
+	}
+	mark := PositionMark{PriceBNB: decimal.NewFromFloat(snap.PriceUSD).Div(bnbUSD)}
+	if reason, ok := trailingStopHit(pos, cfg, mark); ok {
+		s.sell(snap, 1, StrategyTrailingStop, reason)
+		return
+	}
+	if cfg.MaxHoldMinutes > 0 {
+		held := snap.TS.Sub(s.openedAt)
+		if held >= time.Duration(cfg.MaxHoldMinutes)*time.Minute {
+			s.sell(snap, 1, StrategyMaxHold, fmt.Sprintf("held %s, max %d minutes", held.Round(time.Minute), cfg.MaxHoldMinutes))
+			return
+		}
+	}
+	if len(cfg.ExitPhases) > 0 {
+		phase := token.GoldenDogPhaseAt(snap.TS)
+		for _, exitPhase := range cfg.ExitPhases {
+			if strings.EqualFold(strings.TrimSpace(exitPhase), phase) {
+				s.sell(snap, 1, StrategyPhaseExit, "token entered "+phase+" phase")
+				return
+			}
+		}
+	}
+
+	level := -1
+	for i, threshold := range cfg.TakeProfitLevels {
+		if pl >= threshold {
+			level = i
+		}
+	}
+	if level < 0 || level < s.tpHits {
+		return
+	}
+	ratio := takeProfitRatio(level, cfg.TakeProfitAmounts)
+	if ratio <= 0 || ratio >= 1 {
+		ratio = 1
+	}
+	s.tpHits = level + 1
+	s.sell(snap, ratio, StrategyTakeProfit, fmt.Sprintf("take-profit level %d (%.2f%%) hit at %.2f%%", level+1, cfg.TakeProfitLevels[level]*100, pl*100))
+}
+
+func summarizeBacktest(report *BacktestReport, signals int) BacktestSummary {
+	summary := BacktestSummary{
+		Signals: signals,
+		Trades:  len(report.Trades),
+		Skipped: len(report.Skipped),
+	}
+	if len(report.Trades) == 0 {
+		return summary
+	}
+	returns := make([]float64, 0, len(report.Trades))
+	for _, t := range report.Trades {
+		if t.PnLBNB > 0 {
+			summary.Wins++
+		} else {
+			summary.Losses++
+		}
+		summary.TotalCostBNB += t.CostBNB
+		summary.TotalPnLBNB += t.PnLBNB
+		returns = append(returns, t.Return)
+	}
+	sort.Float64s(returns)
+	sum := 0.0
+	for _, r := range returns {
+		sum += r
+	}
+	n := len(returns)
+	summary.WinRate = float64(summary.Wins) / float64(n)
+	summary.AvgReturn = sum / float64(n)
+	summary.WorstReturn = returns[0]
+	summary.BestReturn = returns[n-1]
+	if n%2 == 1 {
+		summary.MedianReturn = returns[n/2]
+	} else {
+		summary.MedianReturn = (returns[n/2-1] + returns[n/2]) / 2
+	}
+	return summary
+}
+
+var backtestBuckets = []BacktestBucket{
+	{Label: "<= -50%", Min: -1e9, Max: -0.5},
+	{Label: "-50% to -20%", Min: -0.5, Max: -0.2},
+	{Label: "-20% to 0%", Min: -0.2, Max: 0},
+	{Label: "0% to 20%", Min: 0, Max: 0.2},
+	{Label: "20% to 50%", Min: 0.2, Max: 0.5},
+	{Label: "50% to 100%", Min: 0.5, Max: 1},
+	{Label: ">= 100%", Min: 1, Max: 1e9},
+}
+
+func backtestDistribution(trades []BacktestTrade) []BacktestBucket {
+	out := make([]BacktestBucket, len(backtestBuckets))
+	copy(out, backtestBuckets)
+	for _, t := range trades {
+		for i := range out {
+			if t.Return >= out[i].Min && t.Return < out[i].Max {
+				out[i].Count++
+				break
+			}
+		}
+	}
+	return out
+}
+
+// backtestEquity books each trade's P&L at its exit and records the maximum
+// drawdown of the resulting equity curve on summary.
+func backtestEquity(trades []BacktestTrade, initial float64, summary *BacktestSummary) []BacktestEquityPoint {
+	ordered := make([]BacktestTrade, len(trades))
+	copy(ordered, trades)
+	sort.Slice(ordered, func(i, j int) bool { return ordered[i].ExitAt.Before(ordered[j].ExitAt) })
+
+	equity := initial
+	peak := initial
+	points := make([]BacktestEquityPoint, 0, len(ordered))
+	for _, t := range ordered {
+		equity += t.PnLBNB
+		points = append(points, BacktestEquityPoint{At: t.ExitAt, EquityBNB: equity})
+		if equity > peak {
+			peak = equity
+		}
+		if dd := peak - equity; dd > summary.MaxDrawdownBNB {
+			summary.MaxDrawdownBNB = dd
+			if peak > 0 {
+				summary.MaxDrawdown = dd / peak
+			}
+		}
+	}
+	return points
+}
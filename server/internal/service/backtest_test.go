@@ -0,0 +1,169 @@
+package service
+
+import (
+	"math"
+	"reflect"
+	"testing"
+	"time"
+
+	"easymeme/internal/model"
+)
+
+func TestBacktestPositionExits(t *testing.T) {
+	start := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	snap := func(minutes int, price float64) model.TokenPriceSnapshot {
+		return model.TokenPriceSnapshot{TS: start.Add(time.Duration(minutes) * time.Minute), PriceUSD: price}
+	}
+
+	tests := []struct {
+		name       string
+		cfg        AutoTradeConfig
+		marks      []model.TokenPriceSnapshot
+		strategies []string
+		qty        float64
+		proceeds   float64
+	}{
+		{
+			name:       "stop-loss",
+			cfg:        AutoTradeConfig{StopLoss: -0.3},
+			marks:      []model.TokenPriceSnapshot{snap(5, 0.8), snap(10, 0.6)},
+			strategies: []string{StrategyStopLoss},
+			proceeds:   0.6,
+		},
+		{
+			name:       "trailing stop from the peak",
+			cfg:        AutoTradeConfig{TrailingStop: 0.2},
+			marks:      []model.TokenPriceSnapshot{snap(5, 2), snap(10, 1.7), snap(15, 1.5)},
+			strategies: []string{StrategyTrailingStop},
+			proceeds:   1.5,
+		},
+		{
+			name:       "max hold",
+			cfg:        AutoTradeConfig{MaxHoldMinutes: 60},
+			marks:      []model.TokenPriceSnapshot{snap(30, 1.1), snap(61, 1.05)},
+			strategies: []string{StrategyMaxHold},
+			proceeds:   1.05,
+		},
+		{
+			name:       "take-profit levels sell in parts",
+			cfg:        AutoTradeConfig{TakeProfitLevels: []float64{0.5, 1}, TakeProfitAmounts: []float64{0.5, 1}},
+			marks:      []model.TokenPriceSnapshot{snap(5, 1.6), snap(10, 1.7), snap(15, 2.2)},
+			strategies: []string{StrategyTakeProfit, StrategyTakeProfit},
+			proceeds:   1.9,
+		},
+		{
+			name:  "no rule hit",
+			cfg:   AutoTradeConfig{StopLoss: -0.5, TakeProfitLevels: []float64{0.5}},
+			marks: []model.TokenPriceSnapshot{snap(5, 1.1), snap(10, 0.9)},
+			qty:   100,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// 1 BNB at $100 buys 100 tokens at $1; no tax, slippage, gas or impact.
+			pos := &backtestPosition{params: &BacktestParams{BNBPriceUSD: 100, Config: tt.cfg}}
+			pos.buy(snap(0, 1), 1, 0)
+			for _, mark := range tt.marks {
+				if pos.qty <= 0 {
+					break
+				}
+				pos.step(&model.Token{}, mark)
+			}
+			strategies := []string{}
+			for _, e := range pos.events {
+				strategies = append(strategies, e.Strategy)
+			}
+			if tt.strategies == nil {
+				tt.strategies = []string{}
+			}
+			if !reflect.DeepEqual(strategies, tt.strategies) {
+				t.Fatalf("exits = %v, want %v", strategies, tt.strategies)
+			}
+			if math.Abs(pos.qty-tt.qty) > 1e-9 || math.Abs(pos.proceeds-tt.proceeds) > 1e-9 {
+				t.Errorf("qty, proceeds = %v, %v, want %v, %v", pos.qty, pos.proceeds, tt.qty, tt.proceeds)
+			}
+		})
+	}
+}
+
+func TestBacktestPositionCosts(t *testing.T) {
+	pos := &backtestPosition{params: &BacktestParams{BNBPriceUSD: 100, Slippage: 0.01, GasBNB: 0.01}, sellTax: 0.1}
+	// $100 against a $100 side of the pool loses half to impact.
+	pos.buy(model.TokenPriceSnapshot{PriceUSD: 1, LiquidityUSD: 200}, 1, 0.05)
+	wantQty := 100 * (1 - 100.0/200) * 0.99 * 0.95
+	if math.Abs(pos.qty-wantQty) > 1e-9 || math.Abs(pos.cost-1.01) > 1e-9 {
+		t.Errorf("qty, cost = %v, %v, want %v, 1.01", pos.qty, pos.cost, wantQty)
+	}
+}
+
+func TestSummarizeBacktest(t *testing.T) {
+	report := &BacktestReport{
+		Trades: []BacktestTrade{
+			{CostBNB: 1, PnLBNB: 0.5, Return: 0.5},
+			{CostBNB: 1, PnLBNB: -0.2, Return: -0.2},
+			{CostBNB: 1, PnLBNB: 0.1, Return: 0.1},
+			{CostBNB: 1, PnLBNB: 0, Return: 0},
+		},
+		Skipped: []BacktestSkip{{}, {}},
+	}
+	got := summarizeBacktest(report, 6)
+	want := BacktestSummary{
+		Signals: 6, Trades: 4, Skipped: 2,
+		Wins: 2, Losses: 2, WinRate: 0.5,
+		TotalCostBNB: 4, TotalPnLBNB: 0.4,
+		AvgReturn: 0.1, MedianReturn: 0.05, BestReturn: 0.5, WorstReturn: -0.2,
+	}
+	near := func(a, b float64) bool { return math.Abs(a-b) < 1e-9 }
+	if got.Signals != want.Signals || got.Trades != want.Trades || got.Skipped != want.Skipped || got.Wins != want.Wins || got.Losses != want.Losses {
+		t.Errorf("counts = %+v, want %+v", got, want)
+	}
+	if !near(got.WinRate, want.WinRate) || !near(got.TotalCostBNB, want.TotalCostBNB) || !near(got.TotalPnLBNB, want.TotalPnLBNB) ||
+		!near(got.AvgReturn, want.AvgReturn) || !near(got.MedianReturn, want.MedianReturn) ||
+		!near(got.BestReturn, want.BestReturn) || !near(got.WorstReturn, want.WorstReturn) {
+		t.Errorf("summary = %+v, want %+v", got, want)
+	}
+
+	if empty := summarizeBacktest(&BacktestReport{}, 3); empty.Signals != 3 || empty.Trades != 0 || empty.WinRate != 0 {
+		t.Errorf("empty summary = %+v", empty)
+	}
+}
+
+func TestBacktestDistribution(t *testing.T) {
+	trades := []BacktestTrade{{Return: -0.6}, {Return: -0.5}, {Return: 0}, {Return: 0.2}, {Return: 1}, {Return: 3}}
+	got := backtestDistribution(trades)
+	want := []int{1, 1, 0, 1, 1, 0, 2}
+	if len(got) != len(want) {
+		t.Fatalf("buckets = %d, want %d", len(got), len(want))
+	}
+	for i, count := range want {
+		if got[i].Count != count {
+			t.Errorf("bucket %s = %d, want %d", got[i].Label, got[i].Count, count)
+		}
+	}
+	if backtestBuckets[0].Count != 0 {
+		t.Error("distribution modified the shared buckets")
+	}
+}
+
+func TestBacktestEquity(t *testing.T) {
+	at := func(h int) time.Time { return time.Date(2026, 10, 1, h, 0, 0, 0, time.UTC) }
+	// Out of exit order on purpose.
+	trades := []BacktestTrade{
+		{ExitAt: at(4), PnLBNB: 5},
+		{ExitAt: at(2), PnLBNB: -3},
+		{ExitAt: at(1), PnLBNB: 2},
+		{ExitAt: at(3), PnLBNB: -1},
+	}
+	var summary BacktestSummary
+	points := backtestEquity(trades, 10, &summary)
+
+	want := []float64{12, 9, 8, 13}
+	for i, equity := range want {
+		if points[i].EquityBNB != equity || !points[i].At.Equal(at(i+1)) {
+			t.Errorf("point %d = %+v, want %v at %s", i, points[i], equity, at(i+1))
+		}
+	}
+	if summary.MaxDrawdownBNB != 4 || math.Abs(summary.MaxDrawdown-4.0/12) > 1e-9 {
+		t.Errorf("drawdown = %v (%v), want 4 (%v)", summary.MaxDrawdownBNB, summary.MaxDrawdown, 4.0/12)
+	}
+}
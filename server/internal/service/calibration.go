@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+)
+
+// Outcome labels a golden dog call can be scored against.
+var calibrationGoodOutcomes = map[string]bool{
+	model.Outcome2x:       true,
+	model.Outcome10x:      true,
+	model.OutcomeSurvived: true,
+}
+
+// CalibrationStats summarizes how a group of analyzed tokens turned out.
+// Precision is the share of the group with a good outcome; recall is the
+// share of every good token that falls in the group.
+type CalibrationStats struct {
+	Count           int     `json:"count"`
+	Good            int     `json:"good"`
+	Precision       float64 `json:"precision"`
+	Recall          float64 `json:"recall"`
+	MedianMaxReturn float64 `json:"medianMaxReturn"`
+	RugRate         float64 `json:"rugRate"`
+}
+
+// CalibrationBucket is a golden dog score range. Stats cover the bucket
+// itself; Threshold covers every token scoring at least MinScore, i.e. the
+// result of calling golden dogs from this score up.
+type CalibrationBucket struct {
+	Range     string           `json:"range"`
+	MinScore  int              `json:"minScore"`
+	MaxScore  int              `json:"maxScore"`
+	Stats     CalibrationStats `json:"stats"`
+	Threshold CalibrationStats `json:"threshold"`
+}
+
+type CalibrationLevel struct {
+	RiskLevel string           `json:"riskLevel"`
+	Stats     CalibrationStats `json:"stats"`
+}
+
+// CalibrationPoint compares the good outcome rate a score bucket predicts,
+// reading golden dog scores as probabilities, with the observed rate.
+type CalibrationPoint struct {
+	Range     string  `json:"range"`
+	Predicted float64 `json:"predicted"`
+	Observed  float64 `json:"observed"`
+	Count     int     `json:"count"`
+}
+
+type CalibrationReport struct {
+	Horizon      string    `json:"horizon"`
+	GoodOutcome  string    `json:"goodOutcome"`
+	From         time.Time `json:"from"`
+	To           time.Time `json:"to"`
+	Source       string    `json:"source,omitempty"`
+	AgentID      string    `json:"agentId,omitempty"`
+	ModelVersion string    `json:"modelVersion,omitempty"`
+	BucketSize   int       `json:"bucketSize"`
+	// BaseRate is the good outcome share over every sample.
+	BaseRate float64 `json:"baseRate"`
+	// BrierScore is the mean squared error of scores read as probabilities.
+	BrierScore       float64             `json:"brierScore"`
+	Overall          CalibrationStats    `json:"overall"`
+	GoldenDogCalls   CalibrationStats    `json:"goldenDogCalls"`
+	Buckets          []CalibrationBucket `json:"buckets"`
+	RiskLevels       []CalibrationLevel  `json:"riskLevels"`
+	CalibrationCurve []CalibrationPoint  `json:"calibrationCurve"`
+}
+
+type calibrationSample struct {
+	score       int
+	riskLevel   string
+	isGoldenDog bool
+	good        bool
+	rugged      bool
+	maxReturn   float64
+}
+
+// ValidOutcomeHorizon reports whether outcomes are labeled at horizon.
+func ValidOutcomeHorizon(horizon string) bool {
+	for _, h := range outcomeHorizons {
+		if h.Name == horizon {
+			return true
+		}
+	}
+	return false
+}
+
+// BuildCalibrationReport scores the first analysis of every token matching
+// filter against its outcome at filter.Horizon. A token counts as good when
+// it carries the goodOutcome label.
+func BuildCalibrationReport(ctx context.Context, repo *repository.Repository, filter repository.OutcomeFilter, bucketSize int, goodOutcome string) (*CalibrationReport, error) {
+	if !ValidOutcomeHorizon(filter.Horizon) {
+		return nil, fmt.Errorf("unknown horizon %q", filter.Horizon)
+	}
+	if !calibrationGoodOutcomes[goodOutcome] {
+		return nil, fmt.Errorf("good outcome must be 2x, 10x or survived")
+	}
+	if bucketSize <= 0 || bucketSize > 100 {
+		bucketSize = 10
+	}
+	rows, err := repo.ListScoredOutcomes(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := make([]calibrationSample, 0, len(rows))
+	for _, row := range rows {
+		var labels []string
+		_ = json.Unmarshal(row.Labels, &labels)
+		sample := calibrationSample{
+			score:       row.GoldenDogScore,
+			riskLevel:   row.RiskLevel,
+			isGoldenDog: row.IsGoldenDog,
+			maxReturn:   row.MaxReturn,
+		}
+		for _, label := range labels {
+			switch label {
+			case goodOutcome:
+				sample.good = true
+			case model.OutcomeRugged, model.OutcomeHoneypot:
+				sample.rugged = true
+			}
+		}
+		samples = append(samples, sample)
+	}
+	return calibrationReport(samples, bucketSize, filter, goodOutcome), nil
+}
+
+func calibrationReport(samples []calibrationSample, bucketSize int, filter repository.OutcomeFilter, goodOutcome string) *CalibrationReport {
+	totalGood := 0
+	brier := 0.0
+	for _, s := range samples {
+		outcome := 0.0
+		if s.good {
+			totalGood++
+			outcome = 1
+		}
+		predicted := float64(clampScore(s.score)) / 100
+		brier += (predicted - outcome) * (predicted - outcome)
+	}
+
+	report := &CalibrationReport{
+		Horizon:          filter.Horizon,
+		GoodOutcome:      goodOutcome,
+		From:             filter.From,
+		To:               filter.To,
+		Source:           filter.Source,
+		AgentID:          filter.AgentID,
+		ModelVersion:     filter.ModelVersion,
+		BucketSize:       bucketSize,
+		Overall:          calibrationStats(samples, totalGood),
+		Buckets:          []CalibrationBucket{},
+		RiskLevels:       []CalibrationLevel{},
+		CalibrationCurve: []CalibrationPoint{},
+	}
+	if len(samples) > 0 {
+		report.BaseRate = float64(totalGood) / float64(len(samples))
+		report.BrierScore = brier / float64(len(samples))
+	}
+
+	calls := []calibrationSample{}
+	levels := map[string][]calibrationSample{}
+	for _, s := range samples {
+		if s.isGoldenDog {
+			calls = append(calls, s)
+		}
+		levels[s.riskLevel] = append(levels[s.riskLevel], s)
+	}
+	report.GoldenDogCalls = calibrationStats(calls, totalGood)
+
+	levelNames := make([]string, 0, len(levels))
+	for level := range levels {
+		levelNames = append(levelNames, level)
+	}
+	sort.Strings(levelNames)
+	for _, level := range levelNames {
+		report.RiskLevels = append(report.RiskLevels, CalibrationLevel{RiskLevel: level, Stats: calibrationStats(levels[level], totalGood)})
+	}
+
+	for start := 0; start <= 100; start += bucketSize {
+		end := min(start+bucketSize-1, 100)
+		var in, atLeast []calibrationSample
+		scoreSum := 0
+		for _, s := range samples {
+			score := clampScore(s.score)
+			if score >= start {
+				atLeast = append(atLeast, s)
+				if score <= end {
+					in = append(in, s)
+					scoreSum += score
+				}
+			}
+		}
+		bucket := CalibrationBucket{
+			Range:     fmt.Sprintf("%d-%d", start, end),
+			MinScore:  start,
+			MaxScore:  end,
+			Stats:     calibrationStats(in, totalGood),
+			Threshold: calibrationStats(atLeast, totalGood),
+		}
+		report.Buckets = append(report.Buckets, bucket)
+		if len(in) > 0 {
+			report.CalibrationCurve = append(report.CalibrationCurve, CalibrationPoint{
+				Range:     bucket.Range,
+				Predicted: float64(scoreSum) / float64(len(in)) / 100,
+				Observed:  bucket.Stats.Precision,
+				Count:     len(in),
+			})
+		}
+	}
+	return report
+}
+
+func calibrationStats(samples []calibrationSample, totalGood int) CalibrationStats {
+	stats := CalibrationStats{Count: len(samples)}
+	if len(samples) == 0 {
+		return stats
+	}
+	returns := make([]float64, 0, len(samples))
+	rugged := 0
+	for _, s := range samples {
+		if s.good {
+			stats.Good++
+		}
+		if s.rugged {
+			rugged++
+		}
+		returns = append(returns, s.maxReturn)
+	}
+	stats.Precision = float64(stats.Good) / float64(len(samples))
+	if totalGood > 0 {
+		stats.Recall = float64(stats.Good) / float64(totalGood)
+	}
+	stats.RugRate = float64(rugged) / float64(len(samples))
+
+	sort.Float64s(returns)
+	mid := len(returns) / 2
+	if len(returns)%2 == 0 {
+		stats.MedianMaxReturn = (returns[mid-1] + returns[mid]) / 2
+	} else {
+		stats.MedianMaxReturn = returns[mid]
+	}
+	stats.MedianMaxReturn = math.Round(stats.MedianMaxReturn*1000) / 1000
+	return stats
+}
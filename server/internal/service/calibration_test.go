@@ -0,0 +1,110 @@
+package service
+
+import (
+	"math"
+	"testing"
+
+	"easymeme/internal/repository"
+)
+
+func TestCalibrationReport(t *testing.T) {
+	samples := []calibrationSample{
+		{score: 20, riskLevel: "warning", rugged: true, maxReturn: 0.5},
+		{score: 40, riskLevel: "danger", rugged: true, maxReturn: 0.2},
+		{score: 60, riskLevel: "safe", isGoldenDog: true, good: true, maxReturn: 3},
+		{score: 80, riskLevel: "safe", isGoldenDog: true, good: true, maxReturn: 5},
+		{score: 80, riskLevel: "safe", isGoldenDog: true, maxReturn: 1},
+		{score: 100, riskLevel: "safe", isGoldenDog: true, good: true, maxReturn: 12},
+	}
+	r := calibrationReport(samples, 50, repository.OutcomeFilter{Horizon: "24h"}, "2x")
+
+	near := func(got, want float64) bool { return math.Abs(got-want) < 1e-9 }
+	if !near(r.BaseRate, 0.5) {
+		t.Errorf("BaseRate = %v, want 0.5", r.BaseRate)
+	}
+	if !near(r.BrierScore, 1.04/6) {
+		t.Errorf("BrierScore = %v, want %v", r.BrierScore, 1.04/6)
+	}
+	if r.Overall.Count != 6 || r.Overall.Good != 3 || !near(r.Overall.MedianMaxReturn, 2) {
+		t.Errorf("Overall = %+v", r.Overall)
+	}
+	if r.GoldenDogCalls.Count != 4 || !near(r.GoldenDogCalls.Precision, 0.75) || !near(r.GoldenDogCalls.Recall, 1) {
+		t.Errorf("GoldenDogCalls = %+v", r.GoldenDogCalls)
+	}
+
+	buckets := []struct {
+		rng       string
+		stats     CalibrationStats
+		threshold CalibrationStats
+	}{
+		{
+			rng:       "0-49",
+			stats:     CalibrationStats{Count: 2, MedianMaxReturn: 0.35, RugRate: 1},
+			threshold: CalibrationStats{Count: 6, Good: 3, Precision: 0.5, Recall: 1, MedianMaxReturn: 2, RugRate: 2.0 / 6},
+		},
+		{
+			rng:       "50-99",
+			stats:     CalibrationStats{Count: 3, Good: 2, Precision: 2.0 / 3, Recall: 2.0 / 3, MedianMaxReturn: 3},
+			threshold: CalibrationStats{Count: 4, Good: 3, Precision: 0.75, Recall: 1, MedianMaxReturn: 4},
+		},
+		{
+			rng:       "100-100",
+			stats:     CalibrationStats{Count: 1, Good: 1, Precision: 1, Recall: 1.0 / 3, MedianMaxReturn: 12},
+			threshold: CalibrationStats{Count: 1, Good: 1, Precision: 1, Recall: 1.0 / 3, MedianMaxReturn: 12},
+		},
+	}
+	if len(r.Buckets) != len(buckets) {
+		t.Fatalf("buckets = %d, want %d", len(r.Buckets), len(buckets))
+	}
+	sameStats := func(got, want CalibrationStats) bool {
+		return got.Count == want.Count && got.Good == want.Good && near(got.Precision, want.Precision) &&
+			near(got.Recall, want.Recall) && near(got.MedianMaxReturn, want.MedianMaxReturn) && near(got.RugRate, want.RugRate)
+	}
+	for i, want := range buckets {
+		got := r.Buckets[i]
+		if got.Range != want.rng {
+			t.Errorf("bucket %d range = %s, want %s", i, got.Range, want.rng)
+		}
+		if !sameStats(got.Stats, want.stats) {
+			t.Errorf("bucket %s stats = %+v, want %+v", want.rng, got.Stats, want.stats)
+		}
+		if !sameStats(got.Threshold, want.threshold) {
+			t.Errorf("bucket %s threshold = %+v, want %+v", want.rng, got.Threshold, want.threshold)
+		}
+	}
+
+	curve := []CalibrationPoint{
+		{Range: "0-49", Predicted: 0.3, Observed: 0, Count: 2},
+		{Range: "50-99", Predicted: 2.2 / 3, Observed: 2.0 / 3, Count: 3},
+		{Range: "100-100", Predicted: 1, Observed: 1, Count: 1},
+	}
+	if len(r.CalibrationCurve) != len(curve) {
+		t.Fatalf("curve = %+v", r.CalibrationCurve)
+	}
+	for i, want := range curve {
+		got := r.CalibrationCurve[i]
+		if got.Range != want.Range || got.Count != want.Count || !near(got.Predicted, want.Predicted) || !near(got.Observed, want.Observed) {
+			t.Errorf("curve[%d] = %+v, want %+v", i, got, want)
+		}
+	}
+
+	levels := []string{"danger", "safe", "warning"}
+	if len(r.RiskLevels) != len(levels) {
+		t.Fatalf("risk levels = %+v", r.RiskLevels)
+	}
+	for i, level := range levels {
+		if r.RiskLevels[i].RiskLevel != level {
+			t.Errorf("risk level %d = %s, want %s", i, r.RiskLevels[i].RiskLevel, level)
+		}
+	}
+}
+
+func TestCalibrationReportEmpty(t *testing.T) {
+	r := calibrationReport(nil, 10, repository.OutcomeFilter{Horizon: "24h"}, "2x")
+	if r.BaseRate != 0 || r.BrierScore != 0 || r.Overall.Count != 0 {
+		t.Errorf("empty report = %+v", r)
+	}
+	if len(r.Buckets) != 11 || len(r.CalibrationCurve) != 0 {
+		t.Errorf("buckets, curve = %d, %d, want 11, 0", len(r.Buckets), len(r.CalibrationCurve))
+	}
+}
@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+	"easymeme/pkg/ethereum"
+)
+
+const HaltScopeGlobal = "global"
+
+// userHaltPrefix marks per-user halt scopes so no user ID can collide with
+// HaltScopeGlobal.
+const userHaltPrefix = "user:"
+
+// UserHaltScope is the halt scope of one user.
+func UserHaltScope(userID string) string {
+	return userHaltPrefix + userID
+}
+
+// haltScopeUser returns the user a per-user scope belongs to.
+func haltScopeUser(scope string) (string, bool) {
+	if !strings.HasPrefix(scope, userHaltPrefix) {
+		return "", false
+	}
+	return strings.TrimPrefix(scope, userHaltPrefix), true
+}
+
+const (
+	HaltKindKillSwitch          = "kill_switch"
+	HaltKindConsecutiveFailures = "consecutive_failures"
+	HaltKindLossRate            = "loss_rate"
+	HaltKindRPCDegraded         = "rpc_degraded"
+	HaltKindDailyLoss           = "daily_loss"
+)
+
+const rpcProbeInterval = 30 * time.Second
+
+func IsValidHaltKind(kind string) bool {
+	switch kind {
+	case HaltKindKillSwitch, HaltKindConsecutiveFailures, HaltKindLossRate, HaltKindRPCDegraded, HaltKindDailyLoss:
+		return true
+	}
+	return false
+}
+
+// BreakerConfig holds the trip thresholds. A zero value disables that breaker.
+type BreakerConfig struct {
+	MaxConsecutiveFailures int
+	// LossRate trips when this fraction of the last LossWindow sells lost money.
+	LossRate   float64
+	LossWindow int
+	// RPCFailures trips after this many consecutive failed or slow RPC probes.
+	RPCFailures   int
+	RPCMaxLatency time.Duration
+}
+
+// TradingHalted is returned when a kill switch or breaker blocks a trade.
+type TradingHalted struct {
+	Scope  string
+	Kind   string
+	Reason string
+}
+
+func (e *TradingHalted) Error() string {
+	if e.Reason == "" {
+		return "trading halted: " + e.Kind
+	}
+	return "trading halted: " + e.Kind + ": " + e.Reason
+}
+
+// CircuitBreaker holds the kill switches and automatic breakers that block
+// every managed trade. Halts are persisted and stay active until reset.
+type CircuitBreaker struct {
+	client *ethereum.Client
+	repo   *repository.Repository
+	hub    Broadcaster
+	cfg    BreakerConfig
+
+	mu          sync.RWMutex
+	halts       map[string]model.TradingHalt // scope/kind -> active halt
+	failures    map[string]int               // consecutive failed transactions per user
+	outcomes    map[string][]bool            // recent sells per user, true when the sell lost money
+	rpcFailures int
+	rpcLatency  time.Duration
+	rpcError    string
+	rpcProbedAt time.Time
+}
+
+func NewCircuitBreaker(client *ethereum.Client, repo *repository.Repository, hub Broadcaster, cfg BreakerConfig) *CircuitBreaker {
+	return &CircuitBreaker{
+		client:   client,
+		repo:     repo,
+		hub:      hub,
+		cfg:      cfg,
+		halts:    map[string]model.TradingHalt{},
+		failures: map[string]int{},
+		outcomes: map[string][]bool{},
+	}
+}
+
+// Start restores active halts and begins probing the RPC endpoint.
+func (b *CircuitBreaker) Start(ctx context.Context) error {
+	halts, err := b.repo.ListActiveTradingHalts(ctx)
+	if err != nil {
+		return fmt.Errorf("load trading halts: %w", err)
+	}
+	b.mu.Lock()
+	for _, halt := range halts {
+		b.halts[haltKey(halt.Scope, halt.Kind)] = halt
+	}
+	b.mu.Unlock()
+	if len(halts) > 0 {
+		log.Printf("[CircuitBreaker] restored %d active halts", len(halts))
+	}
+
+	log.Println("[CircuitBreaker] Started")
+	go b.probeLoop(ctx)
+	return nil
+}
+
+// Check returns a *TradingHalted when trading is halted globally or for the user.
+func (b *CircuitBreaker) Check(userID string) error {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	for _, halt := range b.halts {
+		if halt.Scope == HaltScopeGlobal || halt.Scope == UserHaltScope(userID) {
+			return &TradingHalted{Scope: halt.Scope, Kind: halt.Kind, Reason: halt.Reason}
+		}
+	}
+	return nil
+}
+
+// Trip activates a halt. Tripping an already active scope/kind is a no-op.
+func (b *CircuitBreaker) Trip(ctx context.Context, scope, kind, reason, actor string) (*model.TradingHalt, error) {
+	key := haltKey(scope, kind)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if existing, ok := b.halts[key]; ok {
+		return &existing, nil
+	}
+	halt := model.TradingHalt{
+		Scope:     scope,
+		Kind:      kind,
+		Reason:    reason,
+		Active:    true,
+		TrippedBy: actor,
+		TrippedAt: time.Now().UTC(),
+	}
+	if err := b.repo.CreateTradingHalt(ctx, &halt); err != nil {
+		return nil, err
+	}
+	b.halts[key] = halt
+	log.Printf("[CircuitBreaker] tripped scope=%s kind=%s by=%s: %s", scope, kind, actor, reason)
+	b.broadcast("tripped", halt.Scope, halt.Kind, halt.Reason)
+	return &halt, nil
+}
+
+// Reset clears the active halts of a scope. An empty kind clears all of them.
+// Breaker counters are cleared too so a reset starts from a clean slate.
+func (b *CircuitBreaker) Reset(ctx context.Context, scope, kind, actor string) (int64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	n, err := b.repo.ResetTradingHalts(ctx, scope, kind, actor, time.Now().UTC())
+	if err != nil {
+		return 0, err
+	}
+	for key, halt := range b.halts {
+		if halt.Scope == scope && (kind == "" || halt.Kind == kind) {
+			delete(b.halts, key)
+		}
+	}
+	if scope == HaltScopeGlobal {
+		if kind == "" || kind == HaltKindRPCDegraded {
+			b.rpcFailures = 0
+		}
+	} else if userID, ok := haltScopeUser(scope); ok {
+		if kind == "" || kind == HaltKindConsecutiveFailures {
+			delete(b.failures, userID)
+		}
+		if kind == "" || kind == HaltKindLossRate {
+			delete(b.outcomes, userID)
+		}
+	}
+	log.Printf("[CircuitBreaker] reset scope=%s kind=%s by=%s (%d halts)", scope, kind, actor, n)
+	b.broadcast("reset", scope, kind, "")
+	return n, nil
+}
+
+// RecordTransaction feeds a submitted transaction into the consecutive failure breaker.
+func (b *CircuitBreaker) RecordTransaction(ctx context.Context, userID string, success bool) {
+	if b.cfg.MaxConsecutiveFailures <= 0 {
+		return
+	}
+	b.mu.Lock()
+	if success {
+		delete(b.failures, userID)
+		b.mu.Unlock()
+		return
+	}
+	b.failures[userID]++
+	count := b.failures[userID]
+	b.mu.Unlock()
+
+	if count >= b.cfg.MaxConsecutiveFailures {
+		b.trip(ctx, UserHaltScope(userID), HaltKindConsecutiveFailures, fmt.Sprintf("%d consecutive failed transactions", count))
+	}
+}
+
+// RecordSell feeds a settled sell into the loss rate and daily loss breakers.
+// profitLoss is the realized return ratio of the sell.
+func (b *CircuitBreaker) RecordSell(ctx context.Context, userID string, profitLoss float64, maxDailyLoss float64) {
+	if b.cfg.LossRate > 0 && b.cfg.LossWindow > 0 {
+		b.mu.Lock()
+		window := append(b.outcomes[userID], profitLoss < 0)
+		if len(window) > b.cfg.LossWindow {
+			window = window[len(window)-b.cfg.LossWindow:]
+		}
+		b.outcomes[userID] = window
+		losses := 0
+		for _, lost := range window {
+			if lost {
+				losses++
+			}
+		}
+		b.mu.Unlock()
+
+		if len(window) == b.cfg.LossWindow && float64(losses)/float64(len(window)) >= b.cfg.LossRate {
+			b.trip(ctx, UserHaltScope(userID), HaltKindLossRate, fmt.Sprintf("%d of the last %d sells lost money", losses, len(window)))
+		}
+	}
+
+	if maxDailyLoss > 0 {
+		loss, err := DailyLoss(ctx, b.repo, userID)
+		if err != nil {
+			log.Printf("[CircuitBreaker] daily loss user=%s err=%v", userID, err)
+			return
+		}
+		if loss.InexactFloat64() > maxDailyLoss {
+			b.trip(ctx, UserHaltScope(userID), HaltKindDailyLoss, fmt.Sprintf("daily loss %s exceeds %g", loss.StringFixed(4), maxDailyLoss))
+		}
+	}
+}
+
+func (b *CircuitBreaker) trip(ctx context.Context, scope, kind, reason string) {
+	if _, err := b.Trip(ctx, scope, kind, reason, "system"); err != nil {
+		log.Printf("[CircuitBreaker] trip scope=%s kind=%s failed: %v", scope, kind, err)
+	}
+}
+
+func (b *CircuitBreaker) probeLoop(ctx context.Context) {
+	ticker := time.NewTicker(rpcProbeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			b.probeRPC(ctx)
+		}
+	}
+}
+
+func (b *CircuitBreaker) probeRPC(ctx context.Context) {
+	probeCtx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	start := time.Now()
+	_, err := b.client.LatestBlockNumber(probeCtx)
+	latency := time.Since(start)
+
+	b.mu.Lock()
+	b.rpcLatency = latency
+	b.rpcProbedAt = time.Now().UTC()
+	switch {
+	case err != nil:
+		b.rpcError = err.Error()
+		b.rpcFailures++
+	case b.cfg.RPCMaxLatency > 0 && latency > b.cfg.RPCMaxLatency:
+		b.rpcError = fmt.Sprintf("latency %s above %s", latency.Round(time.Millisecond), b.cfg.RPCMaxLatency)
+		b.rpcFailures++
+	default:
+		b.rpcError = ""
+		b.rpcFailures = 0
+	}
+	failures := b.rpcFailures
+	reason := b.rpcError
+	b.mu.Unlock()
+
+	if b.cfg.RPCFailures > 0 && failures >= b.cfg.RPCFailures {
+		b.trip(ctx, HaltScopeGlobal, HaltKindRPCDegraded, fmt.Sprintf("%d consecutive RPC probe failures: %s", failures, reason))
+	}
+}
+
+// ActiveHalts returns the currently active halts of every scope.
+func (b *CircuitBreaker) ActiveHalts() []model.TradingHalt {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	halts := make([]model.TradingHalt, 0, len(b.halts))
+	for _, halt := range b.halts {
+		halts = append(halts, halt)
+	}
+	return halts
+}
+
+// HealthStatus reports the global trading state for the public health check.
+// Per-user halts are left out; they are listed on the admin endpoints.
+func (b *CircuitBreaker) HealthStatus() map[string]interface{} {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	halts := []model.TradingHalt{}
+	for _, halt := range b.halts {
+		if halt.Scope == HaltScopeGlobal {
+			halts = append(halts, halt)
+		}
+	}
+	return map[string]interface{}{
+		"halted": len(halts) > 0,
+		"halts":  halts,
+		"rpc": map[string]interface{}{
+			"consecutiveFailures": b.rpcFailures,
+			"latencyMs":           b.rpcLatency.Milliseconds(),
+			"lastError":           b.rpcError,
+			"probedAt":            b.rpcProbedAt,
+		},
+		"thresholds": map[string]interface{}{
+			"maxConsecutiveFailures": b.cfg.MaxConsecutiveFailures,
+			"lossRate":               b.cfg.LossRate,
+			"lossWindow":             b.cfg.LossWindow,
+			"rpcFailures":            b.cfg.RPCFailures,
+			"rpcMaxLatencyMs":        b.cfg.RPCMaxLatency.Milliseconds(),
+		},
+	}
+}
+
+func (b *CircuitBreaker) broadcast(event, scope, kind, reason string) {
+	if b.hub == nil {
+		return
+	}
+	payload := map[string]interface{}{
+		"type":   "trading_halt",
+		"event":  event,
+		"scope":  scope,
+		"kind":   kind,
+		"reason": reason,
+	}
+	if userID, ok := haltScopeUser(scope); ok {
+		b.hub.BroadcastToUser(userID, payload)
+		return
+	}
+	b.hub.Broadcast(payload)
+}
+
+func haltKey(scope, kind string) string {
+	return scope + "/" + kind
+}
@@ -0,0 +1,264 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+)
+
+const (
+	// Agents disagree when their scores spread wider than this.
+	consensusScoreSpread = 30
+	// An agent without an accuracy record counts as a coin flip.
+	defaultAgentWeight = 0.5
+
+	agentAccuracyInterval = time.Hour
+	// A token was a golden dog when the price reached agentWinMultiple
+	// times the price at analysis within agentAccuracyHorizon.
+	agentAccuracyHorizon = 24 * time.Hour
+	agentWinMultiple     = 2.0
+)
+
+// AgentVerdict is one agent's latest analysis of a token and its weight in the
+// consensus.
+type AgentVerdict struct {
+	AgentID        string    `json:"agentId"`
+	AnalysisID     string    `json:"analysisId"`
+	Model          string    `json:"model"`
+	ModelVersion   string    `json:"modelVersion"`
+	RiskScore      int       `json:"riskScore"`
+	RiskLevel      string    `json:"riskLevel"`
+	IsGoldenDog    bool      `json:"isGoldenDog"`
+	GoldenDogScore int       `json:"goldenDogScore"`
+	HoneypotRisk   string    `json:"honeypotRisk,omitempty"`
+	Weight         float64   `json:"weight"`
+	AnalyzedAt     time.Time `json:"analyzedAt"`
+}
+
+// Consensus merges the latest verdict of every agent, weighted by each agent's
+// historical accuracy.
+type Consensus struct {
+	RiskScore      int    `json:"riskScore"`
+	RiskLevel      string `json:"riskLevel"`
+	IsGoldenDog    bool   `json:"isGoldenDog"`
+	GoldenDogScore int    `json:"goldenDogScore"`
+	// GoldenDogVote is the weighted share of agents calling a golden dog.
+	GoldenDogVote float64 `json:"goldenDogVote"`
+	// IsHoneypot is a weighted majority of the agents that assessed the
+	// honeypot risk, HoneypotAgents of them; HIGH counts as a honeypot call.
+	IsHoneypot          bool           `json:"isHoneypot"`
+	HoneypotVote        float64        `json:"honeypotVote"`
+	HoneypotAgents      int            `json:"honeypotAgents"`
+	Agents              int            `json:"agents"`
+	Disagreement        bool           `json:"disagreement"`
+	DisagreementReasons []string       `json:"disagreementReasons"`
+	Verdicts            []AgentVerdict `json:"verdicts"`
+	ComputedAt          time.Time      `json:"computedAt"`
+}
+
+// BuildConsensus weighs each verdict by weights[agentID]. Scores are weighted
+// means, the golden dog and honeypot calls are weighted majorities, and the
+// risk level is the weighted plurality with ties going to the more severe
+// level.
+func BuildConsensus(latest []model.TokenAnalysis, weights map[string]float64, now time.Time) Consensus {
+	c := Consensus{
+		DisagreementReasons: []string{},
+		Verdicts:            make([]AgentVerdict, 0, len(latest)),
+		ComputedAt:          now,
+	}
+	if len(latest) == 0 {
+		return c
+	}
+
+	var totalWeight, riskSum, goldenSum, goldenVote float64
+	var honeypotWeight, honeypotVote float64
+	levelWeight := map[string]float64{}
+	minRisk, maxRisk := math.MaxInt, math.MinInt
+	minGolden, maxGolden := math.MaxInt, math.MinInt
+	calls := map[bool]int{}
+	honeypotCalls := map[bool]int{}
+	for _, a := range latest {
+		w, ok := weights[a.AgentID]
+		if !ok {
+			w = defaultAgentWeight
+		}
+		c.Verdicts = append(c.Verdicts, AgentVerdict{
+			AgentID:        a.AgentID,
+			AnalysisID:     a.ID,
+			Model:          a.Model,
+			ModelVersion:   a.ModelVersion,
+			RiskScore:      a.RiskScore,
+			RiskLevel:      a.RiskLevel,
+			IsGoldenDog:    a.IsGoldenDog,
+			GoldenDogScore: a.GoldenDogScore,
+			HoneypotRisk:   a.HoneypotRisk,
+			Weight:         w,
+			AnalyzedAt:     a.CreatedAt,
+		})
+		totalWeight += w
+		riskSum += w * float64(a.RiskScore)
+		goldenSum += w * float64(a.GoldenDogScore)
+		if a.IsGoldenDog {
+			goldenVote += w
+		}
+		levelWeight[a.RiskLevel] += w
+		calls[a.IsGoldenDog]++
+		if a.HoneypotRisk != "" {
+			honeypot := strings.EqualFold(a.HoneypotRisk, "HIGH")
+			honeypotWeight += w
+			if honeypot {
+				honeypotVote += w
+			}
+			honeypotCalls[honeypot]++
+		}
+		minRisk, maxRisk = min(minRisk, a.RiskScore), max(maxRisk, a.RiskScore)
+		minGolden, maxGolden = min(minGolden, a.GoldenDogScore), max(maxGolden, a.GoldenDogScore)
+	}
+	sort.Slice(c.Verdicts, func(i, j int) bool {
+		return c.Verdicts[i].Weight > c.Verdicts[j].Weight
+	})
+
+	c.Agents = len(latest)
+	if totalWeight <= 0 {
+		// Every agent has zero accuracy: fall back to an unweighted view.
+		totalWeight = float64(len(latest))
+		riskSum, goldenSum, goldenVote = 0, 0, 0
+		honeypotWeight, honeypotVote = 0, 0
+		for _, a := range latest {
+			riskSum += float64(a.RiskScore)
+			goldenSum += float64(a.GoldenDogScore)
+			if a.IsGoldenDog {
+				goldenVote++
+			}
+			if a.HoneypotRisk != "" {
+				honeypotWeight++
+				if strings.EqualFold(a.HoneypotRisk, "HIGH") {
+					honeypotVote++
+				}
+			}
+		}
+	}
+	c.RiskScore = int(math.Round(riskSum / totalWeight))
+	c.GoldenDogScore = int(math.Round(goldenSum / totalWeight))
+	c.GoldenDogVote = goldenVote / totalWeight
+	c.IsGoldenDog = c.GoldenDogVote > 0.5
+	c.RiskLevel = pluralityLevel(levelWeight)
+	c.HoneypotAgents = honeypotCalls[true] + honeypotCalls[false]
+	if honeypotWeight > 0 {
+		c.HoneypotVote = honeypotVote / honeypotWeight
+		c.IsHoneypot = c.HoneypotVote > 0.5
+	}
+
+	if calls[true] > 0 && calls[false] > 0 {
+		c.DisagreementReasons = append(c.DisagreementReasons, fmt.Sprintf("%d of %d agents call a golden dog", calls[true], c.Agents))
+	}
+	if honeypotCalls[true] > 0 && honeypotCalls[false] > 0 {
+		c.DisagreementReasons = append(c.DisagreementReasons, fmt.Sprintf("%d of %d agents call a honeypot", honeypotCalls[true], c.HoneypotAgents))
+	}
+	if len(levelWeight) > 1 {
+		levels := make([]string, 0, len(levelWeight))
+		for level := range levelWeight {
+			levels = append(levels, level)
+		}
+		sort.Strings(levels)
+		c.DisagreementReasons = append(c.DisagreementReasons, "risk levels differ: "+strings.Join(levels, ", "))
+	}
+	if maxRisk-minRisk > consensusScoreSpread {
+		c.DisagreementReasons = append(c.DisagreementReasons, fmt.Sprintf("risk scores range %d-%d", minRisk, maxRisk))
+	}
+	if maxGolden-minGolden > consensusScoreSpread {
+		c.DisagreementReasons = append(c.DisagreementReasons, fmt.Sprintf("golden dog scores range %d-%d", minGolden, maxGolden))
+	}
+	c.Disagreement = len(c.DisagreementReasons) > 0
+	return c
+}
+
+func pluralityLevel(levelWeight map[string]float64) string {
+	severity := map[string]int{string(RiskSafe): 0, string(RiskWarning): 1, string(RiskDanger): 2}
+	best, bestWeight := "", -1.0
+	for level, w := range levelWeight {
+		if w > bestWeight || (w == bestWeight && severity[level] > severity[best]) {
+			best, bestWeight = level, w
+		}
+	}
+	return best
+}
+
+// AgentWeights returns every known agent's consensus weight.
+func AgentWeights(ctx context.Context, repo *repository.Repository) (map[string]float64, error) {
+	agents, err := repo.ListAnalysisAgents(ctx)
+	if err != nil {
+		return nil, err
+	}
+	weights := make(map[string]float64, len(agents))
+	for _, agent := range agents {
+		weights[agent.AgentID] = agent.Accuracy
+	}
+	return weights, nil
+}
+
+// AgentAccuracy periodically rescores every agent's past verdicts against
+// the price snapshots that followed them.
+type AgentAccuracy struct {
+	repo *repository.Repository
+}
+
+func NewAgentAccuracy(repo *repository.Repository) *AgentAccuracy {
+	return &AgentAccuracy{repo: repo}
+}
+
+func (a *AgentAccuracy) Start(ctx context.Context) {
+	log.Println("[AgentAccuracy] Started")
+	go func() {
+		a.refresh(ctx)
+		ticker := time.NewTicker(agentAccuracyInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				a.refresh(ctx)
+			}
+		}
+	}()
+}
+
+func (a *AgentAccuracy) refresh(ctx context.Context) {
+	counts, err := a.repo.CountAgentCalls(ctx, agentAccuracyHorizon, agentWinMultiple)
+	if err != nil {
+		log.Printf("[AgentAccuracy] refresh err=%v", err)
+		return
+	}
+	for _, c := range counts {
+		precision, recall, weight := agentWeight(c)
+		agent := &model.AnalysisAgent{
+			AgentID:   c.AgentID,
+			Samples:   c.TruePositives + c.FalsePositives + c.TrueNegatives + c.FalseNegatives,
+			Correct:   c.TruePositives + c.TrueNegatives,
+			Precision: precision,
+			Recall:    recall,
+			Accuracy:  weight,
+		}
+		if err := a.repo.UpdateAnalysisAgentAccuracy(ctx, agent); err != nil {
+			log.Printf("[AgentAccuracy] update agent=%s err=%v", c.AgentID, err)
+		}
+	}
+}
+
+// agentWeight is the F1 score of an agent's golden dog calls. Precision and
+// recall are Laplace smoothed, so an agent without samples weighs 0.5, and
+// one that never calls a golden dog weighs little however many of its "no"
+// calls were right.
+func agentWeight(c repository.AgentCallCounts) (precision, recall, weight float64) {
+	precision = float64(c.TruePositives+1) / float64(c.TruePositives+c.FalsePositives+2)
+	recall = float64(c.TruePositives+1) / float64(c.TruePositives+c.FalseNegatives+2)
+	return precision, recall, 2 * precision * recall / (precision + recall)
+}
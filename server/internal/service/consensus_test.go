@@ -0,0 +1,148 @@
+package service
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+)
+
+func TestBuildConsensus(t *testing.T) {
+	now := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	verdict := func(agent string, risk int, level string, golden bool, score int, honeypot string) model.TokenAnalysis {
+		return model.TokenAnalysis{AgentID: agent, RiskScore: risk, RiskLevel: level, IsGoldenDog: golden, GoldenDogScore: score, HoneypotRisk: honeypot, CreatedAt: now}
+	}
+
+	tests := []struct {
+		name         string
+		latest       []model.TokenAnalysis
+		weights      map[string]float64
+		golden       bool
+		goldenScore  int
+		riskLevel    string
+		honeypot     bool
+		honeypotVote int // agents that assessed the honeypot risk
+		disagreement bool
+	}{
+		{
+			name:        "single agent",
+			latest:      []model.TokenAnalysis{verdict("a", 70, "safe", true, 80, "LOW")},
+			weights:     map[string]float64{"a": 0.9},
+			golden:      true,
+			goldenScore: 80, riskLevel: "safe", honeypotVote: 1,
+		},
+		{
+			name: "accurate agent outweighs two weak ones",
+			latest: []model.TokenAnalysis{
+				verdict("strong", 70, "safe", true, 90, ""),
+				verdict("weak1", 40, "warning", false, 20, ""),
+				verdict("weak2", 40, "warning", false, 20, ""),
+			},
+			weights:     map[string]float64{"strong": 0.8, "weak1": 0.1, "weak2": 0.1},
+			golden:      true,
+			goldenScore: 76, riskLevel: "safe",
+			disagreement: true,
+		},
+		{
+			name: "unknown agents weigh as coin flips",
+			latest: []model.TokenAnalysis{
+				verdict("x", 70, "safe", true, 80, ""),
+				verdict("y", 70, "safe", false, 40, ""),
+			},
+			weights:     map[string]float64{},
+			golden:      false, // a tie is no majority
+			goldenScore: 60, riskLevel: "safe",
+			disagreement: true,
+		},
+		{
+			name: "risk level ties go to the more severe level",
+			latest: []model.TokenAnalysis{
+				verdict("a", 50, "safe", false, 10, ""),
+				verdict("b", 50, "danger", false, 10, ""),
+			},
+			weights:     map[string]float64{"a": 0.5, "b": 0.5},
+			goldenScore: 10, riskLevel: "danger",
+			disagreement: true,
+		},
+		{
+			name: "one agent cannot clear a weighted honeypot call",
+			latest: []model.TokenAnalysis{
+				verdict("a", 20, "danger", false, 0, "HIGH"),
+				verdict("b", 20, "danger", false, 0, "HIGH"),
+				verdict("c", 20, "danger", false, 0, "LOW"),
+			},
+			weights:  map[string]float64{"a": 0.6, "b": 0.6, "c": 0.6},
+			honeypot: true, riskLevel: "danger", honeypotVote: 3,
+			disagreement: true,
+		},
+		{
+			name: "one agent cannot flag a honeypot alone",
+			latest: []model.TokenAnalysis{
+				verdict("a", 60, "warning", false, 30, "HIGH"),
+				verdict("b", 60, "warning", false, 30, "LOW"),
+				verdict("c", 60, "warning", false, 30, ""),
+			},
+			weights:     map[string]float64{"a": 0.2, "b": 0.7, "c": 0.9},
+			goldenScore: 30, riskLevel: "warning", honeypotVote: 2,
+			disagreement: true,
+		},
+		{
+			name: "zero weights fall back to an unweighted vote",
+			latest: []model.TokenAnalysis{
+				verdict("a", 60, "safe", true, 70, ""),
+				verdict("b", 60, "safe", true, 90, ""),
+			},
+			weights:     map[string]float64{"a": 0, "b": 0},
+			golden:      true,
+			goldenScore: 80, riskLevel: "safe",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := BuildConsensus(tt.latest, tt.weights, now)
+			if c.IsGoldenDog != tt.golden || c.GoldenDogScore != tt.goldenScore || c.RiskLevel != tt.riskLevel {
+				t.Errorf("golden, score, level = %v, %d, %s, want %v, %d, %s", c.IsGoldenDog, c.GoldenDogScore, c.RiskLevel, tt.golden, tt.goldenScore, tt.riskLevel)
+			}
+			if c.IsHoneypot != tt.honeypot || c.HoneypotAgents != tt.honeypotVote {
+				t.Errorf("honeypot, agents = %v, %d, want %v, %d", c.IsHoneypot, c.HoneypotAgents, tt.honeypot, tt.honeypotVote)
+			}
+			if c.Disagreement != tt.disagreement {
+				t.Errorf("disagreement = %v (%v), want %v", c.Disagreement, c.DisagreementReasons, tt.disagreement)
+			}
+			if c.Agents != len(tt.latest) || len(c.Verdicts) != len(tt.latest) {
+				t.Errorf("agents, verdicts = %d, %d", c.Agents, len(c.Verdicts))
+			}
+		})
+	}
+}
+
+func TestAgentWeight(t *testing.T) {
+	tests := []struct {
+		name   string
+		counts repository.AgentCallCounts
+		want   float64
+	}{
+		{name: "no samples", counts: repository.AgentCallCounts{}, want: 0.5},
+		// 90 correct "no" calls out of 100 would be 90% accurate; F1 sees
+		// that it never found one of the 10 golden dogs.
+		{name: "always no", counts: repository.AgentCallCounts{TrueNegatives: 90, FalseNegatives: 10}, want: 2 * 0.5 * (1.0 / 12) / (0.5 + 1.0/12)},
+		{name: "always yes", counts: repository.AgentCallCounts{TruePositives: 10, FalsePositives: 90}, want: 2 * (11.0 / 102) * (11.0 / 12) / (11.0/102 + 11.0/12)},
+		{name: "good caller", counts: repository.AgentCallCounts{TruePositives: 8, FalsePositives: 2, TrueNegatives: 88, FalseNegatives: 2}, want: 0.75},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, got := agentWeight(tt.counts)
+			if math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("weight = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	_, _, alwaysNo := agentWeight(repository.AgentCallCounts{TrueNegatives: 90, FalseNegatives: 10})
+	_, _, good := agentWeight(repository.AgentCallCounts{TruePositives: 8, FalsePositives: 2, TrueNegatives: 88, FalseNegatives: 2})
+	if alwaysNo >= good {
+		t.Errorf("always-no weight %v should be below a good caller's %v", alwaysNo, good)
+	}
+}
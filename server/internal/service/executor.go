@@ -0,0 +1,45 @@
+package service
+
+import "context"
+
+// TradeExecutor runs a managed wallet trade through the same checks as the
+// execute-trade API. It is implemented by the wallet handler.
+type TradeExecutor interface {
+	ExecuteManagedTrade(ctx context.Context, req ManagedTradeRequest) (*ManagedTradeResult, error)
+}
+
+type ManagedTradeRequest struct {
+	UserID       string
+	TokenAddress string
+	TokenSymbol  string
+	Type         string // BUY | SELL
+	AmountIn     string // BNB for BUY; token amount, ratio or "ALL" for SELL
+	AmountOut    string
+	Reason       string
+	StrategyUsed string
+	GoldenScore  int
+	Force        bool
+	// OrderID links the resulting AITrade to the resting order that placed it.
+	OrderID string
+	// DecisionTrail is stored on the resulting AITrade.
+	DecisionTrail map[string]interface{}
+}
+
+type ManagedTradeResult struct {
+	TradeID    string
+	TxHash     string
+	Status     string
+	AmountIn   string
+	AmountOut  string
+	ProfitLoss float64
+}
+
+// ApprovalRequired is returned when a trade exceeds ConfirmThreshold and was
+// queued for manual approval instead of being executed.
+type ApprovalRequired struct {
+	ApprovalID string
+}
+
+func (e *ApprovalRequired) Error() string {
+	return "trade queued for approval " + e.ApprovalID
+}
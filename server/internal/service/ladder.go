@@ -0,0 +1,227 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"easymeme/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	LadderModeTime     = "time"     // tranches every IntervalSeconds
+	LadderModeBlock    = "block"    // tranches every IntervalBlocks
+	LadderModeDrawdown = "drawdown" // tranches at DrawdownStep intervals below the spot price at the first fill
+)
+
+const (
+	LadderStatusOpen      = "open"
+	LadderStatusFilled    = "filled"
+	LadderStatusPartial   = "partial"
+	LadderStatusFailed    = "failed"
+	LadderStatusCancelled = "cancelled"
+	LadderStatusExpired   = "expired"
+)
+
+const maxLadderTranches = 50
+
+// ValidateLadder checks the split parameters of a new ladder.
+func ValidateLadder(ladder *model.OrderLadder) error {
+	if ladder.TotalAmountBNB.LessThanOrEqual(decimal.Zero) {
+		return fmt.Errorf("totalAmount must be positive")
+	}
+	if ladder.Tranches < 2 || ladder.Tranches > maxLadderTranches {
+		return fmt.Errorf("tranches must be between 2 and %d", maxLadderTranches)
+	}
+	switch ladder.Mode {
+	case LadderModeTime:
+		if ladder.IntervalSeconds <= 0 {
+			return fmt.Errorf("intervalSeconds must be positive")
+		}
+	case LadderModeBlock:
+		if ladder.IntervalBlocks <= 0 {
+			return fmt.Errorf("intervalBlocks must be positive")
+		}
+	case LadderModeDrawdown:
+		if ladder.DrawdownStep <= 0 || ladder.DrawdownStep*float64(ladder.Tranches-1) >= 1 {
+			return fmt.Errorf("drawdownStep must be positive and keep every tranche above zero")
+		}
+	default:
+		return fmt.Errorf("mode must be time, block or drawdown")
+	}
+	return nil
+}
+
+// BuildLadderTranches splits the ladder into BUY tranches. The first tranche
+// fires immediately; drawdown tranches wait until the first fill sets their price.
+func BuildLadderTranches(ladder *model.OrderLadder, now time.Time, currentBlock uint64) []model.TradeOrder {
+	n := ladder.Tranches
+	share := ladder.TotalAmountBNB.Div(decimal.NewFromInt(int64(n))).Truncate(18)
+	tranches := make([]model.TradeOrder, 0, n)
+	allocated := decimal.Zero
+	for i := 0; i < n; i++ {
+		amount := share
+		if i == n-1 {
+			amount = ladder.TotalAmountBNB.Sub(allocated)
+		}
+		allocated = allocated.Add(amount)
+
+		order := model.TradeOrder{
+			UserID:       ladder.UserID,
+			TrancheIndex: i,
+			TokenAddress: ladder.TokenAddress,
+			TokenSymbol:  ladder.TokenSymbol,
+			Side:         "BUY",
+			AmountIn:     amount.String(),
+			Status:       OrderStatusOpen,
+			ExpiresAt:    ladder.ExpiresAt,
+		}
+		switch {
+		case ladder.Mode == LadderModeBlock && i > 0:
+			order.Trigger = OrderTriggerAtBlock
+			order.TriggerValue = decimal.NewFromInt(int64(currentBlock) + int64(i*ladder.IntervalBlocks))
+		case ladder.Mode == LadderModeDrawdown && i > 0:
+			order.Trigger = OrderTriggerPriceBelow
+			order.Status = OrderStatusWaiting
+		default:
+			order.Trigger = OrderTriggerAtTime
+			order.TriggerValue = decimal.NewFromInt(now.Unix() + int64(i*ladder.IntervalSeconds))
+		}
+		tranches = append(tranches, order)
+	}
+	return tranches
+}
+
+// syncLadders rolls tranche fills up into their ladders, arms drawdown
+// tranches once the first fill is known and settles finished ladders.
+func (e *OrderEngine) syncLadders(ctx context.Context) {
+	ladders, err := e.repo.ListOpenOrderLadders(ctx, orderEngineBatch)
+	if err != nil {
+		log.Printf("[OrderEngine] list ladders failed: %v", err)
+		return
+	}
+	for i := range ladders {
+		if err := e.syncLadder(ctx, &ladders[i]); err != nil {
+			log.Printf("[OrderEngine] sync ladder=%s failed: %v", ladders[i].ID, err)
+		}
+	}
+}
+
+func (e *OrderEngine) syncLadder(ctx context.Context, ladder *model.OrderLadder) error {
+	tranches, err := e.repo.ListLadderTranches(ctx, ladder.ID)
+	if err != nil {
+		return err
+	}
+
+	filled := 0
+	pending := 0
+	counts := map[string]int{}
+	filledBNB := decimal.Zero
+	filledTokens := decimal.Zero
+	firstPrice := ladder.FirstFillPrice
+	for _, tranche := range tranches {
+		counts[tranche.Status]++
+		switch tranche.Status {
+		case OrderStatusWaiting, OrderStatusOpen, OrderStatusExecuting, OrderStatusAwaitingApproval:
+			pending++
+		case OrderStatusFilled:
+			filled++
+			in, errIn := decimal.NewFromString(tranche.AmountIn)
+			out, errOut := decimal.NewFromString(tranche.AmountOut)
+			if errIn != nil || errOut != nil || out.LessThanOrEqual(decimal.Zero) {
+				continue
+			}
+			filledBNB = filledBNB.Add(in)
+			filledTokens = filledTokens.Add(out)
+			if tranche.TrancheIndex == 0 && firstPrice.IsZero() {
+				// Tranches rest on the spot price, which the fill price
+				// overstates by tax and impact; the fill price only stands in
+				// for tranches that filled before the spot was recorded.
+				firstPrice = tranche.SpotPrice
+				if firstPrice.LessThanOrEqual(decimal.Zero) {
+					firstPrice = in.Div(out)
+				}
+			}
+		}
+	}
+
+	updates := map[string]interface{}{
+		"filled_tranches":  filled,
+		"filled_bnb":       filledBNB,
+		"filled_tokens":    filledTokens,
+		"first_fill_price": firstPrice,
+	}
+	if filledTokens.GreaterThan(decimal.Zero) {
+		updates["avg_price_bnb"] = filledBNB.Div(filledTokens)
+	}
+
+	if ladder.Mode == LadderModeDrawdown && counts[OrderStatusWaiting] > 0 {
+		switch {
+		case firstPrice.GreaterThan(decimal.Zero):
+			e.armDrawdownTranches(ctx, ladder, tranches, firstPrice)
+		case len(tranches) > 0 && tranches[0].Status != OrderStatusFilled && !isPendingOrder(tranches[0].Status):
+			// Without a first fill the remaining tranches have no reference price.
+			if _, err := e.repo.CancelLadderTranches(ctx, ladder.ID); err != nil {
+				return err
+			}
+			counts[OrderStatusCancelled] += counts[OrderStatusWaiting]
+			pending -= counts[OrderStatusWaiting]
+		}
+	}
+
+	if pending == 0 {
+		status := LadderStatusFailed
+		switch {
+		case filled == len(tranches):
+			status = LadderStatusFilled
+		case filled > 0:
+			status = LadderStatusPartial
+		case counts[OrderStatusCancelled] > 0:
+			status = LadderStatusCancelled
+		case counts[OrderStatusExpired] > 0:
+			status = LadderStatusExpired
+		}
+		updates["status"] = status
+		log.Printf("[OrderEngine] ladder=%s %s (%d/%d tranches)", ladder.ID, status, filled, len(tranches))
+		if e.hub != nil {
+			e.hub.BroadcastToUser(ladder.UserID, map[string]interface{}{
+				"type":         "ladder_update",
+				"userId":       ladder.UserID,
+				"ladderId":     ladder.ID,
+				"tokenAddress": ladder.TokenAddress,
+				"status":       status,
+				"filled":       filled,
+				"tranches":     len(tranches),
+			})
+		}
+	}
+	return e.repo.UpdateOrderLadder(ctx, ladder.ID, updates)
+}
+
+func (e *OrderEngine) armDrawdownTranches(ctx context.Context, ladder *model.OrderLadder, tranches []model.TradeOrder, firstPrice decimal.Decimal) {
+	for _, tranche := range tranches {
+		if tranche.Status != OrderStatusWaiting {
+			continue
+		}
+		price := drawdownTriggerPrice(firstPrice, ladder.DrawdownStep, tranche.TrancheIndex)
+		if _, err := e.repo.TransitionTradeOrder(ctx, tranche.ID, OrderStatusWaiting, OrderStatusOpen, map[string]interface{}{
+			"trigger_value": price,
+		}); err != nil {
+			log.Printf("[OrderEngine] arm tranche=%s failed: %v", tranche.ID, err)
+		}
+	}
+}
+
+// drawdownTriggerPrice is the price tranche index rests at: step further
+// below the first fill's spot price for every tranche before it.
+func drawdownTriggerPrice(firstPrice decimal.Decimal, step float64, index int) decimal.Decimal {
+	drop := decimal.NewFromFloat(step * float64(index))
+	return firstPrice.Mul(decimal.NewFromInt(1).Sub(drop))
+}
+
+func isPendingOrder(status string) bool {
+	return status == OrderStatusWaiting || status == OrderStatusOpen || status == OrderStatusExecuting || status == OrderStatusAwaitingApproval
+}
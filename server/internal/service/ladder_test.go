@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"easymeme/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestBuildLadderTranches(t *testing.T) {
+	now := time.Unix(1_800_000_000, 0)
+	total := decimal.RequireFromString("1")
+
+	tests := []struct {
+		name        string
+		ladder      model.OrderLadder
+		wantTrigger []string
+		wantStatus  []string
+		wantValue   []int64 // trigger values; 0 for tranches armed later
+	}{
+		{
+			name:        "time",
+			ladder:      model.OrderLadder{Mode: LadderModeTime, TotalAmountBNB: total, Tranches: 3, IntervalSeconds: 60},
+			wantTrigger: []string{OrderTriggerAtTime, OrderTriggerAtTime, OrderTriggerAtTime},
+			wantStatus:  []string{OrderStatusOpen, OrderStatusOpen, OrderStatusOpen},
+			wantValue:   []int64{now.Unix(), now.Unix() + 60, now.Unix() + 120},
+		},
+		{
+			name:        "block",
+			ladder:      model.OrderLadder{Mode: LadderModeBlock, TotalAmountBNB: total, Tranches: 3, IntervalBlocks: 10},
+			wantTrigger: []string{OrderTriggerAtTime, OrderTriggerAtBlock, OrderTriggerAtBlock},
+			wantStatus:  []string{OrderStatusOpen, OrderStatusOpen, OrderStatusOpen},
+			wantValue:   []int64{now.Unix(), 1010, 1020},
+		},
+		{
+			name:        "drawdown",
+			ladder:      model.OrderLadder{Mode: LadderModeDrawdown, TotalAmountBNB: total, Tranches: 3, DrawdownStep: 0.1},
+			wantTrigger: []string{OrderTriggerAtTime, OrderTriggerPriceBelow, OrderTriggerPriceBelow},
+			wantStatus:  []string{OrderStatusOpen, OrderStatusWaiting, OrderStatusWaiting},
+			wantValue:   []int64{now.Unix(), 0, 0},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tranches := BuildLadderTranches(&tt.ladder, now, 1000)
+			if len(tranches) != tt.ladder.Tranches {
+				t.Fatalf("got %d tranches, want %d", len(tranches), tt.ladder.Tranches)
+			}
+			sum := decimal.Zero
+			for i, tranche := range tranches {
+				amount := decimal.RequireFromString(tranche.AmountIn)
+				sum = sum.Add(amount)
+				if tranche.TrancheIndex != i || tranche.Trigger != tt.wantTrigger[i] || tranche.Status != tt.wantStatus[i] {
+					t.Errorf("tranche %d = index %d, %s, %s", i, tranche.TrancheIndex, tranche.Trigger, tranche.Status)
+				}
+				if !tranche.TriggerValue.Equal(decimal.NewFromInt(tt.wantValue[i])) {
+					t.Errorf("tranche %d trigger value = %s, want %d", i, tranche.TriggerValue, tt.wantValue[i])
+				}
+			}
+			// The last tranche takes the rounding remainder.
+			if !sum.Equal(total) {
+				t.Errorf("tranches sum to %s, want %s", sum, total)
+			}
+		})
+	}
+}
+
+func TestDrawdownTriggerPrice(t *testing.T) {
+	spot := decimal.RequireFromString("0.002")
+	tests := []struct {
+		index int
+		want  string
+	}{
+		{index: 1, want: "0.0018"},
+		{index: 2, want: "0.0016"},
+		{index: 4, want: "0.0012"},
+	}
+	for _, tt := range tests {
+		if got := drawdownTriggerPrice(spot, 0.1, tt.index); !got.Equal(decimal.RequireFromString(tt.want)) {
+			t.Errorf("tranche %d = %s, want %s", tt.index, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,195 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	CostBasisFIFO    = "fifo"
+	CostBasisAverage = "average"
+)
+
+func IsValidCostBasis(method string) bool {
+	return method == CostBasisFIFO || method == CostBasisAverage
+}
+
+// lotMatch is how much of one lot a sell consumed.
+type lotMatch struct {
+	LotID     string          `json:"lot_id"`
+	BuyTxHash string          `json:"buy_tx_hash"`
+	Quantity  decimal.Decimal `json:"quantity"`
+	CostBNB   decimal.Decimal `json:"cost_bnb"`
+}
+
+// Ledger keeps buy lots and realized P&L. Taxes are captured by the measured
+// amounts: buy taxes shrink the tokens received and sell taxes the BNB received.
+type Ledger struct {
+	repo     *repository.Repository
+	valuator *Valuator
+}
+
+func NewLedger(repo *repository.Repository, valuator *Valuator) *Ledger {
+	return &Ledger{repo: repo, valuator: valuator}
+}
+
+// RecordBuy opens a lot for the tokens a buy received. costBNB includes gas.
+func (l *Ledger) RecordBuy(ctx context.Context, userID, tokenAddress, txHash string, quantity, costBNB decimal.Decimal, simulated bool) error {
+	if quantity.LessThanOrEqual(decimal.Zero) {
+		return nil
+	}
+	return l.repo.CreatePositionLot(ctx, &model.PositionLot{
+		UserID:       userID,
+		TokenAddress: tokenAddress,
+		BuyTxHash:    txHash,
+		Quantity:     quantity,
+		Remaining:    quantity,
+		CostBNB:      costBNB,
+		CostPerToken: costBNB.Div(quantity),
+		OpenedAt:     time.Now().UTC(),
+		Simulated:    simulated,
+	})
+}
+
+// SellLedgerInput describes a settled sell.
+type SellLedgerInput struct {
+	UserID       string
+	TokenAddress string
+	TokenSymbol  string
+	TxHash       string
+	Quantity     decimal.Decimal
+	ProceedsBNB  decimal.Decimal
+	GasBNB       decimal.Decimal
+	CostBasis    string
+	// FallbackCostPerToken prices tokens not covered by lots, such as
+	// positions opened before lots were recorded.
+	FallbackCostPerToken decimal.Decimal
+	// Simulated sells are paper trades and only consume paper lots.
+	Simulated bool
+}
+
+// RecordSell consumes lots for the tokens sold and records the realized P&L
+// net of gas in BNB and USD. The lots are locked while they are matched, so
+// concurrent sells of the same token never consume the same tokens.
+func (l *Ledger) RecordSell(ctx context.Context, in SellLedgerInput) (*model.RealizedPnL, error) {
+	method := strings.ToLower(in.CostBasis)
+	if !IsValidCostBasis(method) {
+		method = CostBasisFIFO
+	}
+	price, priceErr := l.valuator.BNBPriceUSD(ctx)
+	if priceErr != nil {
+		log.Printf("[Ledger] BNB/USD price unavailable for tx=%s: %v", in.TxHash, priceErr)
+	}
+
+	return l.repo.RecordSellAgainstLots(ctx, in.UserID, in.TokenAddress, in.Simulated, func(lots []model.PositionLot) (*model.RealizedPnL, map[string]decimal.Decimal) {
+		used := consumeLots(method, lots, in.Quantity, in.FallbackCostPerToken)
+		realized := in.ProceedsBNB.Sub(in.GasBNB).Sub(used.costBNB)
+		entry := &model.RealizedPnL{
+			UserID:       in.UserID,
+			TokenAddress: in.TokenAddress,
+			TokenSymbol:  in.TokenSymbol,
+			TxHash:       in.TxHash,
+			CostBasis:    method,
+			Quantity:     in.Quantity,
+			ProceedsBNB:  in.ProceedsBNB,
+			CostBNB:      used.costBNB,
+			GasBNB:       in.GasBNB,
+			RealizedBNB:  realized,
+			Simulated:    in.Simulated,
+		}
+		if priceErr == nil {
+			entry.BNBPriceUSD = price
+			entry.RealizedUSD = realized.Mul(price)
+		}
+		entry.Lots, _ = json.Marshal(used.matches)
+		return entry, used.remaining
+	})
+}
+
+// lotConsumption is what a sell takes from the open lots.
+type lotConsumption struct {
+	matches   []lotMatch
+	remaining map[string]decimal.Decimal // lot ID -> quantity left
+	costBNB   decimal.Decimal
+}
+
+// consumeLots matches quantity against lots, oldest first, by FIFO or by
+// average cost. Tokens the lots do not cover cost fallbackCostPerToken.
+func consumeLots(method string, lots []model.PositionLot, quantity, fallbackCostPerToken decimal.Decimal) lotConsumption {
+	c := lotConsumption{remaining: map[string]decimal.Decimal{}}
+	covered := decimal.Zero
+	switch method {
+	case CostBasisAverage:
+		held := decimal.Zero
+		heldCost := decimal.Zero
+		for _, lot := range lots {
+			held = held.Add(lot.Remaining)
+			heldCost = heldCost.Add(lot.Remaining.Mul(lot.CostPerToken))
+		}
+		if held.GreaterThan(decimal.Zero) {
+			covered = decimal.Min(quantity, held)
+			share := covered.Div(held)
+			c.costBNB = heldCost.Mul(share)
+			for _, lot := range lots {
+				used := lot.Remaining.Mul(share)
+				c.remaining[lot.ID] = lot.Remaining.Sub(used)
+				c.matches = append(c.matches, lotMatch{LotID: lot.ID, BuyTxHash: lot.BuyTxHash, Quantity: used, CostBNB: used.Mul(lot.CostPerToken)})
+			}
+		}
+	default:
+		left := quantity
+		for _, lot := range lots {
+			if left.LessThanOrEqual(decimal.Zero) {
+				break
+			}
+			used := decimal.Min(left, lot.Remaining)
+			left = left.Sub(used)
+			covered = covered.Add(used)
+			cost := used.Mul(lot.CostPerToken)
+			c.costBNB = c.costBNB.Add(cost)
+			c.remaining[lot.ID] = lot.Remaining.Sub(used)
+			c.matches = append(c.matches, lotMatch{LotID: lot.ID, BuyTxHash: lot.BuyTxHash, Quantity: used, CostBNB: cost})
+		}
+	}
+	if uncovered := quantity.Sub(covered); uncovered.GreaterThan(decimal.Zero) {
+		c.costBNB = c.costBNB.Add(uncovered.Mul(fallbackCostPerToken))
+	}
+	return c
+}
+
+// ReturnRatio is the realized P&L as a ratio of the cost basis.
+func ReturnRatio(entry *model.RealizedPnL) decimal.Decimal {
+	if entry == nil || entry.CostBNB.LessThanOrEqual(decimal.Zero) {
+		return decimal.Zero
+	}
+	return entry.RealizedBNB.Div(entry.CostBNB)
+}
+
+// RecordGasLoss books the gas burnt by a transaction that reverted.
+func (l *Ledger) RecordGasLoss(ctx context.Context, userID, tokenAddress, tokenSymbol, txHash string, gasBNB decimal.Decimal, simulated bool) error {
+	if gasBNB.LessThanOrEqual(decimal.Zero) {
+		return nil
+	}
+	entry := &model.RealizedPnL{
+		UserID:       userID,
+		TokenAddress: tokenAddress,
+		TokenSymbol:  tokenSymbol,
+		TxHash:       txHash,
+		GasBNB:       gasBNB,
+		RealizedBNB:  gasBNB.Neg(),
+		Simulated:    simulated,
+	}
+	if price, err := l.valuator.BNBPriceUSD(ctx); err == nil {
+		entry.BNBPriceUSD = price
+		entry.RealizedUSD = entry.RealizedBNB.Mul(price)
+	}
+	return l.repo.RecordRealizedPnL(ctx, entry, nil)
+}
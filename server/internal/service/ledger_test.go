@@ -0,0 +1,99 @@
+package service
+
+import (
+	"testing"
+
+	"easymeme/internal/model"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestConsumeLots(t *testing.T) {
+	d := decimal.RequireFromString
+	// 100 tokens at 0.01 BNB, then 100 at 0.03 BNB.
+	lots := []model.PositionLot{
+		{ID: "a", Remaining: d("100"), CostPerToken: d("0.01")},
+		{ID: "b", Remaining: d("100"), CostPerToken: d("0.03")},
+	}
+
+	tests := []struct {
+		name      string
+		method    string
+		quantity  string
+		fallback  string
+		cost      string
+		remaining map[string]string
+	}{
+		{
+			name:      "fifo within the first lot",
+			method:    CostBasisFIFO,
+			quantity:  "60",
+			cost:      "0.6",
+			remaining: map[string]string{"a": "40"},
+		},
+		{
+			name:      "fifo across lots",
+			method:    CostBasisFIFO,
+			quantity:  "150",
+			cost:      "2.5",
+			remaining: map[string]string{"a": "0", "b": "50"},
+		},
+		{
+			name:      "average cost",
+			method:    CostBasisAverage,
+			quantity:  "100",
+			cost:      "2",
+			remaining: map[string]string{"a": "50", "b": "50"},
+		},
+		{
+			name:      "fifo beyond the lots uses the fallback cost",
+			method:    CostBasisFIFO,
+			quantity:  "250",
+			fallback:  "0.02",
+			cost:      "5",
+			remaining: map[string]string{"a": "0", "b": "0"},
+		},
+		{
+			name:      "average beyond the lots uses the fallback cost",
+			method:    CostBasisAverage,
+			quantity:  "250",
+			fallback:  "0.02",
+			cost:      "5",
+			remaining: map[string]string{"a": "0", "b": "0"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fallback := decimal.Zero
+			if tt.fallback != "" {
+				fallback = d(tt.fallback)
+			}
+			got := consumeLots(tt.method, lots, d(tt.quantity), fallback)
+			if !got.costBNB.Equal(d(tt.cost)) {
+				t.Errorf("cost = %s, want %s", got.costBNB, tt.cost)
+			}
+			if len(got.remaining) != len(tt.remaining) {
+				t.Fatalf("remaining = %v, want %v", got.remaining, tt.remaining)
+			}
+			for id, want := range tt.remaining {
+				if left, ok := got.remaining[id]; !ok || !left.Equal(d(want)) {
+					t.Errorf("remaining[%s] = %s, want %s", id, left, want)
+				}
+			}
+			matched := decimal.Zero
+			for _, m := range got.matches {
+				matched = matched.Add(m.CostBNB)
+			}
+			if tt.fallback == "" && !matched.Equal(got.costBNB) {
+				t.Errorf("matched cost %s differs from cost %s", matched, got.costBNB)
+			}
+		})
+	}
+}
+
+func TestConsumeLotsWithoutLots(t *testing.T) {
+	got := consumeLots(CostBasisFIFO, nil, decimal.NewFromInt(10), decimal.RequireFromString("0.5"))
+	if !got.costBNB.Equal(decimal.NewFromInt(5)) || len(got.matches) != 0 {
+		t.Fatalf("cost, matches = %s, %v, want 5 and none", got.costBNB, got.matches)
+	}
+}
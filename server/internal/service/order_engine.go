@@ -0,0 +1,358 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+	"gorm.io/gorm"
+)
+
+const (
+	OrderStatusWaiting   = "waiting" // ladder tranche whose trigger is not known yet
+	OrderStatusOpen      = "open"
+	OrderStatusExecuting = "executing"
+	// OrderStatusAwaitingApproval orders placed a trade above ConfirmThreshold
+	// and settle once its TradeApproval is decided.
+	OrderStatusAwaitingApproval = "awaiting_approval"
+	OrderStatusFilled           = "filled"
+	OrderStatusFailed           = "failed"
+	OrderStatusCancelled        = "cancelled"
+	OrderStatusExpired          = "expired"
+)
+
+const (
+	OrderTriggerPriceBelow       = "price_below"        // token price in BNB from pair reserves
+	OrderTriggerPriceAbove       = "price_above"        // token price in BNB from pair reserves
+	OrderTriggerLiquidityAbove   = "liquidity_above"    // USD liquidity from market refresh data
+	OrderTriggerGoldenScoreAbove = "golden_score_above" // golden dog score reaches the value
+	OrderTriggerAtTime           = "at_time"            // unix seconds; used by ladder tranches
+	OrderTriggerAtBlock          = "at_block"           // block number; used by ladder tranches
+)
+
+const (
+	StrategyLimitOrder       = "limit_order"
+	StrategyConditionalOrder = "conditional_order"
+	StrategyLadderOrder      = "ladder_order"
+)
+
+const (
+	orderEngineInterval = 15 * time.Second
+	orderEngineBatch    = 200
+	// An order still executing this long after its trigger was interrupted,
+	// e.g. by a restart, and is settled from the trade it recorded.
+	orderExecutingTimeout = 10 * time.Minute
+)
+
+func IsValidOrderTrigger(trigger string) bool {
+	switch trigger {
+	case OrderTriggerPriceBelow, OrderTriggerPriceAbove, OrderTriggerLiquidityAbove, OrderTriggerGoldenScoreAbove:
+		return true
+	}
+	return false
+}
+
+// OrderEngine evaluates resting orders and executes them through the managed
+// wallet once their trigger condition holds.
+type OrderEngine struct {
+	client   *ethereum.Client
+	repo     *repository.Repository
+	executor TradeExecutor
+	hub      Broadcaster
+	pricer   *tokenPricer
+}
+
+func NewOrderEngine(client *ethereum.Client, repo *repository.Repository, executor TradeExecutor, hub Broadcaster) *OrderEngine {
+	return &OrderEngine{
+		client:   client,
+		repo:     repo,
+		executor: executor,
+		hub:      hub,
+		pricer:   newTokenPricer(client),
+	}
+}
+
+func (e *OrderEngine) Start(ctx context.Context) {
+	log.Println("[OrderEngine] Started")
+	go e.loop(ctx)
+}
+
+func (e *OrderEngine) loop(ctx context.Context) {
+	ticker := time.NewTicker(orderEngineInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			e.evaluateOrders(ctx)
+		}
+	}
+}
+
+// marketView caches per-token market inputs for one evaluation pass.
+type marketView struct {
+	token    *model.Token
+	price    decimal.Decimal
+	priceErr error
+	priced   bool
+}
+
+// orderPass caches chain and market inputs shared by one evaluation pass.
+type orderPass struct {
+	views    map[string]*marketView
+	block    uint64
+	blockErr error
+	blocked  bool
+}
+
+func (e *OrderEngine) evaluateOrders(ctx context.Context) {
+	e.recoverStaleOrders(ctx)
+	if n, err := e.repo.ExpireTradeOrders(ctx, time.Now().UTC()); err != nil {
+		log.Printf("[OrderEngine] expire orders failed: %v", err)
+	} else if n > 0 {
+		log.Printf("[OrderEngine] expired %d orders", n)
+	}
+
+	orders, err := e.repo.ListOpenTradeOrders(ctx, orderEngineBatch)
+	if err != nil {
+		log.Printf("[OrderEngine] list orders failed: %v", err)
+		return
+	}
+
+	pass := &orderPass{views: map[string]*marketView{}}
+	for i := range orders {
+		order := &orders[i]
+		observed, triggered, err := e.checkTrigger(ctx, order, pass)
+		if err != nil {
+			log.Printf("[OrderEngine] order=%s trigger=%s err=%v", order.ID, order.Trigger, err)
+			continue
+		}
+		if !triggered {
+			continue
+		}
+		e.execute(ctx, order, observed)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+
+	e.syncLadders(ctx)
+}
+
+func (e *OrderEngine) marketView(ctx context.Context, pass *orderPass, tokenAddress string) *marketView {
+	key := strings.ToLower(tokenAddress)
+	view, ok := pass.views[key]
+	if !ok {
+		view = &marketView{}
+		if token, err := e.repo.GetTokenByAddress(ctx, tokenAddress); err == nil {
+			view.token = token
+		}
+		pass.views[key] = view
+	}
+	return view
+}
+
+func (e *OrderEngine) checkTrigger(ctx context.Context, order *model.TradeOrder, pass *orderPass) (decimal.Decimal, bool, error) {
+	switch order.Trigger {
+	case OrderTriggerAtTime:
+		now := decimal.NewFromInt(time.Now().Unix())
+		return now, now.GreaterThanOrEqual(order.TriggerValue), nil
+	case OrderTriggerAtBlock:
+		if !pass.blocked {
+			pass.block, pass.blockErr = e.client.LatestBlockNumber(ctx)
+			pass.blocked = true
+		}
+		if pass.blockErr != nil {
+			return decimal.Zero, false, pass.blockErr
+		}
+		block := decimal.NewFromInt(int64(pass.block))
+		return block, block.GreaterThanOrEqual(order.TriggerValue), nil
+	}
+
+	view := e.marketView(ctx, pass, order.TokenAddress)
+	switch order.Trigger {
+	case OrderTriggerPriceBelow, OrderTriggerPriceAbove:
+		if !view.priced {
+			view.price, _, view.priceErr = e.pricer.Quote(ctx, common.HexToAddress(order.TokenAddress), decimal.Zero)
+			view.priced = true
+		}
+		if view.priceErr != nil {
+			return decimal.Zero, false, view.priceErr
+		}
+		if order.Trigger == OrderTriggerPriceBelow {
+			return view.price, view.price.LessThanOrEqual(order.TriggerValue), nil
+		}
+		return view.price, view.price.GreaterThanOrEqual(order.TriggerValue), nil
+	case OrderTriggerLiquidityAbove:
+		if view.token == nil {
+			return decimal.Zero, false, nil
+		}
+		liquidity := decimal.NewFromFloat(marketLiquidityUSD(view.token.MarketData))
+		return liquidity, liquidity.GreaterThanOrEqual(order.TriggerValue), nil
+	case OrderTriggerGoldenScoreAbove:
+		if view.token == nil {
+			return decimal.Zero, false, nil
+		}
+		score := decimal.NewFromInt(int64(view.token.GoldenDogScore))
+		return score, score.GreaterThanOrEqual(order.TriggerValue), nil
+	}
+	return decimal.Zero, false, fmt.Errorf("unknown trigger %q", order.Trigger)
+}
+
+func (e *OrderEngine) execute(ctx context.Context, order *model.TradeOrder, observed decimal.Decimal) {
+	now := time.Now().UTC()
+	claim := map[string]interface{}{"triggered_at": now}
+	// A ladder's first tranche records the spot price its drawdown
+	// tranches are measured from; the fill price includes tax and impact.
+	if order.ParentID != "" && order.TrancheIndex == 0 {
+		if spot, _, err := e.pricer.Quote(ctx, common.HexToAddress(order.TokenAddress), decimal.Zero); err == nil {
+			claim["spot_price"] = spot
+		} else {
+			log.Printf("[OrderEngine] spot price order=%s failed: %v", order.ID, err)
+		}
+	}
+	claimed, err := e.repo.TransitionTradeOrder(ctx, order.ID, OrderStatusOpen, OrderStatusExecuting, claim)
+	if err != nil || !claimed {
+		return
+	}
+
+	strategy := StrategyConditionalOrder
+	if order.ParentID != "" {
+		strategy = StrategyLadderOrder
+	} else if order.Trigger == OrderTriggerPriceBelow || order.Trigger == OrderTriggerPriceAbove {
+		strategy = StrategyLimitOrder
+	}
+	reason := fmt.Sprintf("order %s: %s %s (observed %s)", order.ID, order.Trigger, order.TriggerValue.String(), observed.String())
+	log.Printf("[OrderEngine] executing %s", reason)
+
+	// Buys pass the MinGoldenDogScore gate on the token's current score.
+	goldenScore := 0
+	if order.Trigger == OrderTriggerGoldenScoreAbove {
+		goldenScore = int(observed.IntPart())
+	} else if token, err := e.repo.GetTokenByAddress(ctx, order.TokenAddress); err == nil {
+		goldenScore = token.GoldenDogScore
+	}
+	result, err := e.executor.ExecuteManagedTrade(ctx, ManagedTradeRequest{
+		UserID:       order.UserID,
+		TokenAddress: order.TokenAddress,
+		TokenSymbol:  order.TokenSymbol,
+		Type:         order.Side,
+		AmountIn:     order.AmountIn,
+		AmountOut:    order.AmountOutMin,
+		Reason:       reason,
+		StrategyUsed: strategy,
+		GoldenScore:  goldenScore,
+		Force:        true,
+		OrderID:      order.ID,
+	})
+
+	var pending *ApprovalRequired
+	if errors.As(err, &pending) {
+		e.settle(ctx, order, OrderStatusAwaitingApproval, map[string]interface{}{"approval_id": pending.ApprovalID})
+		return
+	}
+	var halted *TradingHalted
+	if errors.As(err, &halted) {
+		// Halted orders keep resting until trading is resumed.
+		if _, err := e.repo.TransitionTradeOrder(ctx, order.ID, OrderStatusExecuting, OrderStatusOpen, nil); err != nil {
+			log.Printf("[OrderEngine] release order=%s failed: %v", order.ID, err)
+		}
+		log.Printf("[OrderEngine] order=%s held: %v", order.ID, err)
+		return
+	}
+
+	if err != nil {
+		e.settle(ctx, order, OrderStatusFailed, map[string]interface{}{"error_message": err.Error()})
+		return
+	}
+	status, updates := orderSettlement(result.TradeID, result.TxHash, result.Status, result.AmountOut)
+	e.settle(ctx, order, status, updates)
+}
+
+// orderSettlement maps the trade an order placed to the order's final status.
+func orderSettlement(tradeID, txHash, tradeStatus, amountOut string) (string, map[string]interface{}) {
+	updates := map[string]interface{}{
+		"trade_id": tradeID,
+		"tx_hash":  txHash,
+	}
+	if tradeStatus != "success" {
+		updates["error_message"] = "trade status " + tradeStatus
+		return OrderStatusFailed, updates
+	}
+	updates["amount_out"] = amountOut
+	updates["filled_at"] = time.Now().UTC()
+	return OrderStatusFilled, updates
+}
+
+// settle moves an executing order to its next status and announces it.
+func (e *OrderEngine) settle(ctx context.Context, order *model.TradeOrder, status string, updates map[string]interface{}) {
+	if _, err := e.repo.TransitionTradeOrder(ctx, order.ID, OrderStatusExecuting, status, updates); err != nil {
+		log.Printf("[OrderEngine] update order=%s failed: %v", order.ID, err)
+	}
+	log.Printf("[OrderEngine] order=%s %s", order.ID, status)
+
+	if e.hub != nil {
+		e.hub.BroadcastToUser(order.UserID, map[string]interface{}{
+			"type":         "order_update",
+			"userId":       order.UserID,
+			"orderId":      order.ID,
+			"tokenAddress": order.TokenAddress,
+			"side":         order.Side,
+			"status":       status,
+			"txHash":       updates["tx_hash"],
+		})
+	}
+}
+
+// recoverStaleOrders settles orders left executing by an interrupted run. An
+// order whose trade was recorded takes that trade's result; otherwise it
+// fails rather than reopening, since its swap may have been broadcast.
+func (e *OrderEngine) recoverStaleOrders(ctx context.Context) {
+	orders, err := e.repo.ListStaleTradeOrders(ctx, time.Now().UTC().Add(-orderExecutingTimeout), orderEngineBatch)
+	if err != nil {
+		log.Printf("[OrderEngine] list stale orders failed: %v", err)
+		return
+	}
+	for i := range orders {
+		order := &orders[i]
+		trade, err := e.repo.GetAITradeByOrder(ctx, order.ID)
+		if err != nil {
+			if !errors.Is(err, gorm.ErrRecordNotFound) {
+				log.Printf("[OrderEngine] load trade of order=%s failed: %v", order.ID, err)
+				continue
+			}
+			e.settle(ctx, order, OrderStatusFailed, map[string]interface{}{
+				"error_message": "interrupted before a trade was recorded; check the wallet for a broadcast swap",
+			})
+			continue
+		}
+		status, updates := orderSettlement(trade.ID, trade.TxHash, trade.Status, trade.AmountOut.String())
+		e.settle(ctx, order, status, updates)
+	}
+}
+
+func marketLiquidityUSD(marketData []byte) float64 {
+	if len(marketData) == 0 {
+		return 0
+	}
+	var market map[string]interface{}
+	if err := json.Unmarshal(marketData, &market); err != nil {
+		return 0
+	}
+	return toFloat64(getNested(market, "liquidity", "usd"))
+}
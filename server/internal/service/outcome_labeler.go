@@ -0,0 +1,308 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+)
+
+const (
+	outcomeInterval = 5 * time.Minute
+	outcomeBatch    = 50
+	// A token is labeled up to this long before its horizon so the chain
+	// state can be read live without looking past the horizon; a token
+	// reached after its horizon is labeled from stored snapshots alone.
+	outcomeLiveLead = outcomeInterval
+
+	// Liquidity down this far from its peak is a rug.
+	rugLiquidityDrop = 0.8
+	// A token is dead once its price fell to this share of the entry price,
+	// its liquidity below deadLiquidityUSD, or nobody traded in the last hour.
+	deadPriceShare   = 0.1
+	deadLiquidityUSD = 500
+	// This many buys with no sell at all confirms a honeypot.
+	honeypotMinBuys = 20
+)
+
+type outcomeHorizon struct {
+	Name  string
+	After time.Duration
+}
+
+var outcomeHorizons = []outcomeHorizon{
+	{Name: "1h", After: time.Hour},
+	{Name: "6h", After: 6 * time.Hour},
+	{Name: "24h", After: 24 * time.Hour},
+}
+
+// OutcomeMetrics are the measurements an outcome is labeled from, taken
+// between launch and the horizon.
+type OutcomeMetrics struct {
+	Snapshots           int      `json:"snapshots"`
+	EntryPriceUSD       float64  `json:"entryPriceUsd"`
+	PeakPriceUSD        float64  `json:"peakPriceUsd"`
+	LastPriceUSD        float64  `json:"lastPriceUsd"`
+	MaxReturn           float64  `json:"maxReturn"`
+	FinalReturn         float64  `json:"finalReturn"`
+	MaxDrawdown         float64  `json:"maxDrawdown"`
+	EntryLiquidityUSD   float64  `json:"entryLiquidityUsd"`
+	PeakLiquidityUSD    float64  `json:"peakLiquidityUsd"`
+	LastLiquidityUSD    float64  `json:"lastLiquidityUsd"`
+	LiquidityFromPeak   float64  `json:"liquidityFromPeak"`
+	BuysH1              *int     `json:"buysH1,omitempty"`
+	SellsH1             *int     `json:"sellsH1,omitempty"`
+	MaxBuysH1           int      `json:"maxBuysH1"`
+	MaxSellsH1          int      `json:"maxSellsH1"`
+	HoldersAtEnrichment int      `json:"holdersAtEnrichment"`
+	HoldersAtHorizon    *int     `json:"holdersAtHorizon,omitempty"`
+	HolderChange        *float64 `json:"holderChange,omitempty"`
+	HoneypotAtHorizon   *bool    `json:"honeypotAtHorizon,omitempty"`
+	SellTaxAtHorizon    *float64 `json:"sellTaxAtHorizon,omitempty"`
+}
+
+type outcomePoint struct {
+	at        time.Time
+	price     float64
+	liquidity float64
+	flow      bool
+	buys      int
+	sells     int
+}
+
+// OutcomeLabeler labels every enriched token at fixed horizons after launch
+// so analyses can be scored against what happened.
+type OutcomeLabeler struct {
+	repo        *repository.Repository
+	goPlus      *GoPlusClient
+	dexScreener *DEXScreenerClient
+}
+
+func NewOutcomeLabeler(repo *repository.Repository) *OutcomeLabeler {
+	return &OutcomeLabeler{
+		repo:        repo,
+		goPlus:      NewGoPlusClient(),
+		dexScreener: NewDEXScreenerClient(),
+	}
+}
+
+func (l *OutcomeLabeler) Start(ctx context.Context) {
+	log.Println("[OutcomeLabeler] Started")
+	go func() {
+		l.labelDue(ctx)
+		ticker := time.NewTicker(outcomeInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				l.labelDue(ctx)
+			}
+		}
+	}()
+}
+
+func (l *OutcomeLabeler) labelDue(ctx context.Context) {
+	for _, horizon := range outcomeHorizons {
+		tokens, err := l.repo.ListTokensDueForOutcome(ctx, horizon.Name, horizon.After-outcomeLiveLead, outcomeBatch)
+		if err != nil {
+			log.Printf("[OutcomeLabeler] list due horizon=%s err=%v", horizon.Name, err)
+			continue
+		}
+		for i := range tokens {
+			if ctx.Err() != nil {
+				return
+			}
+			outcome, err := l.evaluate(ctx, &tokens[i], horizon)
+			if err == nil {
+				err = l.repo.CreateTokenOutcome(ctx, outcome)
+			}
+			if err != nil {
+				log.Printf("[OutcomeLabeler] label token=%s horizon=%s err=%v", tokens[i].Address, horizon.Name, err)
+			}
+		}
+	}
+}
+
+func (l *OutcomeLabeler) evaluate(ctx context.Context, token *model.Token, horizon outcomeHorizon) (*model.TokenOutcome, error) {
+	now := time.Now().UTC()
+	horizonAt := token.CreatedAt.Add(horizon.After)
+	end := horizonAt
+	if now.Before(end) {
+		end = now
+	}
+
+	market, err := l.repo.ListMarketSnapshotsBetween(ctx, token.Address, token.CreatedAt, end)
+	if err != nil {
+		return nil, err
+	}
+	prices, err := l.repo.GetTokenPriceSeries(ctx, token.Address, token.CreatedAt, end, 0)
+	if err != nil {
+		return nil, err
+	}
+	points := make([]outcomePoint, 0, len(market)+len(prices)+1)
+	for _, s := range market {
+		points = append(points, outcomePoint{at: s.CreatedAt, price: s.PriceUSD, liquidity: s.LiquidityUSD, flow: true, buys: s.BuysH1, sells: s.SellsH1})
+	}
+	for _, s := range prices {
+		points = append(points, outcomePoint{at: s.TS, price: s.PriceUSD, liquidity: s.LiquidityUSD})
+	}
+
+	live := !now.After(horizonAt)
+	var security *GoPlusSecurityData
+	if live {
+		if token.PairAddress != "" {
+			if pair, err := l.dexScreener.GetPairData(ctx, token.PairAddress); err == nil {
+				dex := normalizeDEXScreener(pair)
+				points = append(points, outcomePoint{
+					at:        now,
+					price:     toFloat64(dex["priceUsd"]),
+					liquidity: toFloat64(getNested(dex, "liquidity", "usd")),
+					flow:      true,
+					buys:      int(toFloat64(getNested(dex, "txns", "h1", "buys"))),
+					sells:     int(toFloat64(getNested(dex, "txns", "h1", "sells"))),
+				})
+			} else {
+				log.Printf("[OutcomeLabeler] DEXScreener warning token=%s: %v", token.Address, err)
+			}
+		}
+		if security, err = l.goPlus.GetTokenSecurity(ctx, token.Address); err != nil {
+			log.Printf("[OutcomeLabeler] GoPlus warning token=%s: %v", token.Address, err)
+		}
+	}
+	sort.Slice(points, func(i, j int) bool { return points[i].at.Before(points[j].at) })
+
+	metrics := outcomeMetrics(points, baselineInputsFromToken(token).holderCount, security)
+	labels, evidence := labelOutcome(metrics, model.ActiveScoring())
+
+	labelsJSON, _ := json.Marshal(labels)
+	metricsJSON, err := json.Marshal(metrics)
+	if err != nil {
+		return nil, err
+	}
+	evidenceJSON, _ := json.Marshal(evidence)
+	return &model.TokenOutcome{
+		TokenAddress: token.Address,
+		Horizon:      horizon.Name,
+		HorizonAt:    horizonAt,
+		Outcome:      labels[0],
+		Labels:       labelsJSON,
+		MaxReturn:    metrics.MaxReturn,
+		Metrics:      metricsJSON,
+		Evidence:     evidenceJSON,
+		Live:         live,
+		EvaluatedAt:  now,
+	}, nil
+}
+
+func outcomeMetrics(points []outcomePoint, holdersAtEnrichment int, security *GoPlusSecurityData) OutcomeMetrics {
+	m := OutcomeMetrics{HoldersAtEnrichment: holdersAtEnrichment}
+	runningPeak := 0.0
+	for _, p := range points {
+		if p.price > 0 {
+			m.Snapshots++
+			if m.EntryPriceUSD == 0 {
+				m.EntryPriceUSD = p.price
+				m.EntryLiquidityUSD = p.liquidity
+			}
+			m.PeakPriceUSD = max(m.PeakPriceUSD, p.price)
+			m.LastPriceUSD = p.price
+			runningPeak = max(runningPeak, p.price)
+			m.MaxDrawdown = max(m.MaxDrawdown, 1-p.price/runningPeak)
+		}
+		if p.liquidity > 0 || p.flow {
+			m.PeakLiquidityUSD = max(m.PeakLiquidityUSD, p.liquidity)
+			m.LastLiquidityUSD = p.liquidity
+		}
+		if p.flow {
+			buys, sells := p.buys, p.sells
+			m.BuysH1, m.SellsH1 = &buys, &sells
+			m.MaxBuysH1 = max(m.MaxBuysH1, p.buys)
+			m.MaxSellsH1 = max(m.MaxSellsH1, p.sells)
+		}
+	}
+	if m.EntryPriceUSD > 0 {
+		m.MaxReturn = m.PeakPriceUSD / m.EntryPriceUSD
+		m.FinalReturn = m.LastPriceUSD / m.EntryPriceUSD
+	}
+	if m.PeakLiquidityUSD > 0 {
+		m.LiquidityFromPeak = m.LastLiquidityUSD/m.PeakLiquidityUSD - 1
+	}
+
+	if security != nil {
+		honeypot := parseBinaryFlag(security.IsHoneypot)
+		sellTax := parsePercentNumber(security.SellTax)
+		holders := int(parsePlainNumber(security.HolderCount))
+		m.HoneypotAtHorizon, m.SellTaxAtHorizon, m.HoldersAtHorizon = &honeypot, &sellTax, &holders
+		if holdersAtEnrichment > 0 {
+			change := float64(holders)/float64(holdersAtEnrichment) - 1
+			m.HolderChange = &change
+		}
+	}
+	return m
+}
+
+// labelOutcome returns the labels that apply, most severe first, and the
+// reason for each.
+func labelOutcome(m OutcomeMetrics, cfg *model.ScoringConfig) ([]string, map[string]string) {
+	labels := []string{}
+	evidence := map[string]string{}
+	add := func(label, reason string) {
+		labels = append(labels, label)
+		evidence[label] = reason
+	}
+
+	switch {
+	case m.HoneypotAtHorizon != nil && *m.HoneypotAtHorizon:
+		add(model.OutcomeHoneypot, "GoPlus flags the token as a honeypot at the horizon")
+	case m.SellTaxAtHorizon != nil && *m.SellTaxAtHorizon >= cfg.Thresholds.TaxExtreme:
+		add(model.OutcomeHoneypot, fmt.Sprintf("sell tax %.0f%% at the horizon", *m.SellTaxAtHorizon*100))
+	case m.MaxBuysH1 >= honeypotMinBuys && m.MaxSellsH1 == 0:
+		add(model.OutcomeHoneypot, fmt.Sprintf("up to %d buys per hour and no sell", m.MaxBuysH1))
+	}
+
+	if m.Snapshots == 0 {
+		if len(labels) == 0 {
+			add(model.OutcomeNoData, "no price snapshot before the horizon")
+		}
+		return labels, evidence
+	}
+
+	rugged := m.PeakLiquidityUSD > 0 && m.LiquidityFromPeak <= -rugLiquidityDrop
+	if rugged {
+		add(model.OutcomeRugged, fmt.Sprintf("liquidity fell %.0f%% from its $%.0f peak to $%.0f", -m.LiquidityFromPeak*100, m.PeakLiquidityUSD, m.LastLiquidityUSD))
+	}
+
+	if !rugged && len(labels) == 0 {
+		switch {
+		case m.EntryPriceUSD > 0 && m.FinalReturn <= deadPriceShare:
+			add(model.OutcomeDead, fmt.Sprintf("price at %.0f%% of entry", m.FinalReturn*100))
+		case m.PeakLiquidityUSD > 0 && m.LastLiquidityUSD < deadLiquidityUSD:
+			add(model.OutcomeDead, fmt.Sprintf("liquidity $%.0f", m.LastLiquidityUSD))
+		case m.BuysH1 != nil && *m.BuysH1+*m.SellsH1 == 0:
+			add(model.OutcomeDead, "no trade in the last hour")
+		}
+	}
+
+	if m.MaxReturn >= 10 {
+		add(model.Outcome10x, fmt.Sprintf("peaked at %.1fx the entry price", m.MaxReturn))
+	}
+	if m.MaxReturn >= 2 {
+		add(model.Outcome2x, fmt.Sprintf("peaked at %.1fx the entry price", m.MaxReturn))
+	}
+
+	if len(labels) == 0 || (labels[0] != model.OutcomeHoneypot && labels[0] != model.OutcomeRugged && labels[0] != model.OutcomeDead) {
+		reason := fmt.Sprintf("price at %.0f%% of entry, liquidity $%.0f", m.FinalReturn*100, m.LastLiquidityUSD)
+		if m.HolderChange != nil {
+			reason += fmt.Sprintf(", holders %+.0f%% since enrichment", *m.HolderChange*100)
+		}
+		add(model.OutcomeSurvived, reason)
+	}
+	return labels, evidence
+}
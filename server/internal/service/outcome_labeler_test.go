@@ -0,0 +1,112 @@
+package service
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"easymeme/internal/model"
+)
+
+func TestOutcomeMetrics(t *testing.T) {
+	start := time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)
+	points := []outcomePoint{
+		{at: start, price: 1, liquidity: 10000, flow: true, buys: 5, sells: 2},
+		{at: start.Add(10 * time.Minute), price: 4, liquidity: 20000},
+		{at: start.Add(20 * time.Minute), price: 2, liquidity: 15000},
+		{at: start.Add(30 * time.Minute), price: 0, liquidity: 0, flow: true, buys: 0, sells: 0},
+	}
+	m := outcomeMetrics(points, 100, &GoPlusSecurityData{IsHoneypot: "0", SellTax: "0.05", HolderCount: "150"})
+
+	if m.Snapshots != 3 {
+		t.Errorf("Snapshots = %d, want 3", m.Snapshots)
+	}
+	if m.MaxReturn != 4 || m.FinalReturn != 2 {
+		t.Errorf("MaxReturn, FinalReturn = %v, %v, want 4, 2", m.MaxReturn, m.FinalReturn)
+	}
+	if m.MaxDrawdown != 0.5 {
+		t.Errorf("MaxDrawdown = %v, want 0.5", m.MaxDrawdown)
+	}
+	// A flow point without price still counts as liquidity gone.
+	if m.PeakLiquidityUSD != 20000 || m.LastLiquidityUSD != 0 || m.LiquidityFromPeak != -1 {
+		t.Errorf("liquidity peak, last, from peak = %v, %v, %v", m.PeakLiquidityUSD, m.LastLiquidityUSD, m.LiquidityFromPeak)
+	}
+	if m.MaxBuysH1 != 5 || m.BuysH1 == nil || *m.BuysH1 != 0 {
+		t.Errorf("MaxBuysH1, BuysH1 = %d, %v", m.MaxBuysH1, m.BuysH1)
+	}
+	if m.HolderChange == nil || *m.HolderChange != 0.5 {
+		t.Errorf("HolderChange = %v, want 0.5", m.HolderChange)
+	}
+}
+
+func TestLabelOutcome(t *testing.T) {
+	cfg := model.DefaultScoringConfig()
+	yes, no := true, false
+	highTax := 0.6
+	intp := func(v int) *int { return &v }
+
+	tests := []struct {
+		name    string
+		metrics OutcomeMetrics
+		want    []string
+	}{
+		{
+			name:    "no data",
+			metrics: OutcomeMetrics{},
+			want:    []string{model.OutcomeNoData},
+		},
+		{
+			name:    "honeypot flagged at horizon",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 1, FinalReturn: 1, HoneypotAtHorizon: &yes},
+			want:    []string{model.OutcomeHoneypot},
+		},
+		{
+			name:    "extreme sell tax",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 1, FinalReturn: 1, HoneypotAtHorizon: &no, SellTaxAtHorizon: &highTax},
+			want:    []string{model.OutcomeHoneypot},
+		},
+		{
+			name:    "buys without sells",
+			metrics: OutcomeMetrics{MaxBuysH1: honeypotMinBuys},
+			want:    []string{model.OutcomeHoneypot},
+		},
+		{
+			name:    "rug after a 3x",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 3, FinalReturn: 0.05, PeakLiquidityUSD: 50000, LastLiquidityUSD: 1000, LiquidityFromPeak: -0.98},
+			want:    []string{model.OutcomeRugged, model.Outcome2x},
+		},
+		{
+			name:    "price collapsed",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 1, FinalReturn: 0.05, PeakLiquidityUSD: 20000, LastLiquidityUSD: 15000, LiquidityFromPeak: -0.25},
+			want:    []string{model.OutcomeDead},
+		},
+		{
+			name:    "no trade in the last hour",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 1.2, FinalReturn: 0.9, PeakLiquidityUSD: 20000, LastLiquidityUSD: 18000, LiquidityFromPeak: -0.1, BuysH1: intp(0), SellsH1: intp(0)},
+			want:    []string{model.OutcomeDead},
+		},
+		{
+			name:    "10x survivor",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 12, FinalReturn: 8, PeakLiquidityUSD: 90000, LastLiquidityUSD: 80000, LiquidityFromPeak: -0.11},
+			want:    []string{model.Outcome10x, model.Outcome2x, model.OutcomeSurvived},
+		},
+		{
+			name:    "flat survivor",
+			metrics: OutcomeMetrics{Snapshots: 3, EntryPriceUSD: 1, MaxReturn: 1.1, FinalReturn: 1, PeakLiquidityUSD: 20000, LastLiquidityUSD: 20000},
+			want:    []string{model.OutcomeSurvived},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			labels, evidence := labelOutcome(tt.metrics, cfg)
+			if !reflect.DeepEqual(labels, tt.want) {
+				t.Fatalf("labels = %v, want %v", labels, tt.want)
+			}
+			for _, label := range labels {
+				if evidence[label] == "" {
+					t.Errorf("no evidence for %s", label)
+				}
+			}
+		})
+	}
+}
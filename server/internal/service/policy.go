@@ -0,0 +1,260 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	PolicyActionBuy      = "swap_buy"
+	PolicyActionSell     = "swap_sell"
+	PolicyActionApprove  = "approve"
+	PolicyActionWithdraw = "withdraw"
+)
+
+const (
+	RuleTradingHours     = "trading_hours"
+	RuleAllowedRouters   = "allowed_routers"
+	RuleAllowedContracts = "allowed_contracts"
+	RuleTokenAllowlist   = "token_allowlist"
+	RuleTokenDenylist    = "token_denylist"
+	RuleMaxSlippage      = "max_slippage"
+	RuleMaxPriceImpact   = "max_price_impact"
+	RuleMaxSellTax       = "max_sell_tax"
+	RuleMaxOpenPositions = "max_open_positions"
+	RuleMaxTokenExposure = "max_token_exposure"
+	RuleMaxWithdrawal    = "max_withdrawal"
+)
+
+// SpendingPolicy is the per-wallet rule set checked before every signature.
+// Zero values disable a rule. Ratios are fractions (0.1 = 10%).
+type SpendingPolicy struct {
+	// AllowedRouters restricts swap routers and approval spenders.
+	AllowedRouters []string `json:"allowedRouters"`
+	// AllowedContracts extends the set of contracts that may be called or approved as spenders.
+	AllowedContracts    []string `json:"allowedContracts"`
+	TokenAllowlist      []string `json:"tokenAllowlist"`
+	TokenDenylist       []string `json:"tokenDenylist"`
+	MaxSlippage         float64  `json:"maxSlippage"`
+	MaxPriceImpact      float64  `json:"maxPriceImpact"`
+	MaxSellTax          float64  `json:"maxSellTax"`
+	MaxOpenPositions    int      `json:"maxOpenPositions"`
+	MaxTokenExposureBNB float64  `json:"maxTokenExposureBnb"`
+	MaxWithdrawalBNB    float64  `json:"maxWithdrawalBnb"`
+	// TradingHours are UTC windows such as "08:00-22:00"; windows may wrap midnight.
+	// They gate buys and withdrawals only, so exits are never blocked.
+	TradingHours []string `json:"tradingHours"`
+}
+
+type PolicyAction struct {
+	Kind          string
+	Target        string // contract or address the transaction is sent to
+	Router        string // swap router or approval spender
+	TokenAddress  string
+	AmountBNB     decimal.Decimal
+	Quoted        bool // Slippage and PriceImpact come from on-chain reserves
+	Slippage      float64
+	PriceImpact   float64
+	SellTax       float64
+	SellTaxKnown  bool
+	OpenPositions int // open positions in other tokens
+	HasPosition   bool
+	ExposureBNB   decimal.Decimal // cost basis already held in TokenAddress
+	Now           time.Time
+	// Emergency exits skip the slippage and price impact caps so a position
+	// can always be dumped; see Bypassed.
+	Emergency bool
+}
+
+type PolicyViolation struct {
+	Rule    string `json:"rule"`
+	Message string `json:"message"`
+}
+
+func (v *PolicyViolation) Error() string {
+	return fmt.Sprintf("policy %s: %s", v.Rule, v.Message)
+}
+
+func (p SpendingPolicy) IsEmpty() bool {
+	return len(p.AllowedRouters) == 0 &&
+		len(p.AllowedContracts) == 0 &&
+		len(p.TokenAllowlist) == 0 &&
+		len(p.TokenDenylist) == 0 &&
+		p.MaxSlippage <= 0 &&
+		p.MaxPriceImpact <= 0 &&
+		p.MaxSellTax <= 0 &&
+		p.MaxOpenPositions <= 0 &&
+		p.MaxTokenExposureBNB <= 0 &&
+		p.MaxWithdrawalBNB <= 0 &&
+		len(p.TradingHours) == 0
+}
+
+func (p SpendingPolicy) Validate() error {
+	for _, window := range p.TradingHours {
+		if _, _, err := parseTradingWindow(window); err != nil {
+			return err
+		}
+	}
+	for _, ratio := range []float64{p.MaxSlippage, p.MaxPriceImpact, p.MaxSellTax} {
+		if ratio < 0 || ratio > 1 {
+			return fmt.Errorf("ratio limits must be between 0 and 1")
+		}
+	}
+	if p.MaxOpenPositions < 0 || p.MaxTokenExposureBNB < 0 || p.MaxWithdrawalBNB < 0 {
+		return fmt.Errorf("limits must not be negative")
+	}
+	return nil
+}
+
+// Evaluate returns the first rule the action breaks, or nil.
+func (p SpendingPolicy) Evaluate(a PolicyAction) *PolicyViolation {
+	now := a.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	if (a.Kind == PolicyActionBuy || a.Kind == PolicyActionWithdraw) && len(p.TradingHours) > 0 && !p.withinTradingHours(now) {
+		return &PolicyViolation{Rule: RuleTradingHours, Message: "outside trading hours " + strings.Join(p.TradingHours, ",")}
+	}
+
+	switch a.Kind {
+	case PolicyActionBuy, PolicyActionSell:
+		if len(p.AllowedRouters) > 0 && !containsAddress(p.AllowedRouters, a.Router) {
+			return &PolicyViolation{Rule: RuleAllowedRouters, Message: "router " + a.Router + " is not allowed"}
+		}
+		if len(p.AllowedContracts) > 0 && !containsAddress(p.AllowedContracts, a.Target) && !containsAddress(p.AllowedRouters, a.Target) {
+			return &PolicyViolation{Rule: RuleAllowedContracts, Message: "contract " + a.Target + " is not allowed"}
+		}
+		if a.Emergency {
+			break
+		}
+		if !a.Quoted {
+			if p.MaxSlippage > 0 {
+				return &PolicyViolation{Rule: RuleMaxSlippage, Message: "quote unavailable"}
+			}
+			if p.MaxPriceImpact > 0 {
+				return &PolicyViolation{Rule: RuleMaxPriceImpact, Message: "quote unavailable"}
+			}
+		}
+		if p.MaxSlippage > 0 && a.Slippage > p.MaxSlippage {
+			return &PolicyViolation{Rule: RuleMaxSlippage, Message: fmt.Sprintf("slippage %.4f exceeds %.4f", a.Slippage, p.MaxSlippage)}
+		}
+		if p.MaxPriceImpact > 0 && a.PriceImpact > p.MaxPriceImpact {
+			return &PolicyViolation{Rule: RuleMaxPriceImpact, Message: fmt.Sprintf("price impact %.4f exceeds %.4f", a.PriceImpact, p.MaxPriceImpact)}
+		}
+	case PolicyActionApprove:
+		if len(p.AllowedRouters) > 0 || len(p.AllowedContracts) > 0 {
+			if !containsAddress(p.AllowedRouters, a.Router) && !containsAddress(p.AllowedContracts, a.Router) {
+				return &PolicyViolation{Rule: RuleAllowedContracts, Message: "spender " + a.Router + " is not allowed"}
+			}
+		}
+	case PolicyActionWithdraw:
+		if p.MaxWithdrawalBNB > 0 && a.AmountBNB.GreaterThan(decimal.NewFromFloat(p.MaxWithdrawalBNB)) {
+			return &PolicyViolation{Rule: RuleMaxWithdrawal, Message: "withdrawal " + a.AmountBNB.String() + " BNB exceeds limit"}
+		}
+	}
+
+	if a.Kind != PolicyActionBuy {
+		return nil
+	}
+	if len(p.TokenDenylist) > 0 && containsAddress(p.TokenDenylist, a.TokenAddress) {
+		return &PolicyViolation{Rule: RuleTokenDenylist, Message: "token " + a.TokenAddress + " is denied"}
+	}
+	if len(p.TokenAllowlist) > 0 && !containsAddress(p.TokenAllowlist, a.TokenAddress) {
+		return &PolicyViolation{Rule: RuleTokenAllowlist, Message: "token " + a.TokenAddress + " is not allowlisted"}
+	}
+	if p.MaxSellTax > 0 {
+		if !a.SellTaxKnown {
+			return &PolicyViolation{Rule: RuleMaxSellTax, Message: "sell tax unknown"}
+		}
+		if a.SellTax > p.MaxSellTax {
+			return &PolicyViolation{Rule: RuleMaxSellTax, Message: fmt.Sprintf("sell tax %.4f exceeds %.4f", a.SellTax, p.MaxSellTax)}
+		}
+	}
+	if p.MaxOpenPositions > 0 && !a.HasPosition && a.OpenPositions >= p.MaxOpenPositions {
+		return &PolicyViolation{Rule: RuleMaxOpenPositions, Message: fmt.Sprintf("already holding %d positions", a.OpenPositions)}
+	}
+	if p.MaxTokenExposureBNB > 0 {
+		exposure := a.ExposureBNB.Add(a.AmountBNB)
+		if exposure.GreaterThan(decimal.NewFromFloat(p.MaxTokenExposureBNB)) {
+			return &PolicyViolation{Rule: RuleMaxTokenExposure, Message: "exposure " + exposure.String() + " BNB exceeds cap"}
+		}
+	}
+	return nil
+}
+
+// Bypassed returns the slippage or price impact violation an emergency action
+// was let through despite, or nil.
+func (p SpendingPolicy) Bypassed(a PolicyAction) *PolicyViolation {
+	if !a.Emergency {
+		return nil
+	}
+	a.Emergency = false
+	if v := p.Evaluate(a); v != nil && (v.Rule == RuleMaxSlippage || v.Rule == RuleMaxPriceImpact) {
+		return v
+	}
+	return nil
+}
+
+func (p SpendingPolicy) withinTradingHours(now time.Time) bool {
+	minute := now.UTC().Hour()*60 + now.UTC().Minute()
+	for _, window := range p.TradingHours {
+		start, end, err := parseTradingWindow(window)
+		if err != nil {
+			continue
+		}
+		if start <= end {
+			if minute >= start && minute < end {
+				return true
+			}
+		} else if minute >= start || minute < end {
+			return true
+		}
+	}
+	return false
+}
+
+func parseTradingWindow(window string) (int, int, error) {
+	parts := strings.Split(strings.TrimSpace(window), "-")
+	if len(parts) != 2 {
+		return 0, 0, fmt.Errorf("invalid trading window %q", window)
+	}
+	start, err := parseClockMinutes(parts[0])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid trading window %q", window)
+	}
+	end, err := parseClockMinutes(parts[1])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid trading window %q", window)
+	}
+	return start, end, nil
+}
+
+func parseClockMinutes(value string) (int, error) {
+	parts := strings.Split(strings.TrimSpace(value), ":")
+	if len(parts) != 2 {
+		return 0, fmt.Errorf("invalid clock %q", value)
+	}
+	hour, err := strconv.Atoi(parts[0])
+	if err != nil || hour < 0 || hour > 24 {
+		return 0, fmt.Errorf("invalid clock %q", value)
+	}
+	minute, err := strconv.Atoi(parts[1])
+	if err != nil || minute < 0 || minute > 59 || (hour == 24 && minute != 0) {
+		return 0, fmt.Errorf("invalid clock %q", value)
+	}
+	return hour*60 + minute, nil
+}
+
+func containsAddress(list []string, address string) bool {
+	for _, item := range list {
+		if strings.EqualFold(strings.TrimSpace(item), strings.TrimSpace(address)) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,104 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+func TestSpendingPolicyEvaluate(t *testing.T) {
+	const router = "0xRouter"
+	noon := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	night := time.Date(2026, 10, 1, 23, 30, 0, 0, time.UTC)
+	buy := func(edit func(*PolicyAction)) PolicyAction {
+		a := PolicyAction{Kind: PolicyActionBuy, Target: router, Router: router, TokenAddress: "0xToken", AmountBNB: decimal.RequireFromString("0.1"), Quoted: true, Now: noon}
+		if edit != nil {
+			edit(&a)
+		}
+		return a
+	}
+
+	tests := []struct {
+		name   string
+		policy SpendingPolicy
+		action PolicyAction
+		rule   string
+	}{
+		{name: "empty policy allows", action: buy(nil)},
+		{name: "outside trading hours", policy: SpendingPolicy{TradingHours: []string{"08:00-22:00"}}, action: buy(func(a *PolicyAction) { a.Now = night }), rule: RuleTradingHours},
+		{name: "window wrapping midnight", policy: SpendingPolicy{TradingHours: []string{"22:00-06:00"}}, action: buy(func(a *PolicyAction) { a.Now = night })},
+		{name: "sells ignore trading hours", policy: SpendingPolicy{TradingHours: []string{"08:00-22:00"}}, action: buy(func(a *PolicyAction) { a.Kind = PolicyActionSell; a.Now = night })},
+		{name: "router matches case-insensitively", policy: SpendingPolicy{AllowedRouters: []string{"0xrouter"}}, action: buy(nil)},
+		{name: "unknown router", policy: SpendingPolicy{AllowedRouters: []string{"0xOther"}}, action: buy(nil), rule: RuleAllowedRouters},
+		{name: "unknown approval spender", policy: SpendingPolicy{AllowedContracts: []string{"0xOther"}}, action: buy(func(a *PolicyAction) { a.Kind = PolicyActionApprove }), rule: RuleAllowedContracts},
+		{name: "unquoted swap under an impact cap", policy: SpendingPolicy{MaxPriceImpact: 0.05}, action: buy(func(a *PolicyAction) { a.Quoted = false }), rule: RuleMaxPriceImpact},
+		{name: "unquoted swap under a slippage cap", policy: SpendingPolicy{MaxSlippage: 0.05}, action: buy(func(a *PolicyAction) { a.Quoted = false }), rule: RuleMaxSlippage},
+		{name: "slippage over cap", policy: SpendingPolicy{MaxSlippage: 0.05}, action: buy(func(a *PolicyAction) { a.Slippage = 0.1 }), rule: RuleMaxSlippage},
+		{name: "emergency exit skips slippage and impact caps", policy: SpendingPolicy{MaxSlippage: 0.05, MaxPriceImpact: 0.05}, action: buy(func(a *PolicyAction) {
+			a.Kind = PolicyActionSell
+			a.Emergency = true
+			a.Slippage = 0.3
+			a.PriceImpact = 0.3
+		})},
+		{name: "emergency exit keeps the router allowlist", policy: SpendingPolicy{AllowedRouters: []string{"0xOther"}}, action: buy(func(a *PolicyAction) { a.Kind = PolicyActionSell; a.Emergency = true }), rule: RuleAllowedRouters},
+		{name: "denied token", policy: SpendingPolicy{TokenDenylist: []string{"0xtoken"}}, action: buy(nil), rule: RuleTokenDenylist},
+		{name: "token not allowlisted", policy: SpendingPolicy{TokenAllowlist: []string{"0xOther"}}, action: buy(nil), rule: RuleTokenAllowlist},
+		{name: "unknown sell tax", policy: SpendingPolicy{MaxSellTax: 0.1}, action: buy(nil), rule: RuleMaxSellTax},
+		{name: "sell tax over cap", policy: SpendingPolicy{MaxSellTax: 0.1}, action: buy(func(a *PolicyAction) { a.SellTaxKnown = true; a.SellTax = 0.2 }), rule: RuleMaxSellTax},
+		{name: "too many positions", policy: SpendingPolicy{MaxOpenPositions: 3}, action: buy(func(a *PolicyAction) { a.OpenPositions = 3 }), rule: RuleMaxOpenPositions},
+		{name: "adding to a held position", policy: SpendingPolicy{MaxOpenPositions: 3}, action: buy(func(a *PolicyAction) { a.OpenPositions = 3; a.HasPosition = true })},
+		{name: "exposure over cap", policy: SpendingPolicy{MaxTokenExposureBNB: 0.5}, action: buy(func(a *PolicyAction) { a.ExposureBNB = decimal.RequireFromString("0.45") }), rule: RuleMaxTokenExposure},
+		{name: "sells skip buy limits", policy: SpendingPolicy{TokenDenylist: []string{"0xToken"}, MaxSellTax: 0.1}, action: buy(func(a *PolicyAction) { a.Kind = PolicyActionSell })},
+		{name: "withdrawal over limit", policy: SpendingPolicy{MaxWithdrawalBNB: 1}, action: PolicyAction{Kind: PolicyActionWithdraw, AmountBNB: decimal.NewFromInt(2), Now: noon}, rule: RuleMaxWithdrawal},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := tt.policy.Evaluate(tt.action)
+			switch {
+			case tt.rule == "" && v != nil:
+				t.Errorf("unexpected violation %v", v)
+			case tt.rule != "" && (v == nil || v.Rule != tt.rule):
+				t.Errorf("violation = %v, want rule %s", v, tt.rule)
+			}
+		})
+	}
+}
+
+func TestSpendingPolicyBypassed(t *testing.T) {
+	policy := SpendingPolicy{MaxSlippage: 0.05, MaxPriceImpact: 0.1}
+	exit := PolicyAction{Kind: PolicyActionSell, Quoted: true, Slippage: 0.2, Emergency: true}
+	if v := policy.Bypassed(exit); v == nil || v.Rule != RuleMaxSlippage {
+		t.Errorf("Bypassed() = %v, want rule %s", v, RuleMaxSlippage)
+	}
+	exit.Slippage = 0.01
+	if v := policy.Bypassed(exit); v != nil {
+		t.Errorf("Bypassed() within caps = %v, want nil", v)
+	}
+	exit.Slippage, exit.Emergency = 0.2, false
+	if v := policy.Bypassed(exit); v != nil {
+		t.Errorf("Bypassed() of a regular sell = %v, want nil", v)
+	}
+}
+
+func TestSpendingPolicyValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  SpendingPolicy
+		wantErr bool
+	}{
+		{name: "empty", policy: SpendingPolicy{}},
+		{name: "valid window", policy: SpendingPolicy{TradingHours: []string{"22:00-24:00"}}},
+		{name: "malformed window", policy: SpendingPolicy{TradingHours: []string{"8-22"}}, wantErr: true},
+		{name: "minute out of range", policy: SpendingPolicy{TradingHours: []string{"08:60-22:00"}}, wantErr: true},
+		{name: "ratio above one", policy: SpendingPolicy{MaxSlippage: 1.5}, wantErr: true},
+		{name: "negative limit", policy: SpendingPolicy{MaxWithdrawalBNB: -1}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.policy.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Validate() = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,282 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	StrategyStopLoss     = "auto_stop_loss"
+	StrategyTakeProfit   = "auto_take_profit"
+	StrategyTrailingStop = "auto_trailing_stop"
+	StrategyMaxHold      = "auto_max_hold"
+	StrategyPhaseExit    = "auto_phase_exit"
+)
+
+const (
+	positionMonitorInterval = 30 * time.Second
+	positionExitRetryDelay  = 5 * time.Minute
+)
+
+// PositionMark is a position valued against current pair reserves.
+type PositionMark struct {
+	PriceBNB     decimal.Decimal
+	ValueBNB     decimal.Decimal
+	UnrealizedPL float64
+}
+
+// PositionMonitor marks open AI positions to market and executes configured
+// stop-loss, trailing-stop, holding-time and take-profit exits without waiting
+// for the client.
+type PositionMonitor struct {
+	client     *ethereum.Client
+	repo       *repository.Repository
+	executor   TradeExecutor
+	hub        Broadcaster
+	pricer     *tokenPricer
+	retryAfter map[string]time.Time
+}
+
+func NewPositionMonitor(client *ethereum.Client, repo *repository.Repository, executor TradeExecutor, hub Broadcaster) *PositionMonitor {
+	return &PositionMonitor{
+		client:     client,
+		repo:       repo,
+		executor:   executor,
+		hub:        hub,
+		pricer:     newTokenPricer(client),
+		retryAfter: map[string]time.Time{},
+	}
+}
+
+func (m *PositionMonitor) Start(ctx context.Context) {
+	log.Println("[PositionMonitor] Started")
+	go m.loop(ctx)
+}
+
+func (m *PositionMonitor) loop(ctx context.Context) {
+	ticker := time.NewTicker(positionMonitorInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.checkPositions(ctx)
+		}
+	}
+}
+
+func (m *PositionMonitor) checkPositions(ctx context.Context) {
+	positions, err := m.repo.ListOpenAIPositions(ctx)
+	if err != nil {
+		log.Printf("[PositionMonitor] list positions failed: %v", err)
+		return
+	}
+
+	configs := map[string]AutoTradeConfig{}
+	for i := range positions {
+		pos := &positions[i]
+		mark, err := m.MarkPosition(ctx, pos)
+		if err != nil {
+			log.Printf("[PositionMonitor] mark failed token=%s err=%v", pos.TokenAddress, err)
+			continue
+		}
+		now := time.Now().UTC()
+		updates := map[string]interface{}{
+			"last_price_bnb": mark.PriceBNB,
+			"value_bnb":      mark.ValueBNB,
+			"unrealized_pl":  mark.UnrealizedPL,
+			"marked_at":      now,
+		}
+		if mark.PriceBNB.GreaterThan(pos.PeakPriceBNB) {
+			pos.PeakPriceBNB = mark.PriceBNB
+			pos.PeakAt = &now
+			updates["peak_price_bnb"] = mark.PriceBNB
+			updates["peak_at"] = now
+		}
+		if pos.OpenedAt == nil {
+			// Positions opened before entry tracking start their holding clock now.
+			pos.OpenedAt = &now
+			updates["opened_at"] = now
+		}
+		if err := m.repo.UpdateAIPositionMark(ctx, pos.ID, updates); err != nil {
+			log.Printf("[PositionMonitor] store mark failed token=%s err=%v", pos.TokenAddress, err)
+		}
+
+		cfg, ok := configs[pos.UserID]
+		if !ok {
+			cfg, _ = LoadAutoTradeConfig(ctx, m.repo, pos.UserID)
+			configs[pos.UserID] = cfg
+		}
+		if !cfg.Enabled || now.Before(m.retryAfter[pos.ID]) {
+			continue
+		}
+		m.checkExit(ctx, pos, cfg, mark)
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+// MarkPosition values the position at what selling it all would return now.
+func (m *PositionMonitor) MarkPosition(ctx context.Context, pos *model.AIPosition) (PositionMark, error) {
+	var mark PositionMark
+	price, value, err := m.pricer.Quote(ctx, common.HexToAddress(pos.TokenAddress), pos.Quantity)
+	if err != nil {
+		return mark, err
+	}
+	mark.PriceBNB = price
+	mark.ValueBNB = value
+	if pos.CostBNB.GreaterThan(decimal.Zero) {
+		mark.UnrealizedPL = mark.ValueBNB.Sub(pos.CostBNB).Div(pos.CostBNB).InexactFloat64()
+	}
+	return mark, nil
+}
+
+func (m *PositionMonitor) checkExit(ctx context.Context, pos *model.AIPosition, cfg AutoTradeConfig, mark PositionMark) {
+	pl := mark.UnrealizedPL
+	if cfg.StopLoss < 0 && pl <= cfg.StopLoss {
+		reason := fmt.Sprintf("stop-loss %.2f%% hit at %.2f%%", cfg.StopLoss*100, pl*100)
+		m.exit(ctx, pos, "ALL", StrategyStopLoss, reason, -1)
+		return
+	}
+	if reason, ok := trailingStopHit(pos, cfg, mark); ok {
+		m.exit(ctx, pos, "ALL", StrategyTrailingStop, reason, -1)
+		return
+	}
+	if cfg.MaxHoldMinutes > 0 && pos.OpenedAt != nil {
+		held := time.Since(*pos.OpenedAt)
+		if held >= time.Duration(cfg.MaxHoldMinutes)*time.Minute {
+			reason := fmt.Sprintf("held %s, max %d minutes", held.Round(time.Minute), cfg.MaxHoldMinutes)
+			m.exit(ctx, pos, "ALL", StrategyMaxHold, reason, -1)
+			return
+		}
+	}
+	if len(cfg.ExitPhases) > 0 {
+		if token, err := m.repo.GetTokenByAddress(ctx, pos.TokenAddress); err == nil {
+			phase := token.GoldenDogPhase()
+			for _, exitPhase := range cfg.ExitPhases {
+				if strings.EqualFold(strings.TrimSpace(exitPhase), phase) {
+					m.exit(ctx, pos, "ALL", StrategyPhaseExit, "token entered "+phase+" phase", -1)
+					return
+				}
+			}
+		}
+	}
+
+	level := -1
+	for i, threshold := range cfg.TakeProfitLevels {
+		if pl >= threshold {
+			level = i
+		}
+	}
+	if level < pos.TakeProfitHits {
+		return
+	}
+	ratio := takeProfitRatio(level, cfg.TakeProfitAmounts)
+	amountIn := "ALL"
+	if ratio > 0 && ratio < 1 {
+		amountIn = decimal.NewFromFloat(ratio*100).Round(4).String() + "%"
+	}
+	reason := fmt.Sprintf("take-profit level %d (%.2f%%) hit at %.2f%%", level+1, cfg.TakeProfitLevels[level]*100, pl*100)
+	m.exit(ctx, pos, amountIn, StrategyTakeProfit, reason, level)
+}
+
+// trailingStopHit reports whether price has fallen TrailingStop below the peak,
+// once the peak has cleared TrailingActivation over the average entry price.
+func trailingStopHit(pos *model.AIPosition, cfg AutoTradeConfig, mark PositionMark) (string, bool) {
+	if cfg.TrailingStop <= 0 || pos.PeakPriceBNB.LessThanOrEqual(decimal.Zero) {
+		return "", false
+	}
+	if cfg.TrailingActivation > 0 {
+		if pos.Quantity.LessThanOrEqual(decimal.Zero) {
+			return "", false
+		}
+		entry := pos.CostBNB.Div(pos.Quantity)
+		if entry.LessThanOrEqual(decimal.Zero) {
+			return "", false
+		}
+		if pos.PeakPriceBNB.Div(entry).Sub(decimal.NewFromInt(1)).InexactFloat64() < cfg.TrailingActivation {
+			return "", false
+		}
+	}
+	drawdown := decimal.NewFromInt(1).Sub(mark.PriceBNB.Div(pos.PeakPriceBNB)).InexactFloat64()
+	if drawdown < cfg.TrailingStop {
+		return "", false
+	}
+	return fmt.Sprintf("price %.2f%% below peak %s BNB, trailing %.2f%%", drawdown*100, pos.PeakPriceBNB.String(), cfg.TrailingStop*100), true
+}
+
+// exit sells through the executor. takeProfitLevel is the index of the level
+// being executed, or -1 for non take-profit exits.
+func (m *PositionMonitor) exit(ctx context.Context, pos *model.AIPosition, amountIn, strategy, reason string, takeProfitLevel int) {
+	log.Printf("[PositionMonitor] %s user=%s token=%s amount=%s: %s", strategy, pos.UserID, pos.TokenAddress, amountIn, reason)
+	result, err := m.executor.ExecuteManagedTrade(ctx, ManagedTradeRequest{
+		UserID:       pos.UserID,
+		TokenAddress: pos.TokenAddress,
+		TokenSymbol:  pos.TokenSymbol,
+		Type:         "SELL",
+		AmountIn:     amountIn,
+		Reason:       reason,
+		StrategyUsed: strategy,
+		Force:        true,
+	})
+	if err != nil || result.Status != "success" {
+		if err == nil {
+			err = fmt.Errorf("trade %s status %s", result.TxHash, result.Status)
+		}
+		log.Printf("[PositionMonitor] exit failed user=%s token=%s err=%v", pos.UserID, pos.TokenAddress, err)
+		m.retryAfter[pos.ID] = time.Now().UTC().Add(positionExitRetryDelay)
+		return
+	}
+	delete(m.retryAfter, pos.ID)
+
+	if takeProfitLevel >= 0 {
+		if err := m.repo.UpdateAIPositionMark(ctx, pos.ID, map[string]interface{}{
+			"take_profit_hits": takeProfitLevel + 1,
+		}); err != nil {
+			log.Printf("[PositionMonitor] store take-profit level failed token=%s err=%v", pos.TokenAddress, err)
+		}
+	}
+
+	if m.hub != nil {
+		m.hub.BroadcastToUser(pos.UserID, map[string]interface{}{
+			"type":         "position_exit",
+			"userId":       pos.UserID,
+			"tokenAddress": pos.TokenAddress,
+			"tokenSymbol":  pos.TokenSymbol,
+			"strategy":     strategy,
+			"reason":       reason,
+			"txHash":       result.TxHash,
+			"amountIn":     result.AmountIn,
+			"amountOut":    result.AmountOut,
+		})
+	}
+}
+
+func takeProfitRatio(index int, amounts []float64) float64 {
+	if index < 0 {
+		return 0
+	}
+	if len(amounts) == 0 {
+		return 1
+	}
+	if index < len(amounts) {
+		return amounts[index]
+	}
+	return amounts[len(amounts)-1]
+}
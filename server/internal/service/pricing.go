@@ -0,0 +1,66 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+// tokenPricer prices tokens in BNB from PancakeSwap pair reserves.
+type tokenPricer struct {
+	client   *ethereum.Client
+	mu       sync.Mutex
+	decimals map[string]int32
+}
+
+func newTokenPricer(client *ethereum.Client) *tokenPricer {
+	return &tokenPricer{client: client, decimals: map[string]int32{}}
+}
+
+func (p *tokenPricer) Decimals(ctx context.Context, tokenAddr common.Address) (int32, error) {
+	key := strings.ToLower(tokenAddr.Hex())
+	p.mu.Lock()
+	decimals, ok := p.decimals[key]
+	p.mu.Unlock()
+	if ok {
+		return decimals, nil
+	}
+	_, _, raw, err := p.client.GetTokenInfo(ctx, tokenAddr)
+	if err != nil {
+		return 0, err
+	}
+	p.mu.Lock()
+	p.decimals[key] = int32(raw)
+	p.mu.Unlock()
+	return int32(raw), nil
+}
+
+// Quote returns the spot price of one whole token in BNB and what selling
+// quantity whole tokens would return after the pool fee and price impact.
+func (p *tokenPricer) Quote(ctx context.Context, tokenAddr common.Address, quantity decimal.Decimal) (price, value decimal.Decimal, err error) {
+	decimals, err := p.Decimals(ctx, tokenAddr)
+	if err != nil {
+		return price, value, err
+	}
+	tokenReserve, bnbReserve, err := p.client.TokenReserves(ctx, tokenAddr)
+	if err != nil {
+		return price, value, err
+	}
+	if tokenReserve.Sign() <= 0 || bnbReserve.Sign() <= 0 {
+		return price, value, fmt.Errorf("empty reserves")
+	}
+	price = decimal.NewFromBigInt(bnbReserve, 0).
+		Div(decimal.NewFromBigInt(tokenReserve, 0)).
+		Shift(decimals - 18)
+	if quantity.GreaterThan(decimal.Zero) {
+		quantityWei := quantity.Shift(decimals).BigInt()
+		value = decimal.NewFromBigInt(ethereum.GetAmountOut(quantityWei, tokenReserve, bnbReserve), -18)
+	}
+	return price, value, nil
+}
@@ -0,0 +1,49 @@
+package service
+
+import (
+	"math"
+	"time"
+
+	"easymeme/internal/model"
+)
+
+// analysisPriority ranks a pending token for the agent queue from its
+// liquidity, early buy flow, holder growth, creator reputation and its age at
+// enrichment. Honeypots need no agent and rank last; queue aging still moves
+// them up eventually.
+func analysisPriority(in baselineInputs, age time.Duration, cfg *model.ScoringConfig) float64 {
+	if in.isHoneypot {
+		return 0
+	}
+	p := cfg.Priority
+	th := cfg.Thresholds
+	score := 0.0
+
+	if in.liquidityUSD > 0 {
+		score += p.LiquidityPoints * math.Min(math.Log1p(in.liquidityUSD)/math.Log1p(p.LiquidityUSD), 1)
+	}
+	if txns := in.buysH1 + in.sellsH1; txns > 0 {
+		buyShare := float64(in.buysH1) / float64(txns)
+		activity := math.Min(float64(txns)/float64(max(th.MinTxnsH1, 1)), 1)
+		score += p.BuyFlowPoints * buyShare * activity
+	}
+	if in.holderCount > 0 {
+		perMinute := float64(in.holderCount) / math.Max(age.Minutes(), 1)
+		score += p.HolderGrowthPoints * math.Min(perMinute/p.HoldersPerMinute, 1)
+	}
+
+	creator := p.CreatorPoints
+	switch {
+	case in.creatorHoneypots > 0:
+		creator = 0
+	case in.createdContracts >= th.SerialDeployerHigh:
+		creator *= 0.25
+	case in.createdContracts >= th.SerialDeployerMedium:
+		creator *= 0.6
+	}
+	score += creator
+
+	_, decay := cfg.PhaseAt(age)
+	score += p.FreshnessPoints * decay
+	return math.Round(score*100) / 100
+}
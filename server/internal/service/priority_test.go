@@ -0,0 +1,63 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"easymeme/internal/model"
+)
+
+func TestAnalysisPriority(t *testing.T) {
+	cfg := model.DefaultScoringConfig()
+	// Full liquidity, one-sided buy flow and 5 holders a minute at 10 minutes.
+	strong := baselineInputs{liquidityUSD: 50000, buysH1: 20, holderCount: 50}
+
+	tests := []struct {
+		name string
+		in   baselineInputs
+		age  time.Duration
+		want float64
+	}{
+		{name: "strong early token", in: strong, age: 10 * time.Minute, want: 100},
+		{name: "honeypot ranks last", in: baselineInputs{isHoneypot: true, liquidityUSD: 50000, buysH1: 20}, age: 10 * time.Minute, want: 0},
+		{name: "creator with honeypots", in: baselineInputs{liquidityUSD: 50000, buysH1: 20, holderCount: 50, creatorHoneypots: 1}, age: 10 * time.Minute, want: 85},
+		{name: "serial deployer", in: baselineInputs{liquidityUSD: 50000, buysH1: 20, holderCount: 50, createdContracts: 10}, age: 10 * time.Minute, want: 88.75},
+		{name: "repeat deployer", in: baselineInputs{liquidityUSD: 50000, buysH1: 20, holderCount: 50, createdContracts: 3}, age: 10 * time.Minute, want: 94},
+		{name: "balanced flow at half activity", in: baselineInputs{buysH1: 5, sellsH1: 5}, age: 10 * time.Minute, want: 35},
+		{name: "no market data", in: baselineInputs{}, age: 10 * time.Minute, want: 30},
+		{name: "freshness decays in the peak phase", in: baselineInputs{}, age: 75 * time.Minute, want: 28.5},
+		{name: "expired token", in: baselineInputs{}, age: 10 * time.Hour, want: 21},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := analysisPriority(tt.in, tt.age, cfg); got != tt.want {
+				t.Errorf("priority = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestQueuePriorityAt(t *testing.T) {
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	enriched := created.Add(5 * time.Minute)
+
+	tests := []struct {
+		name     string
+		enriched *time.Time
+		now      time.Time
+		want     float64
+	}{
+		{name: "waits from creation before enrichment", now: created.Add(10 * time.Minute), want: 45},
+		{name: "waits from enrichment", enriched: &enriched, now: created.Add(10 * time.Minute), want: 42.5},
+		{name: "aging is capped", enriched: &enriched, now: created.Add(3 * time.Hour), want: 70},
+		{name: "clock skew adds nothing", enriched: &enriched, now: created, want: 40},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			token := model.Token{AnalysisPriority: 40, CreatedAt: created, EnrichedAt: tt.enriched}
+			if got := token.QueuePriorityAt(tt.now, 0.5, 30); got != tt.want {
+				t.Errorf("queue priority = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
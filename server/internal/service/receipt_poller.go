@@ -0,0 +1,90 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+	"easymeme/pkg/ethereum"
+
+	goethereum "github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	receiptPollInterval = 30 * time.Second
+	receiptPollBatch    = 50
+	// Trades younger than this are still inside executeTrade's own wait.
+	receiptPollGrace = time.Minute
+	// A trade without a receipt this long after it was sent was dropped.
+	pendingTradeTimeout = time.Hour
+)
+
+// TradeSettler books a pending trade once its receipt lands; a nil receipt
+// means the transaction was dropped.
+type TradeSettler interface {
+	SettleTrade(ctx context.Context, trade *model.AITrade, receipt *types.Receipt) error
+}
+
+// ReceiptPoller settles trades that were still pending when the executor
+// stopped waiting for their receipt.
+type ReceiptPoller struct {
+	client  *ethereum.Client
+	repo    *repository.Repository
+	settler TradeSettler
+}
+
+func NewReceiptPoller(client *ethereum.Client, repo *repository.Repository, settler TradeSettler) *ReceiptPoller {
+	return &ReceiptPoller{client: client, repo: repo, settler: settler}
+}
+
+func (p *ReceiptPoller) Start(ctx context.Context) {
+	log.Println("[ReceiptPoller] Started")
+	go p.loop(ctx)
+}
+
+func (p *ReceiptPoller) loop(ctx context.Context) {
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.poll(ctx)
+		}
+	}
+}
+
+func (p *ReceiptPoller) poll(ctx context.Context) {
+	now := time.Now().UTC()
+	trades, err := p.repo.ListPendingAITrades(ctx, now.Add(-receiptPollGrace), receiptPollBatch)
+	if err != nil {
+		log.Printf("[ReceiptPoller] list pending trades failed: %v", err)
+		return
+	}
+	for i := range trades {
+		trade := &trades[i]
+		receipt, err := p.client.Receipt(ctx, common.HexToHash(trade.TxHash))
+		if err != nil {
+			if !errors.Is(err, goethereum.NotFound) {
+				log.Printf("[ReceiptPoller] receipt of trade=%s failed: %v", trade.ID, err)
+				continue
+			}
+			if now.Sub(trade.Timestamp) < pendingTradeTimeout {
+				continue
+			}
+			receipt = nil
+		}
+		if err := p.settler.SettleTrade(ctx, trade, receipt); err != nil {
+			log.Printf("[ReceiptPoller] settle trade=%s failed: %v", trade.ID, err)
+			continue
+		}
+		log.Printf("[ReceiptPoller] trade=%s settled", trade.ID)
+	}
+}
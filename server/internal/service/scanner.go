@@ -4,6 +4,7 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"log"
 	"math/big"
 	"strconv"
@@ -28,11 +29,27 @@ type Scanner struct {
 	goPlus      *GoPlusClient
 	dexScreener *DEXScreenerClient
 	bscScan     *BscScanClient
+	analyzer    *Analyzer
+	goldenDog   GoldenDogNotifier
+	enriched    EnrichmentNotifier
 	stats       *enrichmentStats
 }
 
 type Broadcaster interface {
 	Broadcast(payload interface{})
+	// BroadcastToUser sends payload only to authenticated connections of userID.
+	BroadcastToUser(userID string, payload interface{})
+}
+
+// GoldenDogNotifier is told when a baseline analysis flags a new golden dog.
+type GoldenDogNotifier interface {
+	OnGoldenDog(tokenAddress string)
+}
+
+// EnrichmentNotifier is told when a token finishes enrichment and enters the
+// analysis queue.
+type EnrichmentNotifier interface {
+	OnTokenEnriched(tokenAddress string)
 }
 
 type EnrichmentStatsSnapshot struct {
@@ -123,7 +140,7 @@ func (s *enrichmentStats) snapshot() EnrichmentStatsSnapshot {
 	}
 }
 
-func NewScanner(client *ethereum.Client, repo *repository.Repository, hub Broadcaster, bscScanAPIKey string) *Scanner {
+func NewScanner(client *ethereum.Client, repo *repository.Repository, hub Broadcaster, bscScanAPIKey string, goldenDog GoldenDogNotifier, enriched EnrichmentNotifier) *Scanner {
 	return &Scanner{
 		client:      client,
 		repo:        repo,
@@ -131,6 +148,9 @@ func NewScanner(client *ethereum.Client, repo *repository.Repository, hub Broadc
 		goPlus:      NewGoPlusClient(),
 		dexScreener: NewDEXScreenerClient(),
 		bscScan:     NewBscScanClient(bscScanAPIKey),
+		analyzer:    NewAnalyzer(repo),
+		goldenDog:   goldenDog,
+		enriched:    enriched,
 		stats:       newEnrichmentStats(),
 	}
 }
@@ -412,7 +432,7 @@ func (s *Scanner) enrichTokenOnce(ctx context.Context, tokenAddress, pairAddress
 		"market_data":            marketDataJSON,
 		"holder_data":            holderDataJSON,
 		"creator_history":        creatorHistoryJSON,
-		"analysis_status":        "enriched",
+		"analysis_status":        gorm.Expr("CASE WHEN analysis_source = ? THEN analysis_status ELSE ? END", model.AnalysisSourceAgent, "enriched"),
 		"enrich_error":           "",
 		"enriched_at":            now,
 		"last_market_refresh_at": now,
@@ -427,6 +447,51 @@ func (s *Scanner) enrichTokenOnce(ctx context.Context, tokenAddress, pairAddress
 	}
 
 	log.Printf("[Scanner] Token enriched: %s", tokenAddress)
+
+	if err := s.applyBaseline(ctx, tokenAddress); err != nil {
+		log.Printf("[Scanner] baseline analysis warning for %s: %v", tokenAddress, err)
+	}
+	if s.enriched != nil {
+		s.enriched.OnTokenEnriched(tokenAddress)
+	}
+	return nil
+}
+
+// applyBaseline scores the token with the rule-based analyzer and stores the
+// result unless an agent analysis already exists. A verdict equal to the
+// current one is not stored again. The golden dog listener is only told when
+// the token newly becomes one. The queue priority is refreshed on every run.
+func (s *Scanner) applyBaseline(ctx context.Context, tokenAddress string) error {
+	token, err := s.repo.GetTokenByAddress(ctx, tokenAddress)
+	if err != nil {
+		return err
+	}
+	if token.AnalysisSource == model.AnalysisSourceAgent {
+		return nil
+	}
+
+	analysis, priority := s.analyzer.Evaluate(ctx, token)
+	if priority != token.AnalysisPriority {
+		if err := s.repo.UpdateTokenAnalysis(ctx, tokenAddress, map[string]interface{}{"analysis_priority": priority}); err != nil {
+			log.Printf("[Scanner] analysis priority warning for %s: %v", tokenAddress, err)
+		}
+	}
+	if token.AnalysisStatus == "analyzed" && analysis.sameVerdict(token) {
+		return nil
+	}
+	record, err := analysis.tokenAnalysis(tokenAddress)
+	if err != nil {
+		return err
+	}
+	applied, err := s.repo.RecordTokenAnalysis(ctx, record, analysis.tokenUpdates(record))
+	if err != nil || !applied {
+		return err
+	}
+
+	log.Printf("[Scanner] Baseline analysis: %s risk=%d level=%s goldenDog=%d", tokenAddress, analysis.RiskScore, analysis.RiskLevel, analysis.GoldenDogScore)
+	if analysis.IsGoldenDog && !token.IsGoldenDog && s.goldenDog != nil {
+		s.goldenDog.OnGoldenDog(tokenAddress)
+	}
 	return nil
 }
 
@@ -462,6 +527,16 @@ func (s *Scanner) recoverEnrichmentBatch(ctx context.Context) {
 		}
 	}
 
+	unscored, err := s.repo.GetTokensByStatus(ctx, "enriched", 20)
+	if err != nil {
+		log.Printf("[Scanner] recover list status=enriched err=%v", err)
+	}
+	for _, token := range unscored {
+		if err := s.applyBaseline(ctx, token.Address); err != nil {
+			log.Printf("[Scanner] baseline analysis warning for %s: %v", token.Address, err)
+		}
+	}
+
 	staleCutoff := time.Now().UTC().Add(-10 * time.Minute)
 	stale, err := s.repo.GetStaleEnrichingTokens(ctx, staleCutoff, 20)
 	if err != nil {
@@ -536,6 +611,13 @@ func (s *Scanner) refreshTokenMarketData(ctx context.Context, token model.Token)
 	if err := s.storeMarketSnapshotAndAlert(ctx, token.Address, token.PairAddress, normalized); err != nil {
 		return err
 	}
+
+	// Momentum rules read the market data, so baseline scores follow it.
+	if token.AnalysisSource != model.AnalysisSourceAgent {
+		if err := s.applyBaseline(ctx, token.Address); err != nil {
+			log.Printf("[Scanner] baseline analysis warning for %s: %v", token.Address, err)
+		}
+	}
 	return nil
 }
 
@@ -568,8 +650,9 @@ func (s *Scanner) storeMarketSnapshotAndAlert(ctx context.Context, tokenAddress,
 		return nil
 	}
 
+	threshold := model.ActiveScoring().Thresholds.LiquidityDrop
 	change := (liquidityUSD - prevSnapshots[0].LiquidityUSD) / prevSnapshots[0].LiquidityUSD
-	if change > -0.4 {
+	if change > threshold {
 		return nil
 	}
 
@@ -577,14 +660,15 @@ func (s *Scanner) storeMarketSnapshotAndAlert(ctx context.Context, tokenAddress,
 		"prevLiquidityUsd": prevSnapshots[0].LiquidityUSD,
 		"newLiquidityUsd":  liquidityUSD,
 		"change":           change,
-		"threshold":        -0.4,
+		"threshold":        threshold,
+		"scoringVersion":   model.ActiveScoring().Version,
 	}
 	detailsJSON, _ := json.Marshal(details)
 	if err := s.repo.CreateTokenAlert(ctx, &model.TokenAlert{
 		TokenAddress: tokenAddress,
 		AlertType:    "LIQUIDITY_DROP",
 		Severity:     "HIGH",
-		Message:      "Liquidity dropped more than 40% within refresh window",
+		Message:      fmt.Sprintf("Liquidity dropped more than %.0f%% within refresh window", -threshold*100),
 		Details:      detailsJSON,
 	}); err != nil {
 		return err
@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"easymeme/internal/model"
+	"easymeme/internal/repository"
+	"easymeme/pkg/ethereum"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	portfolioSnapshotInterval = 5 * time.Minute
+	bnbPriceTTL               = time.Minute
+	// markMaxAge is how old a position monitor mark may be before it is requoted.
+	markMaxAge = 2 * time.Minute
+)
+
+// PositionValuation is an AI position marked to market.
+type PositionValuation struct {
+	TokenAddress  string          `json:"token_address"`
+	TokenSymbol   string          `json:"token_symbol"`
+	Quantity      decimal.Decimal `json:"quantity"`
+	CostBNB       decimal.Decimal `json:"cost_bnb"`
+	EntryPriceBNB decimal.Decimal `json:"entry_price_bnb"`
+	PriceBNB      decimal.Decimal `json:"price_bnb"`
+	// ValueBNB is what selling the whole position would return now.
+	ValueBNB        decimal.Decimal `json:"value_bnb"`
+	ValueUSD        decimal.Decimal `json:"value_usd"`
+	UnrealizedPLBNB decimal.Decimal `json:"unrealized_pl_bnb"`
+	UnrealizedPL    float64         `json:"unrealized_pl"` // ratio of cost
+	PriceChange     float64         `json:"price_change"`  // spot price against entry price
+	MarkedAt        time.Time       `json:"marked_at"`
+	Error           string          `json:"error,omitempty"`
+}
+
+// PortfolioValuation sums the wallet's BNB and its marked positions.
+type PortfolioValuation struct {
+	UserID          string              `json:"user_id"`
+	BNBPriceUSD     decimal.Decimal     `json:"bnb_price_usd"`
+	CashBNB         decimal.Decimal     `json:"cash_bnb"`
+	PositionsBNB    decimal.Decimal     `json:"positions_bnb"`
+	TotalBNB        decimal.Decimal     `json:"total_bnb"`
+	TotalUSD        decimal.Decimal     `json:"total_usd"`
+	CostBNB         decimal.Decimal     `json:"cost_bnb"`
+	UnrealizedPLBNB decimal.Decimal     `json:"unrealized_pl_bnb"`
+	UnrealizedPL    float64             `json:"unrealized_pl"`
+	Positions       []PositionValuation `json:"positions"`
+	ValuedAt        time.Time           `json:"valued_at"`
+}
+
+// Valuator marks positions to market from pair reserves, converts them to USD
+// with the WBNB/USDT pair and records periodic portfolio snapshots.
+type Valuator struct {
+	client *ethereum.Client
+	repo   *repository.Repository
+	pricer *tokenPricer
+
+	mu       sync.Mutex
+	bnbUSD   decimal.Decimal
+	bnbUSDAt time.Time
+}
+
+func NewValuator(client *ethereum.Client, repo *repository.Repository) *Valuator {
+	return &Valuator{
+		client: client,
+		repo:   repo,
+		pricer: newTokenPricer(client),
+	}
+}
+
+func (v *Valuator) Start(ctx context.Context) {
+	log.Println("[Valuator] Started")
+	go v.loop(ctx)
+}
+
+func (v *Valuator) loop(ctx context.Context) {
+	ticker := time.NewTicker(portfolioSnapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.snapshotAll(ctx)
+		}
+	}
+}
+
+// BNBPriceUSD returns the WBNB/USDT spot price, cached for a minute.
+func (v *Valuator) BNBPriceUSD(ctx context.Context) (decimal.Decimal, error) {
+	v.mu.Lock()
+	if !v.bnbUSD.IsZero() && time.Since(v.bnbUSDAt) < bnbPriceTTL {
+		price := v.bnbUSD
+		v.mu.Unlock()
+		return price, nil
+	}
+	v.mu.Unlock()
+
+	bnbReserve, usdReserve, err := v.client.PairReserves(ctx, common.HexToAddress(ethereum.WBNB), common.HexToAddress(ethereum.USDT))
+	if err != nil {
+		return decimal.Zero, err
+	}
+	if bnbReserve.Sign() <= 0 || usdReserve.Sign() <= 0 {
+		return decimal.Zero, fmt.Errorf("empty WBNB/USDT reserves")
+	}
+	// Both tokens have 18 decimals.
+	price := decimal.NewFromBigInt(usdReserve, 0).Div(decimal.NewFromBigInt(bnbReserve, 0))
+
+	v.mu.Lock()
+	v.bnbUSD = price
+	v.bnbUSDAt = time.Now()
+	v.mu.Unlock()
+	return price, nil
+}
+
+// ValuePosition marks pos with the position monitor's recent mark when there
+// is one and quotes the pair otherwise.
+func (v *Valuator) ValuePosition(ctx context.Context, pos *model.AIPosition, bnbUSD decimal.Decimal) PositionValuation {
+	val := PositionValuation{
+		TokenAddress: pos.TokenAddress,
+		TokenSymbol:  pos.TokenSymbol,
+		Quantity:     pos.Quantity,
+		CostBNB:      pos.CostBNB,
+	}
+	if pos.Quantity.GreaterThan(decimal.Zero) {
+		val.EntryPriceBNB = pos.CostBNB.Div(pos.Quantity)
+	}
+
+	if pos.MarkedAt != nil && time.Since(*pos.MarkedAt) < markMaxAge && pos.LastPriceBNB.GreaterThan(decimal.Zero) {
+		val.PriceBNB = pos.LastPriceBNB
+		val.ValueBNB = pos.ValueBNB
+		val.MarkedAt = *pos.MarkedAt
+	} else {
+		price, value, err := v.pricer.Quote(ctx, common.HexToAddress(pos.TokenAddress), pos.Quantity)
+		if err != nil {
+			val.Error = err.Error()
+			return val
+		}
+		val.PriceBNB = price
+		val.ValueBNB = value
+		val.MarkedAt = time.Now().UTC()
+	}
+
+	val.ValueUSD = val.ValueBNB.Mul(bnbUSD)
+	val.UnrealizedPLBNB = val.ValueBNB.Sub(pos.CostBNB)
+	if pos.CostBNB.GreaterThan(decimal.Zero) {
+		val.UnrealizedPL = val.UnrealizedPLBNB.Div(pos.CostBNB).InexactFloat64()
+	}
+	if val.EntryPriceBNB.GreaterThan(decimal.Zero) {
+		val.PriceChange = val.PriceBNB.Sub(val.EntryPriceBNB).Div(val.EntryPriceBNB).InexactFloat64()
+	}
+	return val
+}
+
+// ValuePortfolio values the user's open positions and wallet BNB.
+func (v *Valuator) ValuePortfolio(ctx context.Context, userID string) (*PortfolioValuation, error) {
+	positions, err := v.repo.ListAIPositionsByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	bnbUSD, err := v.BNBPriceUSD(ctx)
+	if err != nil {
+		log.Printf("[Valuator] BNB/USD price unavailable: %v", err)
+	}
+
+	portfolio := &PortfolioValuation{
+		UserID:      userID,
+		BNBPriceUSD: bnbUSD,
+		Positions:   []PositionValuation{},
+		ValuedAt:    time.Now().UTC(),
+	}
+	if wallet, err := v.repo.GetManagedWalletByUser(ctx, userID); err == nil {
+		if wallet.IsPaper() {
+			portfolio.CashBNB = wallet.Balance
+		} else if balance, err := v.client.GetBalance(ctx, common.HexToAddress(wallet.Address)); err == nil {
+			portfolio.CashBNB = decimal.NewFromBigInt(balance, -18)
+		}
+	}
+	for i := range positions {
+		pos := &positions[i]
+		if pos.Quantity.LessThanOrEqual(decimal.Zero) {
+			continue
+		}
+		val := v.ValuePosition(ctx, pos, bnbUSD)
+		portfolio.Positions = append(portfolio.Positions, val)
+		portfolio.CostBNB = portfolio.CostBNB.Add(pos.CostBNB)
+		if val.Error == "" {
+			portfolio.PositionsBNB = portfolio.PositionsBNB.Add(val.ValueBNB)
+			portfolio.UnrealizedPLBNB = portfolio.UnrealizedPLBNB.Add(val.UnrealizedPLBNB)
+		}
+	}
+	portfolio.TotalBNB = portfolio.CashBNB.Add(portfolio.PositionsBNB)
+	portfolio.TotalUSD = portfolio.TotalBNB.Mul(bnbUSD)
+	if portfolio.CostBNB.GreaterThan(decimal.Zero) {
+		portfolio.UnrealizedPL = portfolio.UnrealizedPLBNB.Div(portfolio.CostBNB).InexactFloat64()
+	}
+	return portfolio, nil
+}
+
+func (v *Valuator) snapshotAll(ctx context.Context) {
+	wallets, err := v.repo.ListManagedWallets(ctx)
+	if err != nil {
+		log.Printf("[Valuator] list wallets failed: %v", err)
+		return
+	}
+	for _, wallet := range wallets {
+		if err := v.snapshot(ctx, wallet.UserID); err != nil {
+			log.Printf("[Valuator] snapshot user=%s failed: %v", wallet.UserID, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		default:
+		}
+	}
+}
+
+func (v *Valuator) snapshot(ctx context.Context, userID string) error {
+	portfolio, err := v.ValuePortfolio(ctx, userID)
+	if err != nil {
+		return err
+	}
+	details, _ := json.Marshal(portfolio.Positions)
+	snapshot := &model.PortfolioSnapshot{
+		UserID:          userID,
+		BNBPriceUSD:     portfolio.BNBPriceUSD,
+		CashBNB:         portfolio.CashBNB,
+		PositionsBNB:    portfolio.PositionsBNB,
+		TotalBNB:        portfolio.TotalBNB,
+		TotalUSD:        portfolio.TotalUSD,
+		CostBNB:         portfolio.CostBNB,
+		UnrealizedPLBNB: portfolio.UnrealizedPLBNB,
+		OpenPositions:   len(portfolio.Positions),
+		Positions:       details,
+	}
+	if err := v.repo.CreatePortfolioSnapshot(ctx, snapshot); err != nil {
+		return err
+	}
+	for _, val := range portfolio.Positions {
+		if val.Error != "" {
+			continue
+		}
+		if err := v.repo.UpdateAITradesCurrentValue(ctx, userID, val.TokenAddress, val.PriceBNB); err != nil {
+			log.Printf("[Valuator] update trade values token=%s failed: %v", val.TokenAddress, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	webhookSignatureHeader = "X-EasyMeme-Signature"
+	webhookTimestampHeader = "X-EasyMeme-Timestamp"
+)
+
+type WebhookClient struct {
+	httpClient *http.Client
+}
+
+func NewWebhookClient() *WebhookClient {
+	return &WebhookClient{
+		httpClient: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Post sends payload as JSON. When secret is set, "<timestamp>.<body>" is
+// signed with HMAC-SHA256: the unix timestamp goes in X-EasyMeme-Timestamp
+// and the hex digest in X-EasyMeme-Signature, so receivers can reject stale
+// replays.
+func (c *WebhookClient) Post(ctx context.Context, endpoint, secret string, payload interface{}) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("encode webhook payload: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("build webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if secret != "" {
+		timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(webhookTimestampHeader, timestamp)
+		req.Header.Set(webhookSignatureHeader, webhookSignature(secret, timestamp, body))
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 2048))
+		return fmt.Errorf("webhook status %d: %s", resp.StatusCode, string(msg))
+	}
+	return nil
+}
+
+func webhookSignature(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
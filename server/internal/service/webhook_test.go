@@ -0,0 +1,37 @@
+package service
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestWebhookPostSignsTimestampAndBody(t *testing.T) {
+	var timestamp, signature string
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		timestamp = r.Header.Get(webhookTimestampHeader)
+		signature = r.Header.Get(webhookSignatureHeader)
+		body, _ = io.ReadAll(r.Body)
+	}))
+	defer srv.Close()
+
+	if err := NewWebhookClient().Post(context.Background(), srv.URL, "0123456789abcdef", map[string]string{"deliveryId": "d1"}); err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	sent, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || time.Since(time.Unix(sent, 0)) > time.Minute {
+		t.Fatalf("timestamp = %q", timestamp)
+	}
+	if want := webhookSignature("0123456789abcdef", timestamp, body); signature != want {
+		t.Errorf("signature = %s, want %s", signature, want)
+	}
+	// A replay with another timestamp no longer verifies.
+	if webhookSignature("0123456789abcdef", strconv.FormatInt(sent-600, 10), body) == signature {
+		t.Error("signature does not cover the timestamp")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"crypto/ecdsa"
 	"math/big"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/ethereum/go-ethereum"
@@ -19,13 +20,50 @@ const (
 	PancakeFactoryV2 = "0xcA143Ce32Fe78f1f7019d7d551a6402fC5350c73"
 	PancakeRouterV2  = "0x10ED43C718714eb63d5aA57B78B54704E256024E"
 	WBNB             = "0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"
+	USDT             = "0x55d398326f99059fF775485246999027B3197955" // BSC-USD, 18 decimals
 )
 
 var PairCreatedTopic = common.HexToHash("0x0d3648bd0f6ba80134a33ba9275ac585d9d315f0ad8355cddefde31afa28d0e9")
 
+// WithdrawalTopic is WBNB's Withdrawal(address,uint256) event, emitted when the
+// router unwraps the BNB paid out by a sell.
+var WithdrawalTopic = common.HexToHash("0x7fcf532c15f0a6db0bd6d0e038bea71d30d808c7d98cb3bf7268a95bf5081b65")
+
+// TransferTopic is the ERC-20 Transfer(address,address,uint256) event.
+var TransferTopic = common.HexToHash("0xddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
 type Client struct {
 	http *ethclient.Client
 	ws   *ethclient.Client
+
+	// nonceMu guards nonces; each sender's own lock serializes its sends so
+	// parallel trades from one wallet never reuse a nonce while other wallets
+	// send freely.
+	nonceMu sync.Mutex
+	nonces  map[common.Address]*senderNonce
+}
+
+// nonceCacheTTL is how long a sent nonce overrides the node's pending nonce.
+// Past it a node still behind has dropped the transaction, and the gap must
+// be refilled.
+const nonceCacheTTL = time.Minute
+
+// senderNonce is the next nonce a sender will use after its last send.
+type senderNonce struct {
+	mu     sync.Mutex
+	next   uint64
+	sentAt time.Time
+}
+
+func (c *Client) senderNonce(from common.Address) *senderNonce {
+	c.nonceMu.Lock()
+	defer c.nonceMu.Unlock()
+	n, ok := c.nonces[from]
+	if !ok {
+		n = &senderNonce{}
+		c.nonces[from] = n
+	}
+	return n
 }
 
 func NewClient(httpURL, wsURL string) (*Client, error) {
@@ -43,8 +81,9 @@ func NewClient(httpURL, wsURL string) (*Client, error) {
 	}
 
 	return &Client{
-		http: httpClient,
-		ws:   wsClient,
+		http:   httpClient,
+		ws:     wsClient,
+		nonces: map[common.Address]*senderNonce{},
 	}, nil
 }
 
@@ -127,7 +166,92 @@ func (c *Client) GetPairReserves(ctx context.Context, pairAddr common.Address) (
 	return reserve0, reserve1, nil
 }
 
+func (c *Client) GetPairForToken(ctx context.Context, tokenAddr common.Address) (common.Address, error) {
+	return c.GetPair(ctx, tokenAddr, common.HexToAddress(WBNB))
+}
+
+// GetPair returns the PancakeSwap V2 pair of tokenA and tokenB.
+func (c *Client) GetPair(ctx context.Context, tokenA, tokenB common.Address) (common.Address, error) {
+	factoryABI := `[{"constant":true,"inputs":[{"name":"tokenA","type":"address"},{"name":"tokenB","type":"address"}],"name":"getPair","outputs":[{"name":"pair","type":"address"}],"stateMutability":"view","type":"function"}]`
+	parsed, err := abi.JSON(strings.NewReader(factoryABI))
+	if err != nil {
+		return common.Address{}, err
+	}
+	data, err := parsed.Pack("getPair", tokenA, tokenB)
+	if err != nil {
+		return common.Address{}, err
+	}
+	factory := common.HexToAddress(PancakeFactoryV2)
+	res, err := c.http.CallContract(ctx, ethereum.CallMsg{
+		To:   &factory,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return common.Address{}, err
+	}
+	if len(res) < 32 {
+		return common.Address{}, ethereum.NotFound
+	}
+	pair := common.BytesToAddress(res[12:32])
+	if pair == (common.Address{}) {
+		return common.Address{}, ethereum.NotFound
+	}
+	return pair, nil
+}
+
+// TokenReserves returns the token and WBNB reserves of the PancakeSwap V2 pair for tokenAddr.
+func (c *Client) TokenReserves(ctx context.Context, tokenAddr common.Address) (tokenReserve, bnbReserve *big.Int, err error) {
+	return c.PairReserves(ctx, tokenAddr, common.HexToAddress(WBNB))
+}
+
+// PairReserves returns the reserves of the PancakeSwap V2 pair of tokenAddr and
+// quoteAddr, ordered as (tokenAddr, quoteAddr).
+func (c *Client) PairReserves(ctx context.Context, tokenAddr, quoteAddr common.Address) (tokenReserve, quoteReserve *big.Int, err error) {
+	pair, err := c.GetPair(ctx, tokenAddr, quoteAddr)
+	if err != nil {
+		return nil, nil, err
+	}
+	reserve0, reserve1, err := c.GetPairReserves(ctx, pair)
+	if err != nil {
+		return nil, nil, err
+	}
+	if reserve0 == nil || reserve1 == nil {
+		return nil, nil, ethereum.NotFound
+	}
+	token0Data, err := c.http.CallContract(ctx, ethereum.CallMsg{
+		To:   &pair,
+		Data: common.Hex2Bytes("0dfe1681"),
+	}, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(token0Data) < 32 {
+		return nil, nil, ethereum.NotFound
+	}
+	if common.BytesToAddress(token0Data[12:32]) == tokenAddr {
+		return reserve0, reserve1, nil
+	}
+	return reserve1, reserve0, nil
+}
+
+// GetAmountOut mirrors PancakeSwap V2 pricing with its 0.25% swap fee.
+func GetAmountOut(amountIn, reserveIn, reserveOut *big.Int) *big.Int {
+	if amountIn == nil || reserveIn == nil || reserveOut == nil || amountIn.Sign() <= 0 || reserveIn.Sign() <= 0 || reserveOut.Sign() <= 0 {
+		return big.NewInt(0)
+	}
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(9975))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Add(new(big.Int).Mul(reserveIn, big.NewInt(10000)), amountInWithFee)
+	return numerator.Div(numerator, denominator)
+}
+
 func (c *Client) SimulateSell(ctx context.Context, tokenAddr common.Address, amount *big.Int) error {
+	return c.SimulateSellFrom(ctx, common.Address{}, tokenAddr, amount, big.NewInt(0))
+}
+
+// SimulateSellFrom dry-runs a sell as from. Reverts caused only by a missing
+// allowance or balance are not reported, since the real sell approves first.
+func (c *Client) SimulateSellFrom(ctx context.Context, from, tokenAddr common.Address, amount, amountOutMin *big.Int) error {
 	router := common.HexToAddress(PancakeRouterV2)
 	wbnb := common.HexToAddress(WBNB)
 
@@ -139,11 +263,14 @@ func (c *Client) SimulateSell(ctx context.Context, tokenAddr common.Address, amo
 
 	deadline := big.NewInt(time.Now().Add(2 * time.Minute).Unix())
 	to := common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+	if from != (common.Address{}) {
+		to = from
+	}
 
 	data, err := parsed.Pack(
 		"swapExactTokensForETHSupportingFeeOnTransferTokens",
 		amount,
-		big.NewInt(0),
+		amountOutMin,
 		[]common.Address{tokenAddr, wbnb},
 		to,
 		deadline,
@@ -153,6 +280,7 @@ func (c *Client) SimulateSell(ctx context.Context, tokenAddr common.Address, amo
 	}
 
 	_, err = c.http.CallContract(ctx, ethereum.CallMsg{
+		From: from,
 		To:   &router,
 		Data: data,
 	}, nil)
@@ -181,6 +309,10 @@ func (c *Client) GetBalance(ctx context.Context, addr common.Address) (*big.Int,
 	return c.http.BalanceAt(ctx, addr, nil)
 }
 
+func (c *Client) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return c.http.SuggestGasPrice(ctx)
+}
+
 func (c *Client) Receipt(ctx context.Context, hash common.Hash) (*types.Receipt, error) {
 	return c.http.TransactionReceipt(ctx, hash)
 }
@@ -205,6 +337,26 @@ func (c *Client) TokenBalance(ctx context.Context, tokenAddr, owner common.Addre
 	return new(big.Int).SetBytes(res), nil
 }
 
+func (c *Client) Allowance(ctx context.Context, tokenAddr, owner, spender common.Address) (*big.Int, error) {
+	erc20ABI := `[{"constant":true,"inputs":[{"name":"owner","type":"address"},{"name":"spender","type":"address"}],"name":"allowance","outputs":[{"name":"","type":"uint256"}],"stateMutability":"view","type":"function"}]`
+	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return nil, err
+	}
+	data, err := parsed.Pack("allowance", owner, spender)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.http.CallContract(ctx, ethereum.CallMsg{
+		To:   &tokenAddr,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(res), nil
+}
+
 func (c *Client) ApproveToken(ctx context.Context, pk *ecdsa.PrivateKey, tokenAddr, spender common.Address, amount *big.Int) (common.Hash, error) {
 	erc20ABI := `[{"constant":false,"inputs":[{"name":"spender","type":"address"},{"name":"value","type":"uint256"}],"name":"approve","outputs":[{"name":"","type":"bool"}],"stateMutability":"nonpayable","type":"function"}]`
 	parsed, err := abi.JSON(strings.NewReader(erc20ABI))
@@ -265,12 +417,23 @@ func (c *Client) SwapExactTokensForETH(ctx context.Context, pk *ecdsa.PrivateKey
 	return c.sendTx(ctx, pk, router, big.NewInt(0), data)
 }
 
+func (c *Client) TransferBNB(ctx context.Context, pk *ecdsa.PrivateKey, to common.Address, amount *big.Int) (common.Hash, error) {
+	return c.sendTx(ctx, pk, to, amount, nil)
+}
+
 func (c *Client) sendTx(ctx context.Context, pk *ecdsa.PrivateKey, to common.Address, value *big.Int, data []byte) (common.Hash, error) {
 	from := cryptoPubkeyAddress(pk)
+	sender := c.senderNonce(from)
+	sender.mu.Lock()
+	defer sender.mu.Unlock()
 	nonce, err := c.http.PendingNonceAt(ctx, from)
 	if err != nil {
 		return common.Hash{}, err
 	}
+	// The node may not report a transaction we just sent as pending yet.
+	if sender.next > nonce && time.Since(sender.sentAt) < nonceCacheTTL {
+		nonce = sender.next
+	}
 	gasPrice, err := c.http.SuggestGasPrice(ctx)
 	if err != nil {
 		return common.Hash{}, err
@@ -295,11 +458,61 @@ func (c *Client) sendTx(ctx context.Context, pk *ecdsa.PrivateKey, to common.Add
 		return common.Hash{}, err
 	}
 	if err := c.http.SendTransaction(ctx, signed); err != nil {
+		// The cached nonce may be what the node rejected; the next send
+		// starts again from the node's pending nonce.
+		sender.next = 0
 		return common.Hash{}, err
 	}
+	sender.next, sender.sentAt = nonce+1, time.Now()
 	return signed.Hash(), nil
 }
 
+// SellProceeds returns the BNB the router unwrapped for a sell receipt, or nil
+// when the receipt has no WBNB withdrawal from the router.
+func SellProceeds(receipt *types.Receipt) *big.Int {
+	if receipt == nil {
+		return nil
+	}
+	wbnb := common.HexToAddress(WBNB)
+	router := common.HexToAddress(PancakeRouterV2)
+	var total *big.Int
+	for _, lg := range receipt.Logs {
+		if lg.Address != wbnb || len(lg.Topics) < 2 || lg.Topics[0] != WithdrawalTopic || len(lg.Data) < 32 {
+			continue
+		}
+		if common.BytesToAddress(lg.Topics[1].Bytes()) != router {
+			continue
+		}
+		if total == nil {
+			total = new(big.Int)
+		}
+		total.Add(total, new(big.Int).SetBytes(lg.Data[:32]))
+	}
+	return total
+}
+
+// TokensReceived returns the amount of token transferred to `to` in a receipt,
+// or nil when the receipt logs no such transfer.
+func TokensReceived(receipt *types.Receipt, token, to common.Address) *big.Int {
+	if receipt == nil {
+		return nil
+	}
+	var total *big.Int
+	for _, lg := range receipt.Logs {
+		if lg.Address != token || len(lg.Topics) < 3 || lg.Topics[0] != TransferTopic || len(lg.Data) < 32 {
+			continue
+		}
+		if common.BytesToAddress(lg.Topics[2].Bytes()) != to {
+			continue
+		}
+		if total == nil {
+			total = new(big.Int)
+		}
+		total.Add(total, new(big.Int).SetBytes(lg.Data[:32]))
+	}
+	return total
+}
+
 func cryptoPubkeyAddress(pk *ecdsa.PrivateKey) common.Address {
 	return crypto.PubkeyToAddress(pk.PublicKey)
 }
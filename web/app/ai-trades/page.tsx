@@ -196,16 +196,17 @@ export default async function AITradesPage({ searchParams }: AITradesPageProps)
                   </h2>
                   <p className="text-xs text-white/60 mt-1">
                     {trade.type} • {formatDate(trade.timestamp)}
+                    {trade.simulated ? ' • PAPER' : ''}
                   </p>
                 </div>
                 <div className="text-right">
                   <p className="text-xs text-white/50">{t(lang, 'trades_pl_label')}</p>
                   <p
                     className={`text-lg font-semibold ${
-                      trade.profit_loss >= 0 ? 'text-[#7cf2a4]' : 'text-[#f07d7d]'
+                      Number(trade.profit_loss) >= 0 ? 'text-[#7cf2a4]' : 'text-[#f07d7d]'
                     }`}
                   >
-                    {formatPL(trade.profit_loss)}
+                    {formatPL(Number(trade.profit_loss))}
                   </p>
                   <p className="text-xs text-white/50 mt-1">
                     {t(lang, 'trades_status_label')}: {trade.status || 'N/A'}
@@ -42,6 +42,8 @@ export type TokenDetail = {
   creatorAddress: string;
   createdAt: string;
   analyzedAt?: string | null;
+  analysisSource?: '' | 'baseline' | 'agent';
+  scoringVersion?: number;
   riskScore: number;
   riskLevel: 'pending' | 'safe' | 'warning' | 'danger';
   isGoldenDog: boolean;
@@ -63,23 +65,30 @@ export type AITrade = {
   token_address: string;
   token_symbol: string;
   type: 'BUY' | 'SELL';
+  // Decimal strings in display units; the *_wei fields are raw base units.
   amount_in: string;
   amount_out: string;
+  amount_in_wei: string;
+  amount_out_wei: string;
+  token_decimals: number;
   tx_hash: string;
   timestamp: string;
   status: string;
-  gas_used: string;
+  gas_used: number;
   block_number: number;
   error_message: string;
   golden_dog_score: number;
   decision_reason: string;
   strategy_used: string;
   current_value: string;
-  profit_loss: number;
+  profit_loss: string;
+  // Paper trades were filled against a quote and never broadcast.
+  simulated: boolean;
   user_id: string;
 };
 
 export type AITradeStats = {
+  mode: string;
   count: number;
   winRate: number;
   avgPL: number;